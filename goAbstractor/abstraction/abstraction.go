@@ -0,0 +1,272 @@
+// Package abstraction reads and abstracts a Go project so that the
+// abstractor can be embedded into other Go tools and services.
+//
+// Any failure while reading or abstracting the project is returned
+// as an *Error instead of panicking.
+package abstraction
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstracted"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/resolver/dce"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/cache"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/logger"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/metadata"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/progress"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/reader"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/stats"
+)
+
+// Project is the abstraction of a Go project.
+// Use WriteJSON to write the project as JSON.
+type Project = abstracted.Project
+
+// Diagnostic is a failure that was recovered from so that
+// the abstraction could continue without the failed part.
+type Diagnostic = abstracted.Diagnostic
+
+// Progress is a report of how far along the abstraction is.
+type Progress = progress.Progress
+
+// Stats is the timing and memory statistics of an abstraction.
+type Stats = stats.Stats
+
+// Config is the configuration for reading and abstracting a project.
+type Config struct {
+	// Dir is the path to the directory of the project or package to read.
+	// The directory should have a go.mod file, or a go.work file to read
+	// every module in the workspace.
+	Dir string
+
+	// Patterns are the optional patterns to load the packages with.
+	Patterns []string
+
+	// Modules are the optional paths to additional module directories
+	// to read and abstract along with Dir. The modules are read together
+	// with Dir as one workspace.
+	Modules []string
+
+	// BuildFlags are the optional build flags to read with.
+	BuildFlags []string
+
+	// Builds are the optional build configurations to read and abstract
+	// together, e.g. `linux/amd64` or `windows/amd64:netgo+osusergo`.
+	// If empty, the current platform is read.
+	Builds []string
+
+	// Tests indicates that test files and external test packages
+	// should be read and abstracted.
+	Tests bool
+
+	// SkipBroken indicates that packages with errors, and packages
+	// depending on them, should be skipped and recorded in the project's
+	// diagnostics instead of failing the abstraction.
+	SkipBroken bool
+
+	// SkipDead indicates that the indices should skip the constructs
+	// found to be dead, so that they can be left out of the output.
+	SkipDead bool
+
+	// Tolerant indicates that failures in declarations should be recorded
+	// in the project's diagnostics instead of failing the abstraction.
+	Tolerant bool
+
+	// Workers is the number of packages to analyze and abstract concurrently.
+	// The project is the same as when the packages are abstracted serially.
+	// Zero or one will analyze and abstract the packages serially.
+	Workers int
+
+	// CacheDir is the optional directory to cache the constructs
+	// abstracted from each package in.
+	CacheDir string
+
+	// ExportedRoots are the paths of the packages whose exported API
+	// should be kept alive by the dead-code elimination.
+	ExportedRoots []string
+
+	// RootSymbols are the qualified names of the declarations that should
+	// be kept alive by the dead-code elimination, e.g. `example.com/foo.Bar`
+	// or `example.com/foo.Bar.Method`.
+	RootSymbols []string
+
+	// ReflectionRoots indicates that the declarations used in
+	// reflection-based registrations should be kept alive
+	// by the dead-code elimination.
+	ReflectionRoots bool
+
+	// Verbose indicates that the abstraction process should
+	// output additional status information to the standard out.
+	// Giving a LogLevel other than info, or any LogGroups,
+	// also outputs the status information.
+	Verbose bool
+
+	// LogHandler is the optional structured log handler to write the
+	// status information to, e.g. `slog.NewJSONHandler(w, nil)`.
+	// Each record has the group and indent of the log as attributes.
+	// If set, the status information is not written to the standard out.
+	LogHandler slog.Handler
+
+	// LogLevel is the minimum level of the status information to write.
+	// The default is info, debug will include a trace of each declaration.
+	// The LogHandler is given every level so that it doesn't need to filter
+	// the levels itself, e.g. the shown groups are written at debug.
+	LogLevel slog.Level
+
+	// LogGroups are the groups, e.g. `resolver` or `instantiator`, of the
+	// detailed status information to also write. The group "*" will write
+	// every group. Only the ungrouped status information is written
+	// if no groups are given. The shown groups are written at every level,
+	// including debug, regardless of the LogLevel.
+	LogGroups []string
+
+	// Progress is the optional callback that is called at the start of
+	// each phase, after each package, and for each iteration of the
+	// resolver. It is called from the goroutine that called Abstract.
+	Progress func(p Progress)
+
+	// Stats is the optional callback that is called with the timing and
+	// memory statistics of each phase once the abstraction has finished.
+	// It is called even if the abstraction failed. Recording the statistics
+	// adds a small overhead to the abstraction.
+	Stats func(s *Stats)
+}
+
+// Abstract reads and abstracts the project described by the given config.
+// Returns an *Error if the project could not be read or abstracted.
+//
+// The context is checked throughout every phase of the abstraction.
+// If the context is cancelled, the returned error wraps the context's error.
+func Abstract(ctx context.Context, cfg Config) (proj *Project, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			proj, err = nil, fromPanic(r)
+		}
+	}()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	handle := cfg.Progress
+	if cfg.Stats != nil {
+		rec := stats.NewRecorder()
+		defer func() {
+			var finished constructs.Project
+			if proj != nil {
+				finished = abstracted.Constructs(proj)
+			}
+			cfg.Stats(rec.Finish(finished))
+		}()
+		handle = func(p Progress) {
+			rec.Observe(p)
+			if cfg.Progress != nil {
+				cfg.Progress(p)
+			}
+		}
+	}
+
+	rc := &reader.Config{
+		Verbose:    cfg.Verbose,
+		Dir:        cfg.Dir,
+		Patterns:   cfg.Patterns,
+		Modules:    cfg.Modules,
+		Context:    ctx,
+		BuildFlags: cfg.BuildFlags,
+		SkipBroken: cfg.SkipBroken,
+		Tests:      cfg.Tests,
+	}
+	for _, spec := range cfg.Builds {
+		bc, err := reader.ParseBuildConfig(spec)
+		if err != nil {
+			return nil, newError(PhaseLoad, err)
+		}
+		rc.BuildConfigs = append(rc.BuildConfigs, bc)
+	}
+
+	prog := progress.New(handle)
+	prog.Phase(PhaseLoad)
+	var (
+		ps      []*packages.Package
+		builds  map[string][]*packages.Package
+		skipped []diagnostics.Diagnostic
+	)
+	if len(rc.BuildConfigs) > 0 {
+		builds, skipped, err = reader.ReadBuilds(rc)
+	} else {
+		ps, skipped, err = reader.Read(rc)
+	}
+	// The loader doesn't always wrap the context's error,
+	// so check for a cancellation before any other load error.
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, newError(PhaseLoad, ctxErr)
+	}
+	if err != nil {
+		return nil, newError(PhaseLoad, err)
+	}
+
+	all := slices.Clone(ps)
+	for _, name := range slices.Sorted(maps.Keys(builds)) {
+		all = append(all, builds[name]...)
+	}
+	goEnv, err := reader.GoEnv(rc)
+	if err != nil {
+		return nil, newError(PhaseLoad, err)
+	}
+	meta := metadata.New(ctx, goEnv, all)
+	meta.Patterns = cfg.Patterns
+	meta.BuildFlags = cfg.BuildFlags
+	meta.Builds = cfg.Builds
+	meta.Tests = cfg.Tests
+
+	var c *cache.Cache
+	if len(cfg.CacheDir) > 0 {
+		if c, err = cache.Open(cfg.CacheDir, rc, ps, builds); err != nil {
+			return nil, newError(PhaseLoad, err)
+		}
+	}
+
+	var log *logger.Logger
+	switch {
+	case cfg.LogHandler != nil:
+		log = logger.NewSlog(cfg.LogHandler)
+	case cfg.Verbose, cfg.LogLevel != logger.LevelInfo, len(cfg.LogGroups) > 0:
+		log = logger.New()
+	}
+	log = log.SetLevel(cfg.LogLevel).Show(cfg.LogGroups...)
+
+	var symbols map[string]string
+	if len(cfg.RootSymbols) > 0 {
+		symbols = map[string]string{}
+		for _, symbol := range cfg.RootSymbols {
+			symbols[symbol] = dce.ReasonSymbolList
+		}
+	}
+
+	constructed := abstractor.Abstract(abstractor.Config{
+		Packages:    ps,
+		Builds:      builds,
+		Log:         log,
+		SkipDead:    cfg.SkipDead,
+		Workers:     cfg.Workers,
+		Cache:       c,
+		Tolerant:    cfg.Tolerant,
+		Diagnostics: skipped,
+		Roots: dce.Roots{
+			Exported: cfg.ExportedRoots,
+			Symbols:  symbols,
+		},
+		ReflectionRoots: cfg.ReflectionRoots,
+		Context:         ctx,
+		Progress:        prog,
+		Metadata:        meta,
+	})
+	return abstracted.New(constructed), nil
+}
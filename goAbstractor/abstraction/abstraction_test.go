@@ -0,0 +1,406 @@
+package abstraction
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go/token"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstracted"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+)
+
+func Test_Abstract(t *testing.T) {
+	proj, err := Abstract(context.Background(), Config{
+		Dir:      `../../testData/go/test0001`,
+		Patterns: []string{`main.go`},
+	})
+	check.NoError(t).Require(err)
+	check.NotEmpty(t).Name(`packages`).Assert(proj.Packages())
+}
+
+func Test_Abstract_LoadError(t *testing.T) {
+	proj, err := Abstract(context.Background(), Config{
+		Builds: []string{`not-a-build`},
+	})
+	check.Nil(t).Name(`project`).Assert(proj)
+
+	var e *Error
+	check.True(t).Name(`is Error`).Assert(errors.As(err, &e))
+	check.Equal(t, PhaseLoad).Name(`phase`).Assert(e.Phase)
+}
+
+func Test_Abstract_Panic(t *testing.T) {
+	pos := token.Position{Filename: `cats.go`, Line: 12, Column: 3}
+	e := fromPanic(func() (r any) {
+		defer func() { r = recover() }()
+		diagnostics.NewSet().Tolerate(PhaseAbstract, `Cat`, nil, func() {
+			panic(terror.New(`bad cat`).With(`pos`, pos))
+		})
+		return nil
+	}())
+	check.Equal(t, PhaseAbstract).Name(`phase`).Assert(e.Phase)
+	check.Equal(t, `Cat`).Name(`construct`).Assert(e.Construct)
+	check.Equal(t, pos).Name(`pos`).Assert(e.Pos)
+	check.Equal(t, `abstraction failed in abstract for "Cat" at cats.go:12:3: bad cat {pos: cats.go:12:3}`).
+		Name(`message`).Assert(e.Error())
+}
+
+func Test_Abstract_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Abstract(ctx, Config{
+		Dir:      `../../testData/go/test0001`,
+		Patterns: []string{`main.go`},
+	})
+	check.True(t).Name(`is cancelled`).Assert(errors.Is(err, context.Canceled))
+}
+
+func Test_Abstract_CancelledMidRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		cancel  string
+		workers int
+		phase   Phase
+	}{
+		{name: `analyze`, cancel: `analyze 0/1 packages`, workers: 2},
+		{name: `abstract`, cancel: `abstract 0/1 packages`, phase: PhaseAbstract},
+		{name: `references`, cancel: `references (iteration 1)`, phase: PhaseReferences},
+		{name: `instantiations`, cancel: `instantiations (iteration 2)`, phase: PhaseInstantiations},
+		{name: `duplicates`, cancel: `duplicates`, phase: PhaseDuplicates},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			reported := []string{}
+			_, err := Abstract(ctx, Config{
+				Dir:      `../../testData/go/test0013`,
+				Patterns: []string{`main.go`},
+				Workers:  test.workers,
+				Progress: func(p Progress) {
+					reported = append(reported, p.String())
+					if p.String() == test.cancel {
+						cancel()
+					}
+				},
+			})
+			check.True(t).Name(`is cancelled`).Assert(errors.Is(err, context.Canceled))
+			check.False(t).Name(`reported deadCode`).Assert(slices.Contains(reported, `deadCode`))
+
+			// The single package may have finished being analyzed before the
+			// cancellation so the phase it is caught in isn't checked for analyze.
+			if len(test.phase) > 0 {
+				var e *Error
+				check.True(t).Name(`is Error`).Assert(errors.As(err, &e))
+				check.Equal(t, test.phase).Name(`phase`).Assert(e.Phase)
+			}
+		})
+	}
+}
+
+func Test_Abstract_Progress(t *testing.T) {
+	reported := []string{}
+	_, err := Abstract(context.Background(), Config{
+		Dir:      `../../testData/go/test0013`,
+		Patterns: []string{`main.go`},
+		Workers:  2,
+		Progress: func(p Progress) { reported = append(reported, p.String()) },
+	})
+	check.NoError(t).Require(err)
+	check.Equal(t, []string{
+		`load`,
+		`analyze 0/1 packages`,
+		`analyze 1/1 packages`,
+		`abstract 0/1 packages`,
+		`abstract 1/1 packages`,
+		`imports`,
+		`receivers`,
+		`references (iteration 1)`,
+		`instantiations (iteration 1)`,
+		`references (iteration 2)`,
+		`instantiations (iteration 2)`,
+		`references`,
+		`duplicates`,
+		`interfaces`,
+		`inheritance`,
+		`deadCode`,
+	}).Assert(reported)
+}
+
+func Test_Abstract_LogGroups(t *testing.T) {
+	buf := &bytes.Buffer{}
+	_, err := Abstract(context.Background(), Config{
+		Dir:        `../../testData/go/test0013`,
+		Patterns:   []string{`main.go`},
+		LogHandler: slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		LogGroups:  []string{`usages`},
+	})
+	check.NoError(t).Require(err)
+
+	// The shown group's debug traces are written at the default level,
+	// but the other groups are still hidden.
+	check.True(t).Name(`has usages`).Assert(strings.Contains(buf.String(), `"level":"DEBUG",`))
+	check.True(t).Name(`has usages`).Assert(strings.Contains(buf.String(), `"group":"usages"`))
+	check.False(t).Name(`has resolver`).Assert(strings.Contains(buf.String(), `"group":"resolver"`))
+}
+
+func Test_Abstract_LogLevel(t *testing.T) {
+	// Giving a level, without Verbose or a LogHandler,
+	// writes the status information to the standard out.
+	out := captureStdout(t, func() {
+		_, err := Abstract(context.Background(), Config{
+			Dir:      `../../testData/go/test0001`,
+			Patterns: []string{`main.go`},
+			LogLevel: slog.LevelDebug,
+		})
+		check.NoError(t).Require(err)
+	})
+	check.True(t).Name(`has done`).Assert(strings.Contains(out, `done`))
+}
+
+// captureStdout gets what the given handle writes to the standard out.
+func captureStdout(t *testing.T, handle func()) string {
+	r, w, err := os.Pipe()
+	check.NoError(t).Require(err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	read := make(chan string)
+	go func() {
+		buf := &bytes.Buffer{}
+		_, _ = buf.ReadFrom(r)
+		read <- buf.String()
+	}()
+	handle()
+	check.NoError(t).Require(w.Close())
+	return <-read
+}
+
+func Test_Abstract_Cache(t *testing.T) {
+	cfg := Config{
+		Dir:        `../../testData/go/test0014`,
+		Patterns:   []string{`main.go`},
+		BuildFlags: []string{`-tags=test`},
+	}
+	abstract := func(cacheDir, used string) string {
+		logs := &bytes.Buffer{}
+		cfg := cfg
+		cfg.CacheDir = cacheDir
+		cfg.LogHandler = slog.NewJSONHandler(logs, nil)
+		proj, err := Abstract(context.Background(), cfg)
+		check.NoError(t).Require(err)
+		check.True(t).Name(`cache used`).With(`used`, used).Assert(strings.Contains(logs.String(), used))
+		buf := &bytes.Buffer{}
+		check.NoError(t).Require(WriteJSON(buf, proj))
+		return buf.String()
+	}
+
+	// The cache is filled and then restored from. That every fixture is
+	// restored with the same output is checked by the fixture tests.
+	uncached := abstract(``, ``)
+	dir := t.TempDir()
+	check.Equal(t, uncached).Name(`filled cache output`).Assert(abstract(dir, `(hits=0, misses=`))
+	check.Equal(t, uncached).Name(`cached output`).Assert(abstract(dir, `, misses=0)`))
+}
+
+func Test_WriteJSON(t *testing.T) {
+	proj, err := Abstract(context.Background(), Config{
+		Dir:      `../../testData/go/test0013`,
+		Patterns: []string{`main.go`},
+	})
+	check.NoError(t).Require(err)
+
+	buf := &bytes.Buffer{}
+	check.NoError(t).Require(WriteJSON(buf, proj))
+	exp, err := jsonify.Marshal(jsonify.NewContext(), abstracted.Constructs(proj))
+	check.NoError(t).Require(err)
+	check.Equal(t, string(exp)).Name(`default`).Assert(buf.String())
+
+	buf.Reset()
+	check.NoError(t).Require(WriteJSON(buf, proj, Minimize(true), IncludeCKMetrics(true),
+		IncludeStableIDs(true), ExcludeGeneratedMetrics(true)))
+	exp, err = jsonify.Marshal(jsonify.NewContext().
+		SetMinimize(true).
+		SetIncludeCKMetrics(true).
+		SetIncludeStableIDs(true).
+		SetExcludeGeneratedMetrics(true), abstracted.Constructs(proj))
+	check.NoError(t).Require(err)
+	check.Equal(t, string(exp)).Name(`with options`).Assert(buf.String())
+	check.True(t).Name(`has CK metrics`).Assert(strings.Contains(buf.String(), `"ckMetrics":`))
+	check.True(t).Name(`has stable IDs`).Assert(strings.Contains(buf.String(), `"id":"`))
+	check.False(t).Name(`is minimized`).Assert(strings.Contains(buf.String(), "\n"))
+}
+
+func Test_Abstract_WorkspaceModules(t *testing.T) {
+	proj, err := Abstract(context.Background(), Config{
+		Dir:        `../../testData/go/test0022`,
+		Modules:    []string{`../../testData/go/test0022/extra`},
+		BuildFlags: []string{`-tags=test`},
+	})
+	check.NoError(t).Require(err)
+
+	// The extra module is read with the workspace. How the modules
+	// are abstracted together is checked by the fixture tests.
+	check.Equal(t, []string{`example.com/app`, `example.com/extra`, `example.com/lib`}).
+		Assert(readPackagePaths(proj))
+}
+
+func Test_Abstract_Builds(t *testing.T) {
+	proj, err := Abstract(context.Background(), Config{
+		Dir:        `../../testData/go/test0023`,
+		Patterns:   []string{`./...`},
+		BuildFlags: []string{`-tags=test`},
+		Builds:     []string{`linux/amd64`, `windows/amd64`},
+	})
+	check.NoError(t).Require(err)
+
+	// Each build is read. How the builds are merged
+	// is checked by the fixture tests.
+	builds := []string{}
+	for m := range abstracted.Constructs(proj).Methods().Enumerate().Seq() {
+		if m.Name() == `open` {
+			builds = append(builds, m.BuildConfigs()...)
+		}
+	}
+	check.Equal(t, []string{`linux/amd64`, `windows/amd64`}).Assert(builds)
+
+	_, err = Abstract(context.Background(), Config{
+		Dir:    `../../testData/go/test0023`,
+		Builds: []string{`linux`},
+	})
+	check.MatchError(t, `build`).Name(`invalid build`).Assert(err)
+}
+
+func Test_Abstract_Tolerant(t *testing.T) {
+	proj, err := Abstract(context.Background(), Config{
+		Dir:        `../../testData/go/test0024`,
+		Patterns:   []string{`main.go`},
+		BuildFlags: []string{`-tags=test`},
+		Tolerant:   true,
+	})
+	check.NoError(t).Require(err)
+
+	// The failure is recorded instead of returned.
+	// What is still abstracted is checked by the fixture tests.
+	diags := proj.Diagnostics()
+	check.Length(t, 1).Name(`diagnostics`).Require(diags)
+	check.Equal(t, PhaseAnalyze).Name(`phase`).Assert(diags[0].Phase)
+	check.Equal(t, `size`).Name(`name`).Assert(diags[0].Name)
+	check.Equal(t, `main.go`).Name(`file`).Assert(filepath.Base(diags[0].Pos.Filename))
+	check.Equal(t, 20).Name(`line`).Assert(diags[0].Pos.Line)
+}
+
+func Test_Abstract_SkipBroken(t *testing.T) {
+	cfg := Config{
+		Dir:        `../../testData/go/test0025`,
+		Patterns:   []string{`./...`},
+		BuildFlags: []string{`-tags=test`},
+	}
+	proj, err := Abstract(context.Background(), cfg)
+	check.Nil(t).Name(`project`).Assert(proj)
+	var e *Error
+	check.True(t).Name(`is Error`).Assert(errors.As(err, &e))
+	check.Equal(t, PhaseLoad).Name(`phase`).Assert(e.Phase)
+
+	// The broken package and the package depending on it are recorded
+	// instead of returned. What is still abstracted is checked by the
+	// fixture tests.
+	cfg.SkipBroken = true
+	proj, err = Abstract(context.Background(), cfg)
+	check.NoError(t).Require(err)
+
+	diags := proj.Diagnostics()
+	check.Length(t, 2).Name(`diagnostics`).Require(diags)
+	check.Equal(t, PhaseLoad).Name(`broken phase`).Assert(diags[0].Phase)
+	check.Equal(t, `test0025/broken`).Name(`broken name`).Assert(diags[0].Name)
+	check.MatchError(t, `cannot use`).Name(`broken error`).Assert(diags[0].Err)
+	check.Equal(t, PhaseLoad).Name(`user phase`).Assert(diags[1].Phase)
+	check.Equal(t, `test0025/user`).Name(`user name`).Assert(diags[1].Name)
+	check.Equal(t, `depends on broken package test0025/broken`).Name(`user error`).Assert(diags[1].Err.Error())
+}
+
+func Test_Abstract_Tests(t *testing.T) {
+	cfg := Config{
+		Dir:        `../../testData/go/test0026`,
+		Patterns:   []string{`./...`},
+		BuildFlags: []string{`-tags=test`},
+	}
+	proj, err := Abstract(context.Background(), cfg)
+	check.NoError(t).Require(err)
+	check.Equal(t, []string{`test0026`, `test0026/lib`}).Assert(readPackagePaths(proj))
+
+	// The external test package is read with the test files. What is
+	// abstracted from the test files is checked by the fixture tests.
+	cfg.Tests = true
+	proj, err = Abstract(context.Background(), cfg)
+	check.NoError(t).Require(err)
+	check.Equal(t, []string{`test0026`, `test0026/lib`, `test0026/lib_test`}).Assert(readPackagePaths(proj))
+}
+
+func Test_Abstract_Roots(t *testing.T) {
+	proj, err := Abstract(context.Background(), Config{
+		Dir:           `../../testData/go/test0014`,
+		Patterns:      []string{`main.go`},
+		BuildFlags:    []string{`-tags=test`},
+		ExportedRoots: []string{`test0014/enums`},
+		RootSymbols:   []string{`test0014/animals.dog`},
+	})
+	check.NoError(t).Require(err)
+
+	// The roots are passed to the dead-code elimination. How each kind
+	// of root keeps declarations alive is checked by the fixture tests.
+	reasons := aliveReasons(proj)
+	check.True(t).Name(`exported root`).Assert(slices.Contains(reasons, `test0014/enums.Valid: exported API`))
+	check.True(t).Name(`symbol root`).Assert(slices.Contains(reasons, `test0014/animals.dog: symbol list`))
+}
+
+// aliveReasons gets the declarations in the packages that were read with
+// the reason each was kept alive by the dead-code elimination, if alive.
+func aliveReasons(proj *Project) []string {
+	reasons := []string{}
+	add := func(decl constructs.Declaration) {
+		if len(decl.Package().Module()) <= 0 {
+			return
+		}
+		reason := `dead`
+		if decl.Alive() {
+			reason = decl.AliveReason()
+			if len(reason) <= 0 {
+				reason = `used by ` + decl.AliveBy().String()
+			}
+		}
+		reasons = append(reasons, decl.Package().Path()+`.`+decl.Name()+`: `+reason)
+	}
+	for m := range abstracted.Constructs(proj).Methods().Enumerate().Seq() {
+		add(m)
+	}
+	for obj := range abstracted.Constructs(proj).Objects().Enumerate().Seq() {
+		add(obj)
+	}
+	slices.Sort(reasons)
+	return reasons
+}
+
+// readPackagePaths gets the paths of the packages in the modules that were read.
+func readPackagePaths(proj *Project) []string {
+	paths := []string{}
+	for pkg := range abstracted.Constructs(proj).Packages().Enumerate().Seq() {
+		if len(pkg.Module()) > 0 {
+			paths = append(paths, pkg.Path())
+		}
+	}
+	return paths
+}
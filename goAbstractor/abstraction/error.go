@@ -0,0 +1,103 @@
+package abstraction
+
+import (
+	"fmt"
+	"go/token"
+
+	"github.com/Snow-Gremlin/goToolbox/terrors"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstracted"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
+)
+
+// Phase is the part of the abstraction that a failure occurred in.
+type Phase = diagnostics.Phase
+
+const (
+	PhaseLoad           = diagnostics.Load
+	PhaseAbstract       = diagnostics.Abstract
+	PhaseAnalyze        = diagnostics.Analyze
+	PhaseImports        = diagnostics.Imports
+	PhaseReceivers      = diagnostics.Receivers
+	PhaseReferences     = diagnostics.References
+	PhaseInstantiations = diagnostics.Instantiations
+	PhaseDuplicates     = diagnostics.Duplicates
+	PhaseInterfaces     = diagnostics.Interfaces
+	PhaseInheritance    = diagnostics.Inheritance
+	PhaseDeadCode       = diagnostics.DeadCode
+)
+
+// Error is a failure that stopped the abstraction.
+type Error struct {
+	// Phase is the part of the abstraction the failure occurred in.
+	// This is empty if the phase is unknown.
+	Phase Phase
+
+	// Pos is the position in the source code of the construct that failed.
+	// This is invalid, see token.Position.IsValid, if the position is unknown.
+	Pos token.Position
+
+	// Construct is the name of the declaration or construct that failed.
+	// This is empty if the failure wasn't specific to one construct.
+	Construct string
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *Error) Error() string {
+	msg := `abstraction failed`
+	if len(e.Phase) > 0 {
+		msg += ` in ` + string(e.Phase)
+	}
+	if len(e.Construct) > 0 {
+		msg += fmt.Sprintf(` for %q`, e.Construct)
+	}
+	if e.Pos.IsValid() {
+		msg += ` at ` + e.Pos.String()
+	}
+	return msg + `: ` + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// newError creates an error for the given failure in the given phase.
+func newError(phase Phase, err error) *Error {
+	return &Error{Phase: phase, Err: err}
+}
+
+// fromPanic creates an error from a recovered panic.
+func fromPanic(r any) *Error {
+	d := diagnostics.FromPanic(r)
+	e := &Error{
+		Phase:     d.Phase,
+		Construct: d.Name,
+		Pos:       abstracted.Position(d.Loc),
+		Err:       d.Err,
+	}
+	if !e.Pos.IsValid() {
+		e.Pos = errorPos(d.Err)
+	}
+	return e
+}
+
+// errorPos gets the position that was added as context to the given
+// error, or any error it wraps, while the error was being panicked.
+func errorPos(err error) token.Position {
+	if c, ok := err.(terrors.Contexture); ok {
+		if pos, ok := c.Context()[`pos`].(token.Position); ok && pos.IsValid() {
+			return pos
+		}
+	}
+	switch w := err.(type) {
+	case interface{ Unwrap() error }:
+		return errorPos(w.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, inner := range w.Unwrap() {
+			if pos := errorPos(inner); pos.IsValid() {
+				return pos
+			}
+		}
+	}
+	return token.Position{}
+}
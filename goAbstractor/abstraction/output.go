@@ -0,0 +1,109 @@
+package abstraction
+
+import (
+	"io"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstracted"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+)
+
+// WriteOption is an option for how a project is written as JSON.
+// The options are created with the functions returning a WriteOption,
+// e.g. Minimize or IncludeCKMetrics.
+type WriteOption struct {
+	apply func(ctx *jsonify.Context) *jsonify.Context
+}
+
+// Minimize indicates the JSON should be minimized instead of formatted.
+func Minimize(min bool) WriteOption {
+	return WriteOption{apply: func(ctx *jsonify.Context) *jsonify.Context { return ctx.SetMinimize(min) }}
+}
+
+// SkipDead indicates the constructs found to be dead by the dead-code
+// elimination should be left out of the JSON. The project should be
+// abstracted with Config.SkipDead so that the indices skip the dead constructs.
+func SkipDead(skip bool) WriteOption {
+	return WriteOption{apply: func(ctx *jsonify.Context) *jsonify.Context { return ctx.SetSkipDead(skip) }}
+}
+
+// IncludeTestMetrics indicates the measurements, such as complexity and
+// line counts, of test code should be written and that test code should be
+// counted by the CK metrics and package metrics. By default only the
+// usages of test code are written.
+func IncludeTestMetrics(include bool) WriteOption {
+	return WriteOption{apply: func(ctx *jsonify.Context) *jsonify.Context { return ctx.SetIncludeTestMetrics(include) }}
+}
+
+// ExcludeGeneratedMetrics indicates the measurements of generated code
+// should not be written nor counted by the CK metrics and package metrics.
+// The usages of generated code are still written.
+func ExcludeGeneratedMetrics(exclude bool) WriteOption {
+	return WriteOption{apply: func(ctx *jsonify.Context) *jsonify.Context { return ctx.SetExcludeGeneratedMetrics(exclude) }}
+}
+
+// IncludeAliveReasons indicates the reason each declaration was kept
+// alive by the dead-code elimination should be written.
+func IncludeAliveReasons(include bool) WriteOption {
+	return WriteOption{apply: func(ctx *jsonify.Context) *jsonify.Context { return ctx.SetIncludeAliveReasons(include) }}
+}
+
+// IncludeDeadReport indicates a report of the dead declarations, methods,
+// and values, and the unused fields, grouped by package, should be written.
+func IncludeDeadReport(include bool) WriteOption {
+	return WriteOption{apply: func(ctx *jsonify.Context) *jsonify.Context { return ctx.SetIncludeDeadReport(include) }}
+}
+
+// IncludeCKMetrics indicates the Chidamber and Kemerer object-oriented
+// metrics of the objects in the read packages should be written.
+func IncludeCKMetrics(include bool) WriteOption {
+	return WriteOption{apply: func(ctx *jsonify.Context) *jsonify.Context { return ctx.SetIncludeCKMetrics(include) }}
+}
+
+// IncludePackageMetrics indicates the coupling and Martin metrics
+// of the read packages should be written.
+func IncludePackageMetrics(include bool) WriteOption {
+	return WriteOption{apply: func(ctx *jsonify.Context) *jsonify.Context { return ctx.SetIncludePackageMetrics(include) }}
+}
+
+// ExcludeStdlib indicates the standard library packages should not be
+// counted as packages that are used in the package metrics.
+func ExcludeStdlib(exclude bool) WriteOption {
+	return WriteOption{apply: func(ctx *jsonify.Context) *jsonify.Context { return ctx.SetExcludeStdlib(exclude) }}
+}
+
+// IncludeCycles indicates the dependency cycles between
+// the declarations should be written.
+func IncludeCycles(include bool) WriteOption {
+	return WriteOption{apply: func(ctx *jsonify.Context) *jsonify.Context { return ctx.SetIncludeCycles(include) }}
+}
+
+// IncludeStableIDs indicates the identifier derived from the content of
+// each package, declaration, and type description should be written.
+func IncludeStableIDs(include bool) WriteOption {
+	return WriteOption{apply: func(ctx *jsonify.Context) *jsonify.Context { return ctx.SetIncludeStableIDs(include) }}
+}
+
+// IncludeRanges indicates the start and end lines and columns
+// of the locations should be written.
+func IncludeRanges(include bool) WriteOption {
+	return WriteOption{apply: func(ctx *jsonify.Context) *jsonify.Context { return ctx.SetIncludeRanges(include) }}
+}
+
+// WriteJSON writes the given project as JSON to the given writer.
+// By default the JSON is formatted and only the abstraction is written,
+// the options can be used to minimize the JSON or to include the
+// additional reports and metrics.
+func WriteJSON(w io.Writer, proj *Project, opts ...WriteOption) error {
+	ctx := jsonify.NewContext()
+	for _, opt := range opts {
+		if opt.apply != nil {
+			ctx = opt.apply(ctx)
+		}
+	}
+	b, err := jsonify.Marshal(ctx, abstracted.Constructs(proj))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/Snow-Gremlin/goToolbox/argers/args"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diff"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+)
+
+// diffCommand is the name of the subcommand to compare two abstractions.
+const diffCommand = `diff`
+
+type diffArgObject struct {
+	ShowHelp bool   `args:"flag, h, help"`
+	Minimize bool   `args:"flag, m, minimize"`
+	OutPath  string `args:"o, out"`
+	Summary  bool   `args:"flag, s, summary"`
+	OldPath  string
+	NewPath  string
+}
+
+// runDiff compares two abstraction output files and
+// writes the differences as JSON and, optionally, a summary.
+func runDiff(arguments []string) {
+	ao := &diffArgObject{}
+	if slices.Contains(arguments, `-h`) || slices.Contains(arguments, `--help`) {
+		// The paths are required, so check for help before processing.
+		ao.ShowHelp = true
+	} else if err := args.New().Struct(ao).Process(arguments); err != nil {
+		fmt.Println(err.Error())
+		fmt.Println(`Use "diff -h" argument to show help.`)
+		os.Exit(1)
+	}
+
+	if ao.ShowHelp {
+		fmt.Println(`Diff will compare two abstraction JSON files, such as from`,
+			`two releases of a project, and output the packages, objects,`,
+			`interfaces, methods, and values that were added, removed, or changed`,
+			`with the changes in their measures, such as complexity and line count.`,
+			`Constructs are matched by their stable identifiers when both files`,
+			`were outputted with --stableIDs, otherwise by their qualified names.`)
+		fmt.Println(os.Args[0], diffCommand, `<options> <oldPath> <newPath>`)
+		fmt.Println(`  --help|-h: Shows this help text.`)
+		fmt.Println(`  --minimize|-m: Indicates the JSON output should be`,
+			`minimized instead of formatted.`)
+		fmt.Println(`  --out|-o: The output file path to write the JSON to.`,
+			`If not given, the JSON will be outputted to the console`,
+			`unless the summary is outputted.`)
+		fmt.Println(`  --summary|-s: Indicates a human readable summary`,
+			`of the differences should be outputted to the console.`)
+		os.Exit(0)
+	}
+
+	before, err := diff.Read(ao.OldPath)
+	if err != nil {
+		fmt.Println(`Error reading old abstraction:`, err)
+		os.Exit(1)
+	}
+	after, err := diff.Read(ao.NewPath)
+	if err != nil {
+		fmt.Println(`Error reading new abstraction:`, err)
+		os.Exit(1)
+	}
+
+	report := diff.New(before, after)
+	if ao.Summary {
+		fmt.Print(report.Summary())
+	}
+	if len(ao.OutPath) > 0 || !ao.Summary {
+		ctx := jsonify.NewContext().SetMinimize(ao.Minimize)
+		if err = writeJson(ao.OutPath, ctx, report); err != nil {
+			fmt.Println(`Error writing differences:`, err)
+			os.Exit(1)
+		}
+	}
+	os.Exit(0)
+}
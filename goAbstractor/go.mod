@@ -4,11 +4,9 @@ go 1.26
 
 require (
 	github.com/Snow-Gremlin/goToolbox v0.5.6
+	golang.org/x/mod v0.28.0
 	golang.org/x/tools v0.37.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
-require (
-	golang.org/x/mod v0.28.0 // indirect
-	golang.org/x/sync v0.17.0 // indirect
-)
+require golang.org/x/sync v0.17.0 // indirect
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Snow-Gremlin/goToolbox/argers/args"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/abstraction"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/history"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+)
+
+// historyCommand is the name of the subcommand to abstract
+// the snapshots of a git repository's history.
+const historyCommand = `history`
+
+type historyArgObject struct {
+	ShowHelp bool   `args:"flag, h, help"`
+	Verbose  bool   `args:"flag, v, verbose"`
+	Minimize bool   `args:"flag, m, minimize"`
+	InPath   string `args:"i, in"`
+	Dir      string `args:", dir"`
+	Ref      string `args:", ref"`
+	Tags     bool   `args:"flag, , tags"`
+	Every    int    `args:", every"`
+	Max      int    `args:", max"`
+	AllPkgs  bool   `args:"flag, , allPackages"`
+	OutPath  string `args:"o, out"`
+	Workers  int    `args:"w, workers"`
+	Tolerant bool   `args:"flag, t, tolerant"`
+	Partial  bool   `args:"flag, p, partial"`
+	Progress bool   `args:"flag, P, progress"`
+	Timeout  int    `args:", timeout"`
+}
+
+// runHistory abstracts the snapshots of a git repository's history and
+// writes the time series of the declaration and package metrics as JSON.
+func runHistory(arguments []string) {
+	ao := &historyArgObject{}
+	if err := args.New().Struct(ao).Process(arguments); err != nil {
+		fmt.Println(err.Error())
+		fmt.Println(`Use "history -h" argument to show help.`)
+		os.Exit(1)
+	}
+
+	if ao.ShowHelp {
+		fmt.Println(`History will abstract the snapshots of a local git repository's`,
+			`history using the git binary and temporary worktrees, and output a time`,
+			`series of the metrics of each declaration and package. The declarations`,
+			`are keyed by their kind, package, and qualified name so they can be`,
+			`followed across snapshots, and the declarations with the same key in a`,
+			`snapshot, such as init functions, are summed. By default only the`,
+			`declarations in the read packages are collected.`,
+			`Snapshots that fail to abstract are recorded and skipped.`)
+		fmt.Println(os.Args[0], historyCommand, `<options> -i <repoPath> [ -o <outputPath> ]`)
+		fmt.Println(`  --help|-h: Shows this help text.`)
+		fmt.Println(`  --verbose|-v: Indicates the abstraction of each snapshot`,
+			`should output additional status information.`)
+		fmt.Println(`  --minimize|-m: Indicates the JSON output should be`,
+			`minimized instead of formatted.`)
+		fmt.Println(`  --in|-i: The path to the local git repository.`,
+			`If not given, the current directory is used.`)
+		fmt.Println(`  --dir: The path, relative to the repository's root, of the`,
+			`directory of the project to abstract in each snapshot.`)
+		fmt.Println(`  --ref: The reference to walk the first-parent commits back from.`,
+			`If not given, "HEAD" is used.`)
+		fmt.Println(`  --tags: Indicates that only the tagged commits should be`,
+			`abstracted instead of the first-parent commits.`)
+		fmt.Println(`  --every: Indicates that only every Nth commit should be`,
+			`abstracted, always including the newest commit.`)
+		fmt.Println(`  --max: The maximum number of the newest snapshots to abstract.`,
+			`If not given, all the snapshots are abstracted.`)
+		fmt.Println(`  --allPackages: Indicates that the declarations in all the`,
+			`abstracted packages, including the dependencies such as the`,
+			`standard library, should be collected instead of only the`,
+			`declarations in the read packages.`)
+		fmt.Println(`  --out|-o: The output file path to write the JSON to.`,
+			`If not given, the JSON will be outputted to the console.`)
+		fmt.Println(`  --workers|-w: The number of packages to analyze and abstract concurrently.`,
+			`The output is the same as when run serially. If not given,`,
+			`the packages will be analyzed and abstracted serially.`)
+		fmt.Println(`  --tolerant|-t: Indicates that failures in declarations`,
+			`should be skipped instead of failing the snapshot.`)
+		fmt.Println(`  --partial|-p: Indicates that packages with errors, and`,
+			`packages depending on them, should be skipped instead of`,
+			`failing the snapshot.`)
+		fmt.Println(`  --progress|-P: Indicates that the progress of each snapshot`,
+			`should be written to the standard error.`)
+		fmt.Println(`  --timeout: The number of seconds to allow the mining`,
+			`to run before it is cancelled. If not given, there is no timeout.`)
+		os.Exit(0)
+	}
+
+	repo := ao.InPath
+	if len(repo) <= 0 {
+		repo = `.`
+	}
+
+	ctx := context.Background()
+	if ao.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(ao.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	var prog func(number, total int, s *history.Snapshot)
+	if ao.Progress {
+		prog = func(number, total int, s *history.Snapshot) {
+			fmt.Fprintf(os.Stderr, "snapshot %d/%d: %s %s\n",
+				number, total, s.Commit, s.Time.Format(time.RFC3339))
+		}
+	}
+
+	series, err := history.Mine(ctx, history.Config{
+		Repo:  repo,
+		Dir:   ao.Dir,
+		Ref:   ao.Ref,
+		Tags:  ao.Tags,
+		Every: ao.Every,
+		Max:   ao.Max,
+
+		AllPackages: ao.AllPkgs,
+		Abstraction: abstraction.Config{
+			SkipBroken: ao.Partial,
+			Tolerant:   ao.Tolerant,
+			Workers:    ao.Workers,
+			Verbose:    ao.Verbose,
+		},
+		Progress: prog,
+	})
+	if err != nil {
+		fmt.Println(`Error mining history:`, err)
+		os.Exit(1)
+	}
+
+	jCtx := jsonify.NewContext().SetMinimize(ao.Minimize)
+	if err = writeJson(ao.OutPath, jCtx, series); err != nil {
+		fmt.Println(`Error writing history:`, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,86 @@
+package abstracted
+
+import (
+	"go/token"
+
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+)
+
+// Project is the abstraction of a Go project.
+// Use WriteJSON to write the project as JSON.
+type Project struct {
+	proj constructs.Project
+}
+
+// New creates the abstraction of a project from the given constructs.
+func New(proj constructs.Project) *Project {
+	return &Project{proj: proj}
+}
+
+// Constructs gets the constructs of the given project so that the commands
+// and internal packages can measure and report on them. This isn't a method
+// so that the constructs stay hidden from users outside of this module.
+func Constructs(p *Project) constructs.Project {
+	return p.proj
+}
+
+// Packages gets the paths of the packages in the project, both the packages
+// that were read and the packages they depend on, in the order they are
+// written in the JSON.
+func (p *Project) Packages() []string {
+	paths := make([]string, 0, p.proj.Packages().Count())
+	for pkg := range p.proj.Packages().Enumerate().Seq() {
+		paths = append(paths, pkg.Path())
+	}
+	return paths
+}
+
+// Diagnostics gets the failures that were recovered from so that the
+// abstraction could continue without the failed part, e.g. the failed
+// declarations with Config.Tolerant or the broken packages with
+// Config.SkipBroken. The diagnostics are also written in the JSON.
+func (p *Project) Diagnostics() []Diagnostic {
+	diags := p.proj.Diagnostics().Diagnostics()
+	result := make([]Diagnostic, len(diags))
+	for i, d := range diags {
+		result[i] = Diagnostic{
+			Phase: d.Phase,
+			Name:  d.Name,
+			Pos:   Position(d.Loc),
+			Err:   d.Err,
+		}
+	}
+	return result
+}
+
+// Diagnostic is a failure that was recovered from so that
+// the abstraction could continue without the failed part.
+type Diagnostic struct {
+	// Phase is the part of the abstraction the failure occurred in.
+	Phase diagnostics.Phase
+
+	// Name is the name of the declaration or construct that failed,
+	// or the path of the package that was skipped.
+	// This is empty if the failure wasn't specific to one construct.
+	Name string
+
+	// Pos is the position in the source code of the construct that failed.
+	// This is invalid, see token.Position.IsValid, if the position is unknown.
+	Pos token.Position
+
+	// Err is the recovered error.
+	Err error
+}
+
+// Position gets the file and line of the given optional location.
+func Position(loc locs.Loc) token.Position {
+	if utils.IsNil(loc) {
+		return token.Position{}
+	}
+	_, file, line := loc.Info()
+	return token.Position{Filename: file, Line: line}
+}
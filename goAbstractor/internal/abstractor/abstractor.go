@@ -1,11 +1,15 @@
 package abstractor
 
 import (
+	"context"
 	"go/ast"
 	"go/token"
 	"go/types"
+	"maps"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
 	"github.com/Snow-Gremlin/goToolbox/utils"
@@ -16,39 +20,128 @@ import (
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/converter"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/querier"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/resolver"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/resolver/dce"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/assert"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/cache"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/innate"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/project"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/logger"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/metadata"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/progress"
 )
 
 type Config struct {
 	Packages []*packages.Package
 	Log      *logger.Logger
 	SkipDead bool
+
+	// Builds are the optional packages read for each named build
+	// configuration. Each build is abstracted into the same project
+	// and each declaration and metrics lists the builds it was found in.
+	Builds map[string][]*packages.Package
+
+	// Workers is the number of packages that may be analyzed and abstracted
+	// concurrently. Each package is abstracted into a project of its own
+	// that is joined into the resulting project in the same order as a
+	// serial run, so the resulting project is identical to a serial run.
+	// Zero or one will run fully serially.
+	Workers int
+
+	// Cache is the optional on-disk cache of the constructs abstracted
+	// from each package. The constructs for a package that hasn't changed,
+	// and doesn't depend on a changed package, since it was cached are
+	// restored instead of being abstracted again. All the packages are
+	// still read, type checked, and resolved, so the cache only saves
+	// the time spent abstracting and analyzing the cached packages.
+	Cache *cache.Cache
+
+	// Tolerant indicates that a failure while abstracting, analyzing, or
+	// resolving a declaration should be recovered from and recorded in
+	// the project's diagnostics, leaving out the failed declaration,
+	// instead of panicking and stopping the whole abstraction.
+	Tolerant bool
+
+	// Diagnostics are any diagnostics from before the abstraction,
+	// such as the packages skipped while reading, to add to the project.
+	Diagnostics []diagnostics.Diagnostic
+
+	// Roots are the additional roots of the dead-code elimination, such as
+	// the packages whose exported API is kept alive. The declarations
+	// referenced by `//go:linkname` and `//export` directives are always
+	// added to these roots.
+	Roots dce.Roots
+
+	// ReflectionRoots indicates that the declarations used in
+	// a reflection-based registration, e.g. `gob.Register(Foo{})`,
+	// should be roots of the dead-code elimination.
+	ReflectionRoots bool
+
+	// Context is the optional context to cancel the abstraction with.
+	// The context is checked before each declaration, package, and
+	// resolver phase, and for each construct handled by the resolver.
+	// When cancelled, the context's error is panicked.
+	Context context.Context
+
+	// Progress is the optional reporter for the progress of each phase.
+	Progress *progress.Reporter
+
+	// Metadata is the optional provenance of the abstraction
+	// to add to the project.
+	Metadata *metadata.Metadata
 }
 
 func Abstract(cfg Config) constructs.Project {
+	pkgs := slices.Clone(cfg.Packages)
+	for _, name := range slices.Sorted(maps.Keys(cfg.Builds)) {
+		pkgs = append(pkgs, cfg.Builds[name]...)
+	}
+
 	var (
 		log     = cfg.Log
-		querier = querier.New(cfg.Packages)
+		querier = querier.New(pkgs)
 		locs    = locs.NewSet(querier.FileSet())
 		proj    = project.New(locs)
 		bk      = baker.New(proj)
 	)
+	proj.Diagnostics().SetTolerant(cfg.Tolerant)
+	proj.Diagnostics().SetContext(cfg.Context)
+	proj.SetMetadata(cfg.Metadata)
+	for _, d := range cfg.Diagnostics {
+		proj.Diagnostics().Add(d)
+	}
 
 	ab := &abstractor{
 		querier:   querier,
 		baker:     bk,
 		proj:      proj,
 		typeCache: map[any]any{},
+		packages:  cfg.Packages,
+		builds:    cfg.Builds,
+		roots:     maps.Clone(cfg.Roots.Symbols),
+		progress:  cfg.Progress,
+		workers:   cfg.Workers,
+		cache:     cfg.Cache,
+		ctx:       cfg.Context,
+
+		reflectionRoots: cfg.ReflectionRoots,
+	}
+	if ab.roots == nil {
+		ab.roots = map[string]string{}
 	}
 	ab.abstractProject(log)
 
-	resolver.Resolve(proj, querier, log, cfg.SkipDead)
+	roots := dce.Roots{
+		Exported: cfg.Roots.Exported,
+		Symbols:  ab.roots,
+	}
+	resolver.Resolve(proj, querier, log, cfg.Progress, roots, cfg.SkipDead)
 
+	if count := len(proj.Diagnostics().Diagnostics()); count > 0 {
+		log.Warnf(`recorded %d failures as diagnostics`, count)
+	}
 	log.Log(`done`)
 	return proj
 }
@@ -57,11 +150,22 @@ type abstractor struct {
 	querier       *querier.Querier
 	baker         baker.Baker
 	proj          constructs.Project
+	prepared      *analyzer.Prepared
+	packages      []*packages.Package
+	builds        map[string][]*packages.Package
+	curBuild      string
 	curPkg        constructs.Package
 	curNest       constructs.NestType
 	implicitTypes []constructs.TypeDesc
 	tpReplacer    map[*types.TypeParam]*types.TypeParam
 	typeCache     map[any]any
+
+	roots           map[string]string
+	reflectionRoots bool
+	progress        *progress.Reporter
+	workers         int
+	cache           *cache.Cache
+	ctx             context.Context
 }
 
 func (ab *abstractor) pos(pos token.Pos) token.Position {
@@ -73,12 +177,76 @@ func (ab *abstractor) converter(log *logger.Logger) converter.Converter {
 		ab.curPkg, ab.curNest, ab.implicitTypes, ab.tpReplacer, ab.typeCache)
 }
 
+// prepare concurrently calculates the analysis results, which don't create
+// any constructs, for the given packages when there are several workers.
+func (ab *abstractor) prepare(log *logger.Logger, srcs []*packages.Package) {
+	if ab.workers > 1 {
+		log.Logf(`prepare analysis (workers=%d, packages=%d)`, ab.workers, len(srcs))
+		ab.proj.Diagnostics().InPhase(diagnostics.Analyze, func() {
+			ab.prepared = analyzer.Prepare(ab.ctx, ab.progress, ab.querier, ab.proj, ab.workers, srcs)
+		})
+	}
+}
+
 func (ab *abstractor) abstractProject(log *logger.Logger) {
 	log.Log(`abstract project`)
 	log2 := log.Group(`packages`).Indent()
-	ab.querier.ForeachPackage(func(src *packages.Package) {
-		ab.abstractPackage(src, log2)
-	})
+	units := ab.units()
+	if ab.workers > 1 || ab.cache != nil {
+		ab.abstractConcurrently(units, log, log2)
+		return
+	}
+
+	for i, u := range units {
+		ab.progress.Packages(diagnostics.Abstract, i, len(units))
+		if u.build != ab.curBuild {
+			log.Logf(`abstract build: %s`, u.build)
+			ab.curBuild = u.build
+		}
+		ab.abstractPackage(u.src, log2)
+	}
+	ab.curBuild = ``
+	ab.progress.Packages(diagnostics.Abstract, len(units), len(units))
+}
+
+// unit is a package to abstract with the name of
+// the build configuration that it was read for, if any.
+type unit struct {
+	build string
+	src   *packages.Package
+}
+
+// units gets the packages to abstract in the order they are abstracted.
+// When there are build configurations, the packages read without a
+// configuration are first, then the packages for each configuration.
+func (ab *abstractor) units() []unit {
+	units := []unit{}
+	if len(ab.builds) <= 0 {
+		ab.querier.ForeachPackage(func(src *packages.Package) {
+			units = append(units, unit{src: src})
+		})
+		return units
+	}
+
+	if len(ab.packages) > 0 {
+		ab.querier.ForeachPackageIn(ab.packages, func(src *packages.Package) {
+			units = append(units, unit{src: src})
+		})
+	}
+	for _, name := range slices.Sorted(maps.Keys(ab.builds)) {
+		ab.querier.ForeachPackageIn(ab.builds[name], func(src *packages.Package) {
+			units = append(units, unit{build: name, src: src})
+		})
+	}
+	return units
+}
+
+// inBuild adds the current build configuration, if there is one,
+// to the given declaration or metrics.
+func (ab *abstractor) inBuild(c constructs.BuildConfigured) {
+	if !utils.IsNil(c) {
+		c.AddBuildConfigs(ab.curBuild)
+	}
 }
 
 func (ab *abstractor) abstractPackage(src *packages.Package, log *logger.Logger) {
@@ -88,6 +256,8 @@ func (ab *abstractor) abstractPackage(src *packages.Package, log *logger.Logger)
 		Path:        src.PkgPath,
 		Name:        src.Name,
 		ImportPaths: utils.SortedKeys(src.Imports),
+		Module:      modulePath(src),
+		EntryPoint:  ab.querier.IsRoot(src),
 	})
 	log2 := log.Group(`files`).Indent()
 	for _, f := range src.Syntax {
@@ -95,32 +265,85 @@ func (ab *abstractor) abstractPackage(src *packages.Package, log *logger.Logger)
 	}
 }
 
-func (ab *abstractor) abstractFile(f *ast.File, log *logger.Logger) {
+// aliasFile aliases the path of the given file in the given package to
+// the path relative to the package's directory, e.g. `foo/bar/baz.go`,
+// and returns the base name of the file.
+func (ab *abstractor) aliasFile(src *packages.Package, f *ast.File) string {
 	path := ab.pos(f.FileStart).Filename
 	basePath := filepath.Base(path)
-	if ab.curPkg.EntryPoint() {
+	if src.PkgPath == `command-line-arguments` {
 		ab.proj.Locs().Alias(path, basePath)
 	} else {
-		pkgPath := ab.curPkg.Source().PkgPath
+		// An external test package, e.g. `foo_test`, is in the
+		// directory of the package it tests.
+		pkgPath := src.PkgPath
+		if forTest := src.ForTest; len(forTest) > 0 {
+			pkgPath = forTest
+		}
 		alias := filepath.ToSlash(filepath.Join(pkgPath, basePath))
 		ab.proj.Locs().Alias(path, alias)
 	}
+	return basePath
+}
+
+func modulePath(src *packages.Package) string {
+	if src.Module == nil {
+		return ``
+	}
+	return src.Module.Path
+}
 
-	log.Logf(`add file to package: %s`, basePath)
+func (ab *abstractor) abstractFile(f *ast.File, log *logger.Logger) {
+	basePath := ab.aliasFile(ab.curPkg.Source(), f)
+	log.Debugf(`add file to package: %s`, basePath)
+	ab.findRoots(f)
 	log2 := log.Indent()
 	for _, decl := range f.Decls {
 		switch d := decl.(type) {
 		case *ast.GenDecl:
 			ab.abstractGenDecl(d, log2)
 		case *ast.FuncDecl:
-			ab.abstractFuncDecl(d, log2)
+			ab.tolerate(d.Name.Name, d.Pos(), func() {
+				ab.abstractFuncDecl(d, log2)
+			})
 		default:
-			panic(terror.New(`unexpected declaration`).
-				With(`pos`, ab.pos(decl.Pos())))
+			ab.tolerate(``, decl.Pos(), func() {
+				panic(terror.New(`unexpected declaration`).
+					With(`pos`, ab.pos(decl.Pos())))
+			})
 		}
 	}
 }
 
+// tolerate runs the given handle for abstracting a declaration.
+// If the abstraction is tolerant and the handle fails, the failure is
+// recorded and the state is cleaned up so that the next declaration
+// can be abstracted without the failed declaration. Any construct the
+// failed declaration added is removed so that none is left partially
+// abstracted.
+func (ab *abstractor) tolerate(name string, pos token.Pos, handle func()) {
+	loc := ab.proj.Locs().NewLoc(pos)
+	cp := ab.proj.Checkpoint()
+	if !ab.proj.Diagnostics().Tolerate(diagnostics.Abstract, name, loc, handle) {
+		ab.clearTypeParamOverrides()
+		ab.rollback(cp)
+	}
+}
+
+// rollback removes the constructs added after the given checkpoint,
+// including from the baked and converted types that are reused.
+func (ab *abstractor) rollback(cp constructs.Checkpoint) {
+	removed := ab.proj.Rollback(cp)
+	if len(removed) <= 0 {
+		return
+	}
+	ab.baker.RemoveRolledBack(removed)
+	maps.DeleteFunc(ab.typeCache, func(_, cached any) bool {
+		c, ok := cached.(constructs.Construct)
+		return ok && removed[c]
+	})
+}
+
 func (ab *abstractor) abstractGenDecl(decl *ast.GenDecl, log *logger.Logger) {
 	isConst := decl.Tok == token.CONST
 	for _, spec := range decl.Specs {
@@ -128,26 +351,40 @@ func (ab *abstractor) abstractGenDecl(decl *ast.GenDecl, log *logger.Logger) {
 		case *ast.ImportSpec:
 			// ignore
 		case *ast.TypeSpec:
-			ab.abstractTypeSpec(s, log)
+			ab.tolerate(s.Name.Name, s.Pos(), func() {
+				ab.abstractTypeSpec(s, log)
+			})
 		case *ast.ValueSpec:
-			ab.abstractValueSpec(s, isConst, log)
+			ab.tolerate(valueSpecName(s), s.Pos(), func() {
+				ab.abstractValueSpec(s, isConst, log)
+			})
 		default:
-			panic(terror.New(`unexpected specification`).
-				With(`pos`, ab.pos(spec.Pos())))
+			ab.tolerate(``, spec.Pos(), func() {
+				panic(terror.New(`unexpected specification`).
+					With(`pos`, ab.pos(spec.Pos())))
+			})
 		}
 	}
 }
 
+func valueSpecName(spec *ast.ValueSpec) string {
+	names := make([]string, len(spec.Names))
+	for i, name := range spec.Names {
+		names[i] = name.Name
+	}
+	return strings.Join(names, `, `)
+}
+
 func (ab *abstractor) abstractTypeSpec(spec *ast.TypeSpec, log *logger.Logger) {
 	t := ab.querier.GetType(spec.Type)
 	context := t.String()
-	loc := ab.proj.Locs().NewLoc(spec.Pos())
+	loc := ab.proj.Locs().NewRange(spec.Pos(), spec.End())
 	tp := ab.abstractTypeParams(spec.TypeParams, context, log)
 	typ := ab.converter(log).ConvertType(t, context)
 
 	if it, ok := typ.(constructs.InterfaceDesc); ok {
-		log.Logf(`add interface: %s @ %v`, spec.Name.Name, loc)
-		ab.proj.NewInterfaceDecl(constructs.InterfaceDeclArgs{
+		log.Debugf(`add interface: %s @ %v`, spec.Name.Name, loc)
+		ab.inBuild(ab.proj.NewInterfaceDecl(constructs.InterfaceDeclArgs{
 			RealType:   t,
 			Package:    ab.curPkg,
 			Name:       spec.Name.Name,
@@ -155,16 +392,18 @@ func (ab *abstractor) abstractTypeSpec(spec *ast.TypeSpec, log *logger.Logger) {
 			Interface:  it,
 			TypeParams: tp,
 			Location:   loc,
+			TestCode:   ab.querier.InTestFile(spec.Pos()),
+			Generated:  ab.querier.InGeneratedFile(spec.Pos()),
 			Nest:       ab.curNest,
-		})
+		}))
 		return
 	}
 
 	st, ok := typ.(constructs.StructDesc)
 	if ok {
-		log.Logf(`add struct type: %s @ %v`, spec.Name.Name, loc)
+		log.Debugf(`add struct type: %s @ %v`, spec.Name.Name, loc)
 	} else {
-		log.Logf(`add value type: %s @ %v`, spec.Name.Name, loc)
+		log.Debugf(`add value type: %s @ %v`, spec.Name.Name, loc)
 		st = ab.proj.NewStructDesc(constructs.StructDescArgs{
 			Fields: []constructs.Field{
 				ab.proj.NewField(constructs.FieldArgs{
@@ -178,7 +417,7 @@ func (ab *abstractor) abstractTypeSpec(spec *ast.TypeSpec, log *logger.Logger) {
 		})
 	}
 
-	ab.proj.NewObject(constructs.ObjectArgs{
+	ab.inBuild(ab.proj.NewObject(constructs.ObjectArgs{
 		RealType:   t,
 		Package:    ab.curPkg,
 		Name:       spec.Name.Name,
@@ -186,8 +425,45 @@ func (ab *abstractor) abstractTypeSpec(spec *ast.TypeSpec, log *logger.Logger) {
 		Data:       st,
 		TypeParams: tp,
 		Location:   loc,
+		TestCode:   ab.querier.InTestFile(spec.Pos()),
+		Generated:  ab.querier.InGeneratedFile(spec.Pos()),
 		Nest:       ab.curNest,
-	})
+
+		FieldLocations: ab.abstractFieldLocations(spec, t),
+	}))
+}
+
+// abstractFieldLocations gets the ranges of the fields, keyed by the field
+// name, for a type spec with a struct. Each range starts at the name of
+// the field, or the type for an embedded field, and ends after the tag.
+func (ab *abstractor) abstractFieldLocations(spec *ast.TypeSpec, t types.Type) map[string]locs.Loc {
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok || utils.IsNil(st.Fields) {
+		return nil
+	}
+	ts, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	fieldLocs := map[string]locs.Loc{}
+	index := 0
+	for _, field := range st.Fields.List {
+		count := max(len(field.Names), 1)
+		for i := 0; i < count && index < ts.NumFields(); i++ {
+			f := ts.Field(index)
+			index++
+			if constructs.BlankName(f.Name()) {
+				continue
+			}
+			start := field.Pos()
+			if i < len(field.Names) {
+				start = field.Names[i].Pos()
+			}
+			fieldLocs[f.Name()] = ab.proj.Locs().NewRange(start, field.End())
+		}
+	}
+	return fieldLocs
 }
 
 func (ab *abstractor) abstractTypeParams(fields *ast.FieldList, context string, log *logger.Logger) []constructs.TypeParam {
@@ -208,7 +484,19 @@ func (ab *abstractor) abstractTypeParam(field *ast.Field, context string, log *l
 
 	t := ab.querier.GetType(field.Type)
 	typ := ab.converter(log).ConvertType(t, context)
+	ref, isRef := typ.(constructs.TempReference)
+	isRef = isRef && len(ref.ImplicitTypes()) <= 0 && len(ref.InstanceTypes()) <= 0
 	for _, name := range field.Names {
+		// A non-generic constraint declared in a package that hasn't been
+		// abstracted yet is a temporary reference. The type parameter is
+		// converted the same way as where it is used instead, so that both
+		// are the same type parameter when the declaration is instantiated.
+		if isRef {
+			tp := ab.converter(log).ConvertType(ab.querier.GetDef(name).Type(), context)
+			ns = append(ns, tp.(constructs.TypeParam))
+			continue
+		}
+
 		named := ab.proj.NewTypeParam(constructs.TypeParamArgs{
 			Name: name.Name,
 			Type: typ,
@@ -218,8 +506,12 @@ func (ab *abstractor) abstractTypeParam(field *ast.Field, context string, log *l
 	return ns
 }
 
-func (ab *abstractor) analyze(node ast.Node, log *logger.Logger) constructs.Metrics {
-	return analyzer.Analyze(log, ab.querier, ab.proj, ab.curPkg, ab.baker, ab.converter(log), node)
+func (ab *abstractor) analyze(node ast.Node, log *logger.Logger) (metrics constructs.Metrics) {
+	ab.proj.Diagnostics().InPhase(diagnostics.Analyze, func() {
+		metrics = analyzer.Analyze(log, ab.querier, ab.proj, ab.curPkg, ab.baker, ab.converter(log), ab.prepared, node)
+	})
+	ab.inBuild(metrics)
+	return metrics
 }
 
 func (ab *abstractor) abstractValueSpec(spec *ast.ValueSpec, isConst bool, log *logger.Logger) {
@@ -228,24 +520,26 @@ func (ab *abstractor) abstractValueSpec(spec *ast.ValueSpec, isConst bool, log *
 		if i < len(spec.Values) {
 			metrics = ab.analyze(spec.Values[i], log)
 		}
-		loc := ab.proj.Locs().NewLoc(name.Pos())
+		loc := ab.proj.Locs().NewRange(name.Pos(), spec.End())
 		if isConst {
-			log.Logf(`add const: %s @ %v`, name.Name, loc)
+			log.Debugf(`add const: %s @ %v`, name.Name, loc)
 		} else {
-			log.Logf(`add var: %s @ %v`, name.Name, loc)
+			log.Debugf(`add var: %s @ %v`, name.Name, loc)
 		}
 
 		obj := ab.querier.GetDef(name)
 		typ := ab.converter(log).ConvertType(obj.Type(), name.Name)
-		ab.proj.NewValue(constructs.ValueArgs{
-			Package:  ab.curPkg,
-			Name:     name.Name,
-			Exported: name.IsExported(),
-			Const:    isConst,
-			Metrics:  metrics,
-			Type:     typ,
-			Location: loc,
-		})
+		ab.inBuild(ab.proj.NewValue(constructs.ValueArgs{
+			Package:   ab.curPkg,
+			Name:      name.Name,
+			Exported:  name.IsExported(),
+			Const:     isConst,
+			Metrics:   metrics,
+			Type:      typ,
+			Location:  loc,
+			TestCode:  ab.querier.InTestFile(name.Pos()),
+			Generated: ab.querier.InGeneratedFile(name.Pos()),
+		}))
 	}
 }
 
@@ -308,7 +602,7 @@ func (ab *abstractor) abstractFuncDecl(decl *ast.FuncDecl, log *logger.Logger) {
 	obj := info.Defs[decl.Name].(*types.Func)
 	assert.ArgNotNil(`abstractFuncDecl's obj`, obj)
 
-	loc := ab.proj.Locs().NewLoc(decl.Pos())
+	loc := ab.proj.Locs().NewRange(decl.Pos(), decl.End())
 	ptrRecv, recvName := ab.abstractReceiver(decl)
 	sigType := obj.Type().(*types.Signature)
 	sig := ab.converter(log).ConvertSignature(sigType, decl.Name.Name)
@@ -318,10 +612,13 @@ func (ab *abstractor) abstractFuncDecl(decl *ast.FuncDecl, log *logger.Logger) {
 	if name == `init` && len(recvName) <= 0 && sig.IsVacant() {
 		name = `init#` + strconv.Itoa(ab.curPkg.InitCount())
 	}
+	if name == `_` {
+		name = `_#` + strconv.Itoa(ab.curPkg.BlankCount())
+	}
 	if len(recvName) > 0 {
-		log.Logf(`add func: %s.%s @ %v`, recvName, name, loc)
+		log.Debugf(`add func: %s.%s @ %v`, recvName, name, loc)
 	} else {
-		log.Logf(`add func: %s @ %v`, name, loc)
+		log.Debugf(`add func: %s @ %v`, name, loc)
 	}
 
 	// Set the nest for this function. Use the type parameter as the implicit
@@ -339,13 +636,26 @@ func (ab *abstractor) abstractFuncDecl(decl *ast.FuncDecl, log *logger.Logger) {
 		Receiver:      recvName,
 		ImplicitTypes: ab.implicitTypes,
 	})
-	if tempNest.Resolved() {
+	// The same function is found again when it is part of more than one
+	// build configuration, in which case the nest was resolved by the
+	// earlier build configuration.
+	reabstracted := tempNest.Resolved()
+	if reabstracted && len(ab.curBuild) <= 0 {
 		panic(terror.New(`the temporary nest placeholder has already resolved`).
 			With(`name`, name).
 			With(`receiver`, recvName).
 			With(`ref`, tempNest))
 	}
 
+	// If the function fails to be abstracted, the placeholder is dropped
+	// so that it, and any usage of the function, isn't left unresolved.
+	nestResolved := reabstracted
+	defer func() {
+		if !nestResolved {
+			tempNest.Drop()
+		}
+	}()
+
 	ab.curNest = tempNest
 	ab.implicitTypes = make([]constructs.TypeDesc, len(tp))
 	for i, t := range tp {
@@ -363,14 +673,20 @@ func (ab *abstractor) abstractFuncDecl(decl *ast.FuncDecl, log *logger.Logger) {
 		Name:        name,
 		Exported:    exported,
 		Location:    loc,
+		TestCode:    ab.querier.InTestFile(decl.Pos()),
+		Generated:   ab.querier.InGeneratedFile(decl.Pos()),
 		TypeParams:  tp,
 		Signature:   sig,
 		Metrics:     metrics,
 		RecvName:    recvName,
 		PointerRecv: ptrRecv,
 	})
+	ab.inBuild(method)
 
-	tempNest.SetResolution(method)
+	if !reabstracted {
+		tempNest.SetResolution(method)
+		nestResolved = true
+	}
 	ab.curNest = method
 	ab.abstractNestedTypes(decl.Body, log.Indent())
 }
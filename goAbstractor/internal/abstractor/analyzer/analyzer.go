@@ -21,6 +21,7 @@ func Analyze(
 	curPkg constructs.Package,
 	baker baker.Baker,
 	conv converter.Converter,
+	prep *Prepared,
 	node ast.Node,
 ) constructs.Metrics {
 
@@ -30,21 +31,27 @@ func Analyze(
 	log.Logf(`analyze`)
 	log2 := log.Indent()
 
-	switch curPkg.Path() {
-	case `runtime`, `unsafe`, `reflect`:
-		// Skip analyzing things in these packages.
+	if skipPackage(curPkg.Path()) {
 		return nil
 	}
 
+	pr, ok := prep.lookup(node)
+	if !ok {
+		pr.cmplx = complexity.Calculate(log2, node, proj.Locs().FileSet())
+		pr.acc = accessor.Calculate(log2, querier.Info(), node)
+	}
+
 	var (
-		loc    = proj.Locs().NewLoc(node.Pos())
-		cmplx  = complexity.Calculate(log2, node, proj.Locs().FileSet())
-		acc    = accessor.Calculate(log2, querier.Info(), node)
+		loc    = proj.Locs().NewRange(node.Pos(), node.End())
+		cmplx  = pr.cmplx
+		acc    = pr.acc
 		usages = usages.Calculate(log2, querier, proj, curPkg, baker, conv, node)
 	)
 
 	return proj.NewMetrics(constructs.MetricsArgs{
 		Location:   loc,
+		TestCode:   querier.InTestFile(node.Pos()),
+		Generated:  querier.InGeneratedFile(node.Pos()),
 		Node:       node,
 		TpReplacer: conv.TpReplacer(),
 		Complexity: cmplx.Complexity,
@@ -59,3 +66,13 @@ func Analyze(
 		SideEffect: usages.SideEffect,
 	})
 }
+
+// skipPackage indicates that things in the package with
+// the given path should not be analyzed.
+func skipPackage(pkgPath string) bool {
+	switch pkgPath {
+	case `runtime`, `unsafe`, `reflect`:
+		return true
+	}
+	return false
+}
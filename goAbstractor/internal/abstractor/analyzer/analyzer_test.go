@@ -1370,7 +1370,7 @@ func Test_SelectOnUnnamedResultValue(t *testing.T) {
 		`    { name: test, path: test }`,
 		`  ],`,
 		`  selections: [`,
-		`    { name: y, origin: structDesc1 }`,
+		`    { name: y, origin: structDesc1, target: field1 }`,
 		`  ],`,
 		`  structDescs: [`,
 		`    { fields: [ 1 ] }`,
@@ -2257,7 +2257,7 @@ func parseExpr(t *testing.T, lines ...string) *testTool {
 	check.NoError(t).Require(err)
 
 	querier := querier.NewSimple(tt.info, tt.fSet)
-	tt.m = Analyze(logger.New(), querier, tt.proj, tt.curPkg, tt.baker, tt.conv, expr)
+	tt.m = Analyze(logger.New(), querier, tt.proj, tt.curPkg, tt.baker, tt.conv, nil, expr)
 	tt.proj.UpdateIndices(false)
 	return tt
 }
@@ -2277,7 +2277,7 @@ func parseDecl(t *testing.T, name string, lines ...string) *testTool {
 	check.NotNil(t).Name(`found name`).With(`name`, name).Assert(target)
 
 	querier := querier.NewSimple(tt.info, tt.fSet)
-	tt.m = Analyze(logger.New(), querier, tt.proj, tt.curPkg, tt.baker, tt.conv, target)
+	tt.m = Analyze(logger.New(), querier, tt.proj, tt.curPkg, tt.baker, tt.conv, nil, target)
 	tt.proj.UpdateIndices(false)
 	return tt
 }
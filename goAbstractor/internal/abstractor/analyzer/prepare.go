@@ -0,0 +1,218 @@
+package analyzer
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"strings"
+	"sync"
+
+	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/analyzer/accessor"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/analyzer/complexity"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/querier"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/progress"
+)
+
+// Prepared is the set of analysis results that only depend on the syntax
+// tree and type information of a node. These results do not create any
+// constructs so they can be calculated concurrently before the constructs
+// are created serially.
+type Prepared struct {
+	results map[ast.Node]prepared
+}
+
+type prepared struct {
+	cmplx complexity.Complexity
+	acc   accessor.Accessor
+}
+
+// preparable is a node to prepare with the name and position
+// of the declaration that the node is part of.
+type preparable struct {
+	node ast.Node
+	name string
+	pos  token.Pos
+}
+
+// failure is a recovered panic from preparing a declaration's node.
+type failure struct {
+	name string
+	pos  token.Pos
+	r    any
+}
+
+// preparedPackage is the results and failures from preparing
+// the package at the index in the packages being prepared.
+type preparedPackage struct {
+	index    int
+	results  map[ast.Node]prepared
+	failures []failure
+}
+
+// Prepare concurrently calculates the analysis results for all the function
+// declarations and value initializers in the given packages, using up to
+// the given number of workers.
+//
+// The optional context is checked before each package is prepared and
+// before each node in the package is analyzed. Once cancelled,
+// the context's error is panicked.
+//
+// A failure while preparing a declaration is recorded with the project's
+// diagnostics, once all the packages are prepared, in the order of the
+// packages and declarations. When tolerant, the other declarations are
+// still prepared, otherwise the first failure is panicked.
+//
+// Returns nil if the workers is one or less so that the analysis
+// is all performed serially during abstraction.
+func Prepare(ctx context.Context, prog *progress.Reporter, querier *querier.Querier, proj constructs.Project, workers int, srcs []*packages.Package) *Prepared {
+	if workers <= 1 {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	pkgs := make(chan int)
+	results := make(chan preparedPackage)
+	panics := make(chan any, workers)
+
+	wg := &sync.WaitGroup{}
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panics <- r
+					// Drain the remaining packages so the feeder isn't blocked.
+					for range pkgs {
+					}
+				}
+			}()
+			for index := range pkgs {
+				if err := ctx.Err(); err != nil {
+					panic(err)
+				}
+				pp := preparePackage(ctx, querier, srcs[index])
+				pp.index = index
+				results <- pp
+			}
+		}()
+	}
+
+	go func() {
+		for index := range srcs {
+			pkgs <- index
+		}
+		close(pkgs)
+		wg.Wait()
+		close(results)
+		close(panics)
+	}()
+
+	done, total := 0, len(srcs)
+	prog.Packages(diagnostics.Analyze, done, total)
+	p := &Prepared{results: map[ast.Node]prepared{}}
+	failures := make([][]failure, len(srcs))
+	for pp := range results {
+		for node, pr := range pp.results {
+			p.results[node] = pr
+		}
+		failures[pp.index] = pp.failures
+		done++
+		prog.Packages(diagnostics.Analyze, done, total)
+	}
+
+	if r := <-panics; r != nil {
+		panic(terror.RecoveredPanic(r))
+	}
+
+	// The failed nodes don't have results, so they are analyzed again
+	// while abstracting, where the failure is found with the same
+	// declaration name and position so isn't recorded twice.
+	for _, fs := range failures {
+		for _, f := range fs {
+			loc := proj.Locs().NewLoc(f.pos)
+			proj.Diagnostics().Tolerate(diagnostics.Analyze, f.name, loc, func() {
+				panic(f.r)
+			})
+		}
+	}
+	return p
+}
+
+func preparePackage(ctx context.Context, querier *querier.Querier, src *packages.Package) preparedPackage {
+	pp := preparedPackage{results: map[ast.Node]prepared{}}
+	if skipPackage(src.PkgPath) {
+		return pp
+	}
+
+	fSet := querier.FileSet()
+	for _, pa := range preparableNodes(src) {
+		if err := ctx.Err(); err != nil {
+			panic(err)
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					pp.failures = append(pp.failures, failure{name: pa.name, pos: pa.pos, r: r})
+				}
+			}()
+			pp.results[pa.node] = prepared{
+				cmplx: complexity.Calculate(nil, pa.node, fSet),
+				acc:   accessor.Calculate(nil, querier.Info(), pa.node),
+			}
+		}()
+	}
+	return pp
+}
+
+// preparableNodes gets the function declarations and value initializers
+// in the given package in the order they are defined. Each is named and
+// positioned by its declaration the same as when it is abstracted.
+func preparableNodes(src *packages.Package) []preparable {
+	nodes := []preparable{}
+	for _, f := range src.Syntax {
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				nodes = append(nodes, preparable{node: d, name: d.Name.Name, pos: d.Pos()})
+			case *ast.GenDecl:
+				if d.Tok != token.CONST && d.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range d.Specs {
+					if vs, ok := spec.(*ast.ValueSpec); ok {
+						name := valueSpecName(vs)
+						for _, value := range vs.Values {
+							nodes = append(nodes, preparable{node: value, name: name, pos: vs.Pos()})
+						}
+					}
+				}
+			}
+		}
+	}
+	return nodes
+}
+
+// valueSpecName gets the names of the values declared by the given spec.
+func valueSpecName(spec *ast.ValueSpec) string {
+	names := make([]string, len(spec.Names))
+	for i, name := range spec.Names {
+		names[i] = name.Name
+	}
+	return strings.Join(names, `, `)
+}
+
+func (p *Prepared) lookup(node ast.Node) (prepared, bool) {
+	if p == nil {
+		return prepared{}, false
+	}
+	pr, ok := p.results[node]
+	return pr, ok
+}
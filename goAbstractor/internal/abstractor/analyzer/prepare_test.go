@@ -0,0 +1,65 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/querier"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/project"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+)
+
+func Test_Prepare_Tolerant(t *testing.T) {
+	fSet := token.NewFileSet()
+	info := &types.Info{
+		Defs:  make(map[*ast.Ident]types.Object),
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	parse := func(name, code string) *packages.Package {
+		file, err := parser.ParseFile(fSet, name+`.go`, []byte(code), 0)
+		check.NoError(t).Require(err)
+		pkg, err := (&types.Config{}).Check(name, fSet, []*ast.File{file}, info)
+		check.NoError(t).Require(err)
+		return &packages.Package{PkgPath: name, Name: name, Types: pkg, Syntax: []*ast.File{file}}
+	}
+	foo := parse(`foo`, "package foo\nfunc A() int { return 1 }\nvar b, c = 2, 3\n")
+	bar := parse(`bar`, "package bar\nfunc D() {}\n")
+
+	// The initializer of c is removed so that preparing it fails,
+	// while the other declarations are still prepared.
+	spec := foo.Syntax[0].Decls[1].(*ast.GenDecl).Specs[0].(*ast.ValueSpec)
+	spec.Values[1] = nil
+
+	proj := project.New(locs.NewSet(fSet))
+	proj.Diagnostics().SetTolerant(true)
+	q := querier.NewSimple(info, fSet)
+	p := Prepare(nil, nil, q, proj, 2, []*packages.Package{foo, bar})
+
+	prepared := func(node ast.Node) bool {
+		_, ok := p.lookup(node)
+		return ok
+	}
+	check.True(t).Name(`A`).Assert(prepared(foo.Syntax[0].Decls[0]))
+	check.True(t).Name(`b`).Assert(prepared(spec.Values[0]))
+	check.True(t).Name(`D`).Assert(prepared(bar.Syntax[0].Decls[0]))
+
+	diags := proj.Diagnostics().Diagnostics()
+	check.Length(t, 1).Require(diags)
+	check.Equal(t, diagnostics.Analyze).Assert(diags[0].Phase)
+	check.Equal(t, `b, c`).Assert(diags[0].Name)
+	check.Equal(t, `foo.go:3:5`).Assert(diags[0].Loc.Position().String())
+
+	// When not tolerant, the failure is panicked.
+	proj = project.New(locs.NewSet(fSet))
+	check.MatchError(t, `node`).Panic(func() {
+		Prepare(nil, nil, q, proj, 2, []*packages.Package{foo, bar})
+	})
+}
@@ -117,59 +117,59 @@ func (ui *usagesImp) hasPending() bool {
 
 func (ui *usagesImp) setPendingConstruct(c constructs.Construct) {
 	ui.flushPendingToRead()
-	ui.log.Logf(`  - PendingCon: %v`, c)
+	ui.log.Debugf(`  - PendingCon: %v`, c)
 	ui.pending = c
 }
 
 func (ui *usagesImp) setPendingType(t types.Type) {
 	ui.flushPendingToRead()
-	ui.log.Logf(`  - PendingType: %v`, t)
+	ui.log.Debugf(`  - PendingType: %v`, t)
 
 	named, pointer := getNamed(t)
 	if named == nil {
-		ui.log.Logf(`    - no named`)
+		ui.log.Debugf(`    - no named`)
 		return
 	}
 
-	ui.log.Logf(`    - named.Obj: %v @ %v`, named.Obj(), ui.pos(named.Obj()))
+	ui.log.Debugf(`    - named.Obj: %v @ %v`, named.Obj(), ui.pos(named.Obj()))
 	if isLocal(ui.root, named.Obj()) {
-		ui.log.Logf(`    - local`)
+		ui.log.Debugf(`    - local`)
 		return
 	}
 
 	if pointer != nil {
 		ui.pending = ui.conv.ConvertType(pointer, named.Obj().Name())
-		ui.log.Logf(`    - converted ptr: %v`, ui.pending)
+		ui.log.Debugf(`    - converted ptr: %v`, ui.pending)
 		return
 	}
 
 	ui.pending = ui.conv.ConvertType(named, named.Obj().Name())
-	ui.log.Logf(`    - converted obj: %v`, ui.pending)
+	ui.log.Debugf(`    - converted obj: %v`, ui.pending)
 }
 
 func (ui *usagesImp) setPendingObject(o types.Object, instanceType []constructs.TypeDesc) {
 	ui.flushPendingToRead()
-	ui.log.Logf(`  - PendingObject: %v`, o)
+	ui.log.Debugf(`  - PendingObject: %v`, o)
 
 	if utils.IsNil(o) {
-		ui.log.Logf(`    + nil`)
+		ui.log.Debugf(`    + nil`)
 		return
 	}
 
 	if _, ok := o.(*types.Label); ok {
 		// Skip over labels
-		ui.log.Logf(`    + label`)
+		ui.log.Debugf(`    + label`)
 		return
 	}
 
 	if _, ok := o.(*types.PkgName); ok {
 		// Skip over package names
-		ui.log.Logf(`    + package name`)
+		ui.log.Debugf(`    + package name`)
 		return
 	}
 
 	if isLocal(ui.root, o) {
-		ui.log.Logf(`    - local: set type`)
+		ui.log.Debugf(`    - local: set type`)
 		ui.setPendingType(o.Type())
 		return
 	}
@@ -177,41 +177,47 @@ func (ui *usagesImp) setPendingObject(o types.Object, instanceType []constructs.
 	var nest constructs.NestType
 	var implicitTypes []constructs.TypeDesc
 	if inNest(ui.querier, ui.conv.Nest(), o) {
-		ui.log.Logf(`    - in nest: %v`, ui.conv.Nest())
+		ui.log.Debugf(`    - in nest: %v`, ui.conv.Nest())
 		nest = ui.conv.Nest()
 		implicitTypes = ui.conv.ImplicitTypes()
 	}
 
 	if tn, ok := o.(*types.TypeName); ok {
-		ui.log.Logf(`    - type name: %v: %v`, o, tn)
+		ui.log.Debugf(`    - type name: %v: %v`, o, tn)
 
 		pkgPath := getPkgPath(o)
 		if len(pkgPath) <= 0 {
 			if typ := ui.baker.TypeByName(o.Name()); !utils.IsNil(typ) {
-				ui.log.Logf(`      - built-in type: %v`, typ)
+				ui.log.Debugf(`      - built-in type: %v`, typ)
 				ui.setPendingConstruct(typ)
 				return
 			}
 
 			if basic, ok := o.Type().(*types.Basic); ok && basic.Kind() != types.Invalid {
-				ui.log.Logf(`      + basic: %v`, basic)
+				ui.log.Debugf(`      + basic: %v`, basic)
 				return
 			}
 
-			ui.log.Logf(`      + dump built-in`)
+			ui.log.Debugf(`      + dump built-in`)
 			return
 		}
 
 		typ, found := ui.proj.FindType(pkgPath, o.Name(), nest, implicitTypes, instanceType, true, false)
 		if found {
-			ui.log.Logf(`      + type found: %v`, typ)
+			ui.log.Debugf(`      + type found: %v`, typ)
 			ui.pending = typ
 			return
 		}
 
-		ui.log.Logf(`      - temp ref: %v`, o)
+		ui.log.Debugf(`      - temp ref: %v`, o)
+		realType := o.Type()
+		if len(implicitTypes) <= 0 && len(instanceType) <= 0 {
+			if declared := ui.querier.DeclaredType(tn); declared != nil {
+				realType = declared
+			}
+		}
 		ui.pending = ui.proj.NewTempReference(constructs.TempReferenceArgs{
-			RealType:      o.Type(),
+			RealType:      realType,
 			PackagePath:   pkgPath,
 			Name:          o.Name(),
 			Nest:          nest,
@@ -223,14 +229,14 @@ func (ui *usagesImp) setPendingObject(o types.Object, instanceType []constructs.
 	}
 
 	if v, ok := o.(*types.Var); ok {
-		ui.log.Logf(`    - type var: %v`, v)
+		ui.log.Debugf(`    - type var: %v`, v)
 		if v.IsField() {
 			if compLit := ui.compLits.Peek(); !utils.IsNil(compLit) && !utils.IsNil(compLit.Type) {
 				compType := ui.querier.GetType(compLit.Type)
-				ui.log.Logf(`      - field sel: %v => %v`, compType, v.Name())
+				ui.log.Debugf(`      - field sel: %v => %v`, compType, v.Name())
 				ui.setPendingType(compType)
 				if ui.hasPending() {
-					ui.log.Logf(`      - pending field selObj: %v`, ui.pending)
+					ui.log.Debugf(`      - pending field selObj: %v`, ui.pending)
 					ui.setPendingConstruct(ui.proj.NewSelection(constructs.SelectionArgs{
 						Name:   v.Name(),
 						Origin: ui.pending,
@@ -239,7 +245,7 @@ func (ui *usagesImp) setPendingObject(o types.Object, instanceType []constructs.
 				}
 			}
 
-			ui.log.Logf(`      - field without recv: %v`, v)
+			ui.log.Debugf(`      - field without recv: %v`, v)
 			ui.setPendingType(v.Type())
 			return
 		}
@@ -252,15 +258,21 @@ func (ui *usagesImp) setPendingObject(o types.Object, instanceType []constructs.
 	pkgPath := getPkgPath(o)
 	typ, found := ui.proj.FindDecl(pkgPath, o.Name(), nest, implicitTypes, instanceType, true, false)
 	if found {
-		ui.log.Logf(`      + decl found: %v`, typ)
+		ui.log.Debugf(`      + decl found: %v`, typ)
 		ui.pending = typ
 		return
 	}
 
+	// The declarations in the runtime and syscall packages are ignored
+	// when those packages aren't abstracted. Whether the package has been
+	// abstracted yet isn't checked so that the usages don't depend on the
+	// order the packages are abstracted in.
 	switch pkgPath {
 	case `runtime`, `syscall`:
-		ui.log.Logf(`    - ignoring decl: %v`, o)
-		return
+		if !ui.querier.IsAbstracted(pkgPath) {
+			ui.log.Debugf(`    - ignoring decl: %v`, o)
+			return
+		}
 	}
 
 	var funcType *types.Func
@@ -268,7 +280,7 @@ func (ui *usagesImp) setPendingObject(o types.Object, instanceType []constructs.
 		funcType = f
 	}
 
-	ui.log.Logf(`    - temp decl ref: %v`, o)
+	ui.log.Debugf(`    - temp decl ref: %v`, o)
 	ui.pending = ui.proj.NewTempDeclRef(constructs.TempDeclRefArgs{
 		FuncType:      funcType,
 		PackagePath:   pkgPath,
@@ -311,7 +323,7 @@ func (ui *usagesImp) flushPendingToInvoke() {
 // addRead adds the given construct as a read usage.
 func (ui *usagesImp) addRead(c constructs.Construct) {
 	if !utils.IsNil(c) {
-		ui.log.Logf(`  + Reads: %v`, c)
+		ui.log.Debugf(`  + Reads: %v`, c)
 		ui.usages.Reads.Add(c)
 	}
 }
@@ -319,7 +331,7 @@ func (ui *usagesImp) addRead(c constructs.Construct) {
 // addWrite adds the given construct as a write usage.
 func (ui *usagesImp) addWrite(c constructs.Construct, sideEffect bool) {
 	if !utils.IsNil(c) {
-		ui.log.Logf(`  + Write: %v`, c)
+		ui.log.Debugf(`  + Write: %v`, c)
 		ui.usages.Writes.Add(c)
 		if sideEffect {
 			ui.usages.SideEffect = true
@@ -330,7 +342,7 @@ func (ui *usagesImp) addWrite(c constructs.Construct, sideEffect bool) {
 // addInvoke adds the given construct as an invoke usage.
 func (ui *usagesImp) addInvoke(c constructs.Construct) {
 	if !utils.IsNil(c) {
-		ui.log.Logf(`  + Invoke: %v`, c)
+		ui.log.Debugf(`  + Invoke: %v`, c)
 		ui.usages.Invokes.Add(c)
 	}
 }
@@ -414,7 +426,7 @@ func (ui *usagesImp) processNode(node ast.Node) {
 			// which nodes do not have custom handling on them yet.
 			// Not all nodes need custom handling but a bug might indicate
 			// one that doesn't have custom handling probably should.
-			// ui.log.Logf(`usagesImp.processNode unhandled (%[1]T) %[1]v`, t)
+			// ui.log.Debugf(`usagesImp.processNode unhandled (%[1]T) %[1]v`, t)
 			return true
 		}
 		return false
@@ -481,7 +493,7 @@ func (ui *usagesImp) processCall(call *ast.CallExpr) {
 }
 
 func (ui *usagesImp) processCompositeLit(comp *ast.CompositeLit) {
-	ui.log.Logf(`>>> processCompositeLit: %v @ %s`, comp, ui.pos(comp))
+	ui.log.Debugf(`>>> processCompositeLit: %v @ %s`, comp, ui.pos(comp))
 	ui.compLits.Push(comp)
 	defer ui.compLits.Pop()
 
@@ -497,11 +509,11 @@ func (ui *usagesImp) processCompositeLit(comp *ast.CompositeLit) {
 }
 
 func (ui *usagesImp) processIdent(id *ast.Ident) {
-	ui.log.Logf(`>>> processIdent: %v @ %s`, id, ui.pos(id))
+	ui.log.Debugf(`>>> processIdent: %v @ %s`, id, ui.pos(id))
 
 	// Check if this identifier is part of a definition.
 	if def, ok := ui.querier.Info().Defs[id]; ok {
-		ui.log.Logf(`  > processIdent: def object: %v`, def)
+		ui.log.Debugf(`  > processIdent: def object: %v`, def)
 		ui.setPendingObject(def, nil)
 		ui.addWrite(ui.pending, ui.pendingSE)
 		return
@@ -510,7 +522,7 @@ func (ui *usagesImp) processIdent(id *ast.Ident) {
 	// Check if the identifier is being used.
 	obj, ok := ui.querier.Info().Uses[id]
 	if !ok {
-		ui.log.Logf(`  > processIdent: no uses`)
+		ui.log.Debugf(`  > processIdent: no uses`)
 		return
 	}
 
@@ -518,14 +530,14 @@ func (ui *usagesImp) processIdent(id *ast.Ident) {
 	// https://pkg.go.dev/builtin#pkg-constants
 	switch obj.Id() {
 	case `_.true`, `_.false`, `_.nil`, `_.iota`:
-		ui.log.Logf(`  > processIdent: build-in constants: %v`, obj.Id())
+		ui.log.Debugf(`  > processIdent: build-in constants: %v`, obj.Id())
 		return
 	}
 
 	// Return built-in type.
 	if obj.Pkg() == nil {
 		if typ := ui.baker.TypeByName(obj.Name()); !utils.IsNil(typ) {
-			ui.log.Logf(`  > processIdent: build-in type: %v`, typ)
+			ui.log.Debugf(`  > processIdent: build-in type: %v`, typ)
 			ui.setPendingConstruct(typ)
 			return
 		}
@@ -539,7 +551,7 @@ func (ui *usagesImp) processIdent(id *ast.Ident) {
 		instType = ui.conv.ConvertInstanceTypes(itList, obj.Name())
 	}
 
-	ui.log.Logf(`  > processIdent: object: %v`, obj)
+	ui.log.Debugf(`  > processIdent: object: %v`, obj)
 	ui.setPendingObject(obj, instType)
 }
 
@@ -563,6 +575,14 @@ func (ui *usagesImp) processIndex(expr *ast.IndexExpr) {
 	ui.processNode(expr.Index)
 	ui.flushPendingToRead()
 
+	// Check for an explicit instantiation, e.g. `Max[float64]`.
+	// The instance is left pending so it can be invoked, like when the
+	// instance types are inferred from the arguments, e.g. `Max(1.5, 2)`.
+	if tv, ok := ui.querier.Info().Types[expr.Index]; ok && tv.IsType() {
+		ui.processNode(expr.X)
+		return
+	}
+
 	// Process target of indexing, e.g. `cats[ ⋯ ]`
 	// Add to read but leave in pending.
 	ui.processNode(expr.X)
@@ -597,7 +617,7 @@ func (ui *usagesImp) processIndexList(expr *ast.IndexListExpr) {
 }
 
 func (ui *usagesImp) processKeyValue(kv *ast.KeyValueExpr) {
-	ui.log.Logf(`>>> processKeyValue: %v @ %s`, kv, ui.pos(kv))
+	ui.log.Debugf(`>>> processKeyValue: %v @ %s`, kv, ui.pos(kv))
 	ui.processNode(kv.Key)
 	ui.flushPendingToWrite()
 
@@ -606,7 +626,7 @@ func (ui *usagesImp) processKeyValue(kv *ast.KeyValueExpr) {
 }
 
 func (ui *usagesImp) processRange(r *ast.RangeStmt) {
-	ui.log.Logf(`>>> processRange: %v @ %s`, r, ui.pos(r))
+	ui.log.Debugf(`>>> processRange: %v @ %s`, r, ui.pos(r))
 	ui.processNode(r.Key)
 	ui.flushPendingToWrite()
 
@@ -633,11 +653,11 @@ func (ui *usagesImp) processSend(send *ast.SendStmt) {
 }
 
 func (ui *usagesImp) processSelector(sel *ast.SelectorExpr) {
-	ui.log.Logf(`>>> processSelector: %v @ %s`, sel, ui.pos(sel))
+	ui.log.Debugf(`>>> processSelector: %v @ %s`, sel, ui.pos(sel))
 	ui.processNode(sel.X)
-	ui.log.Logf(`>>> processSelector.X: %v`, ui.pending)
+	ui.log.Debugf(`>>> processSelector.X: %v`, ui.pending)
 	if ui.hasPending() {
-		ui.log.Logf(`  > pending sel.X: %v`, ui.pending)
+		ui.log.Debugf(`  > pending sel.X: %v`, ui.pending)
 		ui.setPendingConstruct(ui.proj.NewSelection(constructs.SelectionArgs{
 			Name:   sel.Sel.Name,
 			Origin: ui.pending,
@@ -647,16 +667,17 @@ func (ui *usagesImp) processSelector(sel *ast.SelectorExpr) {
 
 	selObj, ok := ui.querier.Info().Selections[sel]
 	if !ok {
-		ui.log.Logf(`  > no selection info: %v`, sel)
+		ui.log.Debugf(`  > no selection info: %v`, sel)
+		ui.processQualified(sel.Sel)
 		return
 	}
-	ui.log.Logf(`  > selObj: %v`, selObj)
+	ui.log.Debugf(`  > selObj: %v`, selObj)
 
 	if !isLocal(ui.root, selObj.Obj()) {
-		ui.log.Logf(`  > non-local selObj: %v at %v`, selObj.Obj(), ui.pos(selObj.Obj()))
+		ui.log.Debugf(`  > non-local selObj: %v at %v`, selObj.Obj(), ui.pos(selObj.Obj()))
 		ui.setPendingConstruct(ui.conv.ConvertType(selObj.Recv(), selObj.Recv().String()))
 		if ui.hasPending() {
-			ui.log.Logf(`  > pending selObj: %v`, ui.pending)
+			ui.log.Debugf(`  > pending selObj: %v`, ui.pending)
 			ui.setPendingConstruct(ui.proj.NewSelection(constructs.SelectionArgs{
 				Name:   sel.Sel.Name,
 				Origin: ui.pending,
@@ -665,12 +686,26 @@ func (ui *usagesImp) processSelector(sel *ast.SelectorExpr) {
 		}
 	}
 
-	ui.log.Logf(`  > selection fallback: %v`, selObj)
+	ui.log.Debugf(`  > selection fallback: %v`, selObj)
 	ui.setPendingType(selObj.Recv())
 	ui.flushPendingToRead()
 	ui.setPendingType(selObj.Obj().Type())
 }
 
+// processQualified processes the identifier of a qualified identifier,
+// e.g. `Println` in `fmt.Println`, which has no selection information
+// since the package name is skipped. Only functions, variables, and
+// constants are processed since qualified types are read from the type
+// information. A generic function, e.g. `slices.Max` or `slices.Max[[]int]`,
+// is processed with the instance types that were explicitly given or
+// inferred from the arguments.
+func (ui *usagesImp) processQualified(id *ast.Ident) {
+	switch ui.querier.Info().Uses[id].(type) {
+	case *types.Func, *types.Var, *types.Const:
+		ui.processIdent(id)
+	}
+}
+
 func (ui *usagesImp) processTypeAssert(exp *ast.TypeAssertExpr) {
 	ui.processNode(exp.X)
 	ui.flushPendingToRead()
@@ -8,6 +8,7 @@ package baker
 
 import (
 	"go/types"
+	"maps"
 
 	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
 	"github.com/Snow-Gremlin/goToolbox/utils"
@@ -33,6 +34,10 @@ type Baker interface {
 	BakeError() constructs.InterfaceDecl
 	BakeComparableAbstract() constructs.Abstract
 	BakeComparable() constructs.InterfaceDecl
+
+	// RemoveRolledBack forgets any of the given rolled back constructs
+	// so that they will be baked again if they are needed.
+	RemoveRolledBack(removed map[constructs.Construct]bool)
 }
 
 type bakerImp struct {
@@ -65,6 +70,13 @@ func bakeOnce[T any](b *bakerImp, key string, create func() T) T {
 	return t
 }
 
+func (b *bakerImp) RemoveRolledBack(removed map[constructs.Construct]bool) {
+	maps.DeleteFunc(b.baked, func(_ string, baked any) bool {
+		c, ok := baked.(constructs.Construct)
+		return ok && removed[c]
+	})
+}
+
 // TypeByName gets a type by name or returns nil.
 func (b *bakerImp) TypeByName(name string) constructs.TypeDecl {
 	switch name {
@@ -77,7 +77,7 @@ func (c *convImp) ConvertType(t types.Type, context string) constructs.TypeDesc
 	c.context = context
 	t2 := cache(c, t, c.convertType)
 	c.tpSeen = nil
-	c.log.Logf(`└─ result: %v`, t2)
+	c.log.Debugf(`└─ result: %v`, t2)
 	return t2
 }
 
@@ -87,7 +87,7 @@ func (c *convImp) ConvertSignature(t *types.Signature, context string) construct
 	c.context = context
 	t2 := cache(c, t, c.convertSignature)
 	c.tpSeen = nil
-	c.log.Logf(`└─ result: %v`, t2)
+	c.log.Debugf(`└─ result: %v`, t2)
 	return t2
 }
 
@@ -97,7 +97,7 @@ func (c *convImp) ConvertInstanceTypes(t *types.TypeList, context string) []cons
 	c.context = context
 	t2 := cache(c, t, c.convertInstanceTypes)
 	c.tpSeen = nil
-	c.log.Logf(`└─ result: %v`, t2)
+	c.log.Debugf(`└─ result: %v`, t2)
 	return t2
 }
 
@@ -112,7 +112,14 @@ func cache[T, R any](c *convImp, t T, handle func(T) R) R {
 			WithType(`expected`, utils.Zero[R]()))
 	}
 	t2 := handle(t)
-	c.typeCache[t] = t2
+
+	// Don't cache a temporary reference since the type it refers to may be
+	// declared later, e.g. a package referencing a type in a package that
+	// hasn't been abstracted yet. Otherwise the package that declares the
+	// type would get the reference instead of its own declaration.
+	if _, isRef := any(t2).(constructs.TempReference); !isRef {
+		c.typeCache[t] = t2
+	}
 	return t2
 }
 
@@ -281,8 +288,17 @@ func (c *convImp) convertNamed(t *types.Named) constructs.TypeDesc {
 	typ, found := c.proj.FindType(pkgPath, name, nest, implicitTypes, instanceTypes, true, false)
 	if !found {
 		// Otherwise, create a temporary reference that will be filled later.
+		// A reference to a declaration, instead of an instance, has the real
+		// type of the declaration so that anything built from the reference
+		// is the same as if the declaration had already been abstracted.
+		var realType types.Type = t
+		if len(implicitTypes) <= 0 && len(instanceTypes) <= 0 {
+			if declared := c.querier.DeclaredType(t.Obj()); declared != nil {
+				realType = declared
+			}
+		}
 		return c.proj.NewTempReference(constructs.TempReferenceArgs{
-			RealType:      t,
+			RealType:      realType,
 			PackagePath:   pkgPath,
 			Name:          name,
 			Nest:          nest,
@@ -5,6 +5,8 @@ import (
 	"go/token"
 	"go/types"
 	"maps"
+	"strings"
+	"sync"
 
 	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
 	"golang.org/x/tools/go/packages"
@@ -12,10 +14,26 @@ import (
 
 type Querier struct {
 	packages []*packages.Package
+	skipped  map[*packages.Package]bool
+	roots    map[string]bool
+	paths    map[string]bool
+	genFiles map[string]bool
 	info     *types.Info
 	fSet     *token.FileSet
 	ctx      *types.Context
 	fnScopes map[*types.Scope]*types.Func
+
+	// defs are the defined objects keyed by the position they are defined
+	// at and the functions keyed by their signature. These are only
+	// indexed when first needed since most runs never look them up.
+	defsOnce sync.Once
+	defs     map[token.Pos]types.Object
+	funcs    map[*types.Signature]*types.Func
+
+	// declared are the types declared by each type specification keyed
+	// by the type name. These are only indexed when first needed.
+	declaredOnce sync.Once
+	declared     map[*types.TypeName]types.Type
 }
 
 func New(pkgs []*packages.Package) *Querier {
@@ -36,12 +54,24 @@ func New(pkgs []*packages.Package) *Querier {
 
 	q := &Querier{
 		packages: pkgs,
+		skipped:  skippedVariants(pkgs),
+		roots:    map[string]bool{},
+		paths:    map[string]bool{},
+		genFiles: map[string]bool{},
 		info:     info,
 		fSet:     pkgs[0].Fset,
 		ctx:      types.NewContext(),
 		fnScopes: map[*types.Scope]*types.Func{},
 	}
-	q.ForeachPackage(q.joinInfo)
+	packages.Visit(pkgs, nil, q.joinInfo)
+	for _, pkg := range pkgs {
+		if !isTestMain(pkg) {
+			q.roots[pkg.PkgPath] = true
+		}
+	}
+	q.ForeachPackage(func(pkg *packages.Package) {
+		q.paths[pkg.PkgPath] = true
+	})
 	for _, obj := range q.info.Defs {
 		if fn, ok := obj.(*types.Func); ok {
 			q.fnScopes[fn.Scope()] = fn
@@ -69,6 +99,12 @@ func (q *Querier) joinInfo(p *packages.Package) {
 	maps.Insert(q.info.Selections, maps.All(src.Selections))
 	maps.Insert(q.info.Types, maps.All(src.Types))
 	maps.Insert(q.info.Uses, maps.All(src.Uses))
+
+	for _, f := range p.Syntax {
+		if ast.IsGenerated(f) {
+			q.genFiles[q.fSet.Position(f.FileStart).Filename] = true
+		}
+	}
 }
 
 func (q *Querier) Info() *types.Info                { return q.info }
@@ -83,13 +119,129 @@ func getPos(p any) token.Pos {
 	return token.NoPos
 }
 
+// ForeachPackage calls the given handle for each package and dependency
+// that should be abstracted. When tests were loaded, only one variant
+// of each package is handled.
 func (q *Querier) ForeachPackage(handle func(*packages.Package)) {
-	packages.Visit(q.packages, func(pkg *packages.Package) bool {
+	foreachPackage(q.packages, q.skipped, handle)
+}
+
+// ForeachPackageIn calls the given handle for each of the given packages
+// and their dependencies that should be abstracted. This is used when the
+// packages for each build configuration are abstracted separately.
+func (q *Querier) ForeachPackageIn(pkgs []*packages.Package, handle func(*packages.Package)) {
+	foreachPackage(pkgs, skippedVariants(pkgs), handle)
+}
+
+// PackageCount gets the number of packages that ForeachPackage will visit.
+func (q *Querier) PackageCount() int {
+	count := 0
+	q.ForeachPackage(func(*packages.Package) { count++ })
+	return count
+}
+
+// PackageCountIn gets the number of packages that ForeachPackageIn
+// will visit for the given packages.
+func (q *Querier) PackageCountIn(pkgs []*packages.Package) int {
+	count := 0
+	q.ForeachPackageIn(pkgs, func(*packages.Package) { count++ })
+	return count
+}
+
+// foreachPackage visits the packages and their dependencies. The skipped
+// packages aren't descended into, so that the dependencies only needed by
+// a skipped package, e.g. the testing packages imported by a generated
+// test main package, aren't abstracted.
+func foreachPackage(pkgs []*packages.Package, skipped map[*packages.Package]bool, handle func(*packages.Package)) {
+	packages.Visit(pkgs, func(pkg *packages.Package) bool {
+		if skipped[pkg] {
+			return false
+		}
 		handle(pkg)
 		return true
 	}, nil)
 }
 
+// skippedVariants determines which packages should not be abstracted.
+//
+// When tests are loaded, a package may be loaded both as is and as a
+// variant that is recompiled with its test files, e.g. `foo` and
+// `foo [foo.test]`. Only one variant for each package path is abstracted,
+// preferring the variant with the test files since it contains everything
+// the package has, then the package as is. The generated test main
+// packages, e.g. `foo.test`, are always skipped.
+func skippedVariants(pkgs []*packages.Package) map[*packages.Package]bool {
+	skipped := map[*packages.Package]bool{}
+	chosen := map[string]*packages.Package{}
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if isTestMain(pkg) {
+			skipped[pkg] = true
+			return
+		}
+		prior, has := chosen[pkg.PkgPath]
+		if !has {
+			chosen[pkg.PkgPath] = pkg
+			return
+		}
+		if betterVariant(pkg, prior) {
+			skipped[prior] = true
+			chosen[pkg.PkgPath] = pkg
+			return
+		}
+		skipped[pkg] = true
+	})
+	return skipped
+}
+
+// isTestMain determines if the given package is a generated test main.
+func isTestMain(pkg *packages.Package) bool {
+	return pkg.Name == `main` && strings.HasSuffix(pkg.ID, `.test`)
+}
+
+// betterVariant determines if package a should be abstracted over package b
+// where both are variants of the same package.
+func betterVariant(a, b *packages.Package) bool {
+	rank := func(pkg *packages.Package) int {
+		switch {
+		case pkg.ForTest == pkg.PkgPath:
+			return 0
+		case pkg.ID == pkg.PkgPath:
+			return 1
+		default:
+			return 2
+		}
+	}
+	if aRank, bRank := rank(a), rank(b); aRank != bRank {
+		return aRank < bRank
+	}
+	return a.ID < b.ID
+}
+
+// IsRoot determines if the given package, or another variant of the same
+// package, was one of the packages that was read, as opposed to only
+// being read because it was a dependency of the read packages.
+func (q *Querier) IsRoot(pkg *packages.Package) bool {
+	return q.roots[pkg.PkgPath]
+}
+
+// IsAbstracted determines if a variant of the package with the given path
+// is one of the packages that are abstracted, i.e. one of the read packages
+// or one of their dependencies, regardless of if it has been abstracted yet.
+func (q *Querier) IsAbstracted(pkgPath string) bool {
+	return q.paths[pkgPath]
+}
+
+// InTestFile determines if the given position is in a test file.
+func (q *Querier) InTestFile(pos token.Pos) bool {
+	return strings.HasSuffix(q.fSet.Position(pos).Filename, `_test.go`)
+}
+
+// InGeneratedFile determines if the given position is in a file with
+// the standard `// Code generated ... DO NOT EDIT.` header comment.
+func (q *Querier) InGeneratedFile(pos token.Pos) bool {
+	return q.genFiles[q.fSet.Position(pos).Filename]
+}
+
 func (q *Querier) GetType(e ast.Expr) types.Type {
 	if tv, has := q.info.Types[e]; has {
 		return tv.Type
@@ -108,6 +260,61 @@ func (q *Querier) GetDef(id *ast.Ident) types.Object {
 		With(`pos`, q.Pos(getPos(id))))
 }
 
+// ObjectAt gets the object defined by the identifier at the given position
+// or nil if no object is defined there.
+func (q *Querier) ObjectAt(pos token.Pos) types.Object {
+	q.indexDefs()
+	return q.defs[pos]
+}
+
+// FuncOf gets the defined function or method with the given signature
+// or nil if the signature isn't the signature of a defined function.
+func (q *Querier) FuncOf(sig *types.Signature) *types.Func {
+	q.indexDefs()
+	return q.funcs[sig]
+}
+
+func (q *Querier) indexDefs() {
+	q.defsOnce.Do(func() {
+		q.defs = make(map[token.Pos]types.Object, len(q.info.Defs))
+		q.funcs = map[*types.Signature]*types.Func{}
+		for id, obj := range q.info.Defs {
+			if obj == nil {
+				continue
+			}
+			q.defs[id.Pos()] = obj
+			if fn, ok := obj.(*types.Func); ok {
+				q.funcs[fn.Signature()] = fn
+			}
+		}
+	})
+}
+
+// DeclaredType gets the type given in the type specification that declares
+// the given type name, e.g. `int64` for `type Duration int64`, which is the
+// real type of the declaration abstracted from that specification.
+// Returns nil if the type name isn't declared in any of the packages.
+func (q *Querier) DeclaredType(tn *types.TypeName) types.Type {
+	q.declaredOnce.Do(func() {
+		q.declared = map[*types.TypeName]types.Type{}
+		packages.Visit(q.packages, nil, func(p *packages.Package) {
+			for _, f := range p.Syntax {
+				ast.Inspect(f, func(n ast.Node) bool {
+					if spec, ok := n.(*ast.TypeSpec); ok {
+						name, ok1 := q.info.Defs[spec.Name].(*types.TypeName)
+						tv, ok2 := q.info.Types[spec.Type]
+						if ok1 && ok2 {
+							q.declared[name] = tv.Type
+						}
+					}
+					return true
+				})
+			}
+		})
+	})
+	return q.declared[tn]
+}
+
 func (q *Querier) NestingFunc(obj types.Object) *types.Func {
 	if obj == nil {
 		return nil
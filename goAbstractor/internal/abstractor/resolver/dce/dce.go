@@ -1,39 +1,83 @@
 package dce
 
 import (
+	"maps"
+	"slices"
+
 	"github.com/Snow-Gremlin/goToolbox/collections"
 	"github.com/Snow-Gremlin/goToolbox/collections/sortedSet"
 	"github.com/Snow-Gremlin/goToolbox/comp"
+	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
 	"github.com/Snow-Gremlin/goToolbox/utils"
 
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/assert"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/kind"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
+)
+
+// Reasons that a construct is a root of the dead-code elimination.
+const (
+	ReasonEntryPoint = `entry point`
+	ReasonMain       = `main`
+	ReasonInit       = `init`
+	ReasonSideEffect = `side effect`
+	ReasonTest       = `test`
+	ReasonLibrary    = `exported by entry point`
+	ReasonExported   = `exported API`
+	ReasonSymbolList = `symbol list`
+	ReasonLinkname   = `//go:linkname`
+	ReasonExport     = `//export`
+	ReasonReflection = `reflection registration`
 )
 
-func DeadCodeElimination(proj constructs.Project) {
+// Roots is the policy for the roots of the dead-code elimination
+// in addition to the entry points, init functions, and tests.
+type Roots struct {
+	// Exported are the paths of the packages whose exported API,
+	// including the exported methods on exported types, are roots.
+	Exported []string
+
+	// Symbols are the qualified names of the declarations that are roots
+	// mapped to the reason they are roots. A qualified name is the package
+	// path and name, e.g. `example.com/foo.Bar`, with the receiver for
+	// a method, e.g. `example.com/foo.Bar.Baz`.
+	//
+	// When a type is a root, the exported methods on it are also roots
+	// since they may be called via reflection.
+	Symbols map[string]string
+}
+
+func DeadCodeElimination(proj constructs.Project, roots Roots) {
 	d := &dce{
 		proj:    proj,
 		pending: sortedSet.New(comp.ComparableComparer[constructs.Construct]()),
 	}
 
 	d.primeAlive()
+	d.primeAliveForRoots(roots)
 
+	// Pending constructs are marked alive when they are pended
+	// so that they are only pended once.
 	for !d.pending.Empty() {
-		if c := d.pending.TakeFirst(); !c.Alive() {
-			c.SetAlive(true)
-			d.updateAlive(c)
-		}
+		d.cur = d.pending.TakeFirst()
+		constructs.Tolerate(proj, diagnostics.DeadCode, d.cur, func() {
+			d.updateAlive(d.cur)
+		})
 	}
 }
 
 type dce struct {
 	proj    constructs.Project
 	pending collections.SortedSet[constructs.Construct]
+	cur     constructs.Construct
 }
 
-func (d *dce) forcePend(c constructs.Construct) {
+func (d *dce) forcePend(c constructs.Construct, reason string) {
 	if !utils.IsNil(c) {
+		if !c.Alive() || len(c.AliveReason()) <= 0 {
+			c.SetAliveReason(reason, nil)
+		}
 		c.SetAlive(true)
 		d.pending.Add(c)
 	}
@@ -42,10 +86,25 @@ func (d *dce) forcePend(c constructs.Construct) {
 func (d *dce) pend(c constructs.Construct) {
 	if !utils.IsNil(c) && !c.Alive() {
 		c.SetAlive(true)
+		c.SetAliveReason(``, d.aliveBy())
 		d.pending.Add(c)
 	}
 }
 
+// aliveBy gets the declaration or package that is keeping the current
+// construct alive, skipping over any constructs between them,
+// e.g. the metrics of a method that invokes another method.
+func (d *dce) aliveBy() constructs.Construct {
+	switch d.cur.(type) {
+	case constructs.Declaration, constructs.Package:
+		return d.cur
+	}
+	if by := d.cur.AliveBy(); !utils.IsNil(by) {
+		return by
+	}
+	return d.cur
+}
+
 func pendSlice[T constructs.Construct](d *dce, cs []T) {
 	for _, c := range cs {
 		d.pend(c)
@@ -59,67 +118,176 @@ func pendSet[T constructs.Construct](d *dce, c collections.ReadonlySortedSet[T])
 }
 
 func (d *dce) primeAlive() {
-	entryPkg := d.proj.EntryPoint()
-	assert.ArgNotNil(`entry point package`, entryPkg)
-	d.forcePend(entryPkg)
+	entryPkgs := d.proj.EntryPoints()
+	assert.ArgNotEmpty(`entry point packages`, entryPkgs)
 	d.primeAliveGeneral()
 
-	// Since this abstractor doesn't currently specify for tests,
-	// if there are any tester functions, just make them alive.
+	// Any tester functions are entry points for the tests,
+	// so if there are any tester functions, make them alive.
 	d.primeAliveForTests()
 
-	// Check if the entry package has the main method.
-	if entryPkg.Name() == `main` {
-		main, ok := entryPkg.Methods().Enumerate().
-			Where(func(m constructs.Method) bool { return m.IsMain() }).
-			First()
-		if ok {
+	// Each module that was read, e.g. each module in a workspace,
+	// is either a program or a library. The main methods are the roots
+	// of a program, while the exported API of every package read from
+	// a library, a module without a main method, is a root.
+	// A package that isn't in a module is its own program or library.
+	programs := map[string]bool{}
+	for _, entryPkg := range entryPkgs {
+		if mainMethod(entryPkg) != nil {
+			programs[moduleKey(entryPkg)] = true
+		}
+	}
+	for _, entryPkg := range entryPkgs {
+		d.forcePend(entryPkg, ReasonEntryPoint)
+		if main := mainMethod(entryPkg); main != nil {
 			d.primeAliveForMain(main)
-			return
+		} else if !programs[moduleKey(entryPkg)] {
+			d.primeAliveForLibrary(entryPkg)
 		}
 	}
+}
+
+// mainMethod gets the main method of the given package
+// or nil if the package isn't a main package with a main method.
+func mainMethod(pkg constructs.Package) constructs.Method {
+	if pkg.Name() != `main` {
+		return nil
+	}
+	main, _ := pkg.Methods().Enumerate().
+		Where(func(m constructs.Method) bool { return m.IsMain() }).
+		First()
+	return main
+}
 
-	// If no main has been found, treat this like a library.
-	d.primeAliveForLibrary(entryPkg)
+// moduleKey gets the key for the module the given package is in,
+// or a key for the package itself if it isn't in a module.
+func moduleKey(pkg constructs.Package) string {
+	if module := pkg.Module(); len(module) > 0 {
+		return `module ` + module
+	}
+	return `package ` + pkg.Path()
 }
 
 func (d *dce) primeAliveGeneral() {
 	d.proj.Methods().Enumerate().
 		Where(func(m constructs.Method) bool { return m.IsInit() }).
-		Foreach(func(m constructs.Method) { d.forcePend(m) })
+		Foreach(func(m constructs.Method) { d.forcePend(m, ReasonInit) })
 
 	d.proj.Values().Enumerate().
 		Where(func(v constructs.Value) bool { return v.HasSideEffect() }).
-		Foreach(func(v constructs.Value) { d.forcePend(v) })
+		Foreach(func(v constructs.Value) { d.forcePend(v, ReasonSideEffect) })
 }
 
 func (d *dce) primeAliveForTests() {
 	d.proj.Methods().Enumerate().
 		Where(func(m constructs.Method) bool { return m.IsTester() }).
-		Foreach(func(m constructs.Method) { d.forcePend(m) })
+		Foreach(func(m constructs.Method) { d.forcePend(m, ReasonTest) })
 }
 
 func (d *dce) primeAliveForMain(main constructs.Method) {
-	d.forcePend(main)
+	d.forcePend(main, ReasonMain)
 }
 
 func (d *dce) primeAliveForLibrary(entryPkg constructs.Package) {
 	d.primeAliveGeneral()
 	entryPkg.InterfaceDecls().Enumerate().
 		Where(func(it constructs.InterfaceDecl) bool { return it.Exported() }).
-		Foreach(func(it constructs.InterfaceDecl) { d.forcePend(it) })
+		Foreach(func(it constructs.InterfaceDecl) { d.forcePend(it, ReasonLibrary) })
 
 	entryPkg.Methods().Enumerate().
 		Where(func(m constructs.Method) bool { return !m.HasReceiver() && m.Exported() }).
-		Foreach(func(m constructs.Method) { d.forcePend(m) })
+		Foreach(func(m constructs.Method) { d.forcePend(m, ReasonLibrary) })
 
 	entryPkg.Objects().Enumerate().
 		Where(func(obj constructs.Object) bool { return obj.Exported() }).
-		Foreach(func(obj constructs.Object) { d.forcePend(obj) })
+		Foreach(func(obj constructs.Object) { d.forcePend(obj, ReasonLibrary) })
 
 	entryPkg.Values().Enumerate().
 		Where(func(v constructs.Value) bool { return v.Exported() }).
-		Foreach(func(v constructs.Value) { d.forcePend(v) })
+		Foreach(func(v constructs.Value) { d.forcePend(v, ReasonLibrary) })
+}
+
+func (d *dce) primeAliveForRoots(roots Roots) {
+	if len(roots.Exported) > 0 {
+		d.proj.Packages().Enumerate().
+			Where(func(pkg constructs.Package) bool { return slices.Contains(roots.Exported, pkg.Path()) }).
+			Foreach(d.primeAliveForExported)
+	}
+
+	if len(roots.Symbols) > 0 {
+		decls := d.declarationsBySymbol()
+		for _, symbol := range slices.Sorted(maps.Keys(roots.Symbols)) {
+			reason := roots.Symbols[symbol]
+			found := decls[symbol]
+			for _, decl := range found {
+				d.primeAliveForDecl(decl, reason)
+			}
+			if len(found) <= 0 && reason == ReasonSymbolList {
+				d.proj.Diagnostics().Add(diagnostics.Diagnostic{
+					Phase: diagnostics.DeadCode,
+					Name:  symbol,
+					Err:   terror.New(`root symbol was not found`),
+				})
+			}
+		}
+	}
+}
+
+func (d *dce) primeAliveForExported(pkg constructs.Package) {
+	d.forcePend(pkg, ReasonExported)
+	pkg.InterfaceDecls().Enumerate().
+		Where(func(it constructs.InterfaceDecl) bool { return it.Exported() }).
+		Foreach(func(it constructs.InterfaceDecl) { d.forcePend(it, ReasonExported) })
+
+	pkg.Methods().Enumerate().
+		Where(func(m constructs.Method) bool {
+			if !m.HasReceiver() {
+				return m.Exported()
+			}
+			recv := m.Receiver()
+			return m.Exported() && !utils.IsNil(recv) && recv.Exported()
+		}).
+		Foreach(func(m constructs.Method) { d.forcePend(m, ReasonExported) })
+
+	pkg.Objects().Enumerate().
+		Where(func(obj constructs.Object) bool { return obj.Exported() }).
+		Foreach(func(obj constructs.Object) { d.forcePend(obj, ReasonExported) })
+
+	pkg.Values().Enumerate().
+		Where(func(v constructs.Value) bool { return v.Exported() }).
+		Foreach(func(v constructs.Value) { d.forcePend(v, ReasonExported) })
+}
+
+func (d *dce) primeAliveForDecl(decl constructs.Declaration, reason string) {
+	d.forcePend(decl, reason)
+	if obj, ok := decl.(constructs.Object); ok {
+		obj.Methods().Enumerate().
+			Where(func(m constructs.Method) bool { return m.Exported() }).
+			Foreach(func(m constructs.Method) { d.forcePend(m, reason) })
+	}
+}
+
+// declarationsBySymbol gets the top-level declarations keyed by their
+// qualified names. There may be more than one declaration for a symbol
+// when the declaration differs between build configurations.
+func (d *dce) declarationsBySymbol() map[string][]constructs.Declaration {
+	decls := map[string][]constructs.Declaration{}
+	add := func(decl constructs.Declaration) {
+		symbol := constructs.QualifiedName(decl)
+		decls[symbol] = append(decls[symbol], decl)
+	}
+	d.proj.InterfaceDecls().Enumerate().
+		Where(func(it constructs.InterfaceDecl) bool { return utils.IsNil(it.Nest()) }).
+		Foreach(func(it constructs.InterfaceDecl) { add(it) })
+	d.proj.Objects().Enumerate().
+		Where(func(obj constructs.Object) bool { return utils.IsNil(obj.Nest()) }).
+		Foreach(func(obj constructs.Object) { add(obj) })
+	d.proj.Values().Enumerate().
+		Foreach(func(v constructs.Value) { add(v) })
+	d.proj.Methods().Enumerate().
+		Where(func(m constructs.Method) bool { return m.IsNamed() }).
+		Foreach(func(m constructs.Method) { add(m) })
+	return decls
 }
 
 func (d *dce) updateAlive(c constructs.Construct) {
@@ -196,6 +364,7 @@ func (d *dce) updateMethod(c constructs.Method) {
 	d.pend(c.Package())
 	d.pend(c.Receiver())
 	d.pend(c.Signature())
+	d.pend(c.Metrics())
 	pendSlice(d, c.TypeParams())
 	// Do not automatically make instances alive for the alive generics.
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/hint"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/innate"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/logger"
 )
 
@@ -16,6 +17,13 @@ func GenerateInterfaces(log *logger.Logger, proj constructs.Project) {
 	extendPointers(proj)
 }
 
+// tolerate runs the given handle for generating the interface of the given
+// construct. If the abstraction is tolerant and the handle fails, the failure
+// is recorded and the interfaces for the other constructs are still generated.
+func tolerate(proj constructs.Project, c constructs.Construct, handle func()) {
+	constructs.Tolerate(proj, diagnostics.Interfaces, c, handle)
+}
+
 // objectInterfaces resolves all object interfaces
 // and the interfaces for object instances.
 func objectInterfaces(log *logger.Logger, proj constructs.Project) {
@@ -29,7 +37,7 @@ func objectInterfaces(log *logger.Logger, proj constructs.Project) {
 		// If the object doesn't have an interface create one and set it.
 		if utils.IsNil(obj.Interface()) {
 			log.Logf(`%d) %s.%s`, i, obj.Package().Path(), obj.Name())
-			objectInter(proj, obj)
+			tolerate(proj, obj, func() { objectInter(proj, obj) })
 		}
 
 		// Resolve all instances for the object
@@ -40,7 +48,7 @@ func objectInterfaces(log *logger.Logger, proj constructs.Project) {
 			// If the instance doesn't have an interface create one and set it.
 			if utils.IsNil(it.ResolvedInterface()) {
 				log2.Logf(`%d.%d) [%s]`, i, j, enumerator.Enumerate(it.InstanceTypes()...).Join(`, `))
-				objectInstanceInter(proj, it)
+				tolerate(proj, it, func() { objectInstanceInter(proj, it) })
 			}
 		}
 	}
@@ -154,7 +162,7 @@ func extendPointers(proj constructs.Project) {
 		if it := its.Get(i); it.Hint() == hint.Pointer {
 			derefSig := constructs.FindSigByName(it.Abstracts(), innate.Deref)
 			if !utils.IsNil(derefSig) && len(derefSig.Results()) == 1 {
-				extendPointer(proj, it, derefSig.Results()[0].Type())
+				tolerate(proj, it, func() { extendPointer(proj, it, derefSig.Results()[0].Type()) })
 			}
 		}
 	}
@@ -9,11 +9,15 @@ import (
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/logger"
 )
 
-func Resolve[T Node[T]](log *logger.Logger, cmp comp.Comparer[T], its collections.ReadonlySortedSet[T]) {
+// Resolve processes each of the given nodes into the inheritance forest.
+// Each node is processed via the given tolerate function so that a failure
+// processing one node may be recorded while the other nodes are processed.
+func Resolve[T Node[T]](log *logger.Logger, cmp comp.Comparer[T], its collections.ReadonlySortedSet[T], tolerate func(node T, handle func())) {
 	log2 := log.Group(`inheritance`).Indent()
 	in := New(cmp, log2)
 	for i := range its.Count() {
-		in.Process(its.Get(i))
+		node := its.Get(i)
+		tolerate(node, func() { in.Process(node) })
 	}
 
 	// Print results of this process
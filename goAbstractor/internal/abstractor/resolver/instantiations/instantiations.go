@@ -13,6 +13,7 @@ import (
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/instantiator"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/querier"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/logger"
 )
 
@@ -61,23 +62,36 @@ func (in *instantiationsImp) ExpandInstantiations() bool {
 	return in.changed
 }
 
+// tolerate runs the given handle for expanding the given construct.
+// If the abstraction is tolerant and the handle fails, the failure is
+// recorded and the instantiations for the other constructs are still
+// expanded. The construct is already marked as done so it isn't retried.
+func (in *instantiationsImp) tolerate(c constructs.Construct, handle func()) {
+	constructs.Tolerate(in.proj, diagnostics.Instantiations, c, handle)
+}
+
 func (in *instantiationsImp) fillOutAllMetrics() {
 	for mi := range in.proj.MethodInsts().Enumerate().Seq() {
 		if !in.doneMetrics[mi] {
 			in.doneMetrics[mi] = true
 			in.changed = true
-			in.fillOutMetrics(mi)
+			in.tolerate(mi, func() { in.fillOutMetrics(mi) })
 		}
 	}
 }
 
 func (in *instantiationsImp) fillOutMetrics(mi constructs.MethodInst) {
 	m := mi.Generic()
+	// The generic functions in packages that aren't analyzed,
+	// e.g. `reflect.TypeFor`, have no metrics to fill out.
+	if utils.IsNil(m.Metrics()) {
+		return
+	}
 	curPkg := m.Package()
 	node := m.Metrics().Node()
 	tpReplacer := m.Metrics().TpReplacer()
 	conv := converter.New(in.log, in.querier, in.bk, in.proj, curPkg, m, mi.InstanceTypes(), tpReplacer, in.typeCache)
-	metrics := analyzer.Analyze(in.log, in.querier, in.proj, curPkg, in.bk, conv, node)
+	metrics := analyzer.Analyze(in.log, in.querier, in.proj, curPkg, in.bk, conv, nil, node)
 	mi.SetMetrics(metrics)
 }
 
@@ -86,7 +100,7 @@ func (in *instantiationsImp) expandAllInstantiations() {
 		if !in.doneExpandInst[obj] {
 			in.doneExpandInst[obj] = true
 			in.changed = true
-			in.expandInstantiations(obj)
+			in.tolerate(obj, func() { in.expandInstantiations(obj) })
 		}
 	}
 }
@@ -132,7 +146,7 @@ func (in *instantiationsImp) fillOutAllPointerReceivers() {
 		if !in.donePointerRec[obj] {
 			in.donePointerRec[obj] = true
 			in.changed = true
-			in.fillOutPointerReceivers(obj)
+			in.tolerate(obj, func() { in.fillOutPointerReceivers(obj) })
 		}
 	}
 }
@@ -182,7 +196,7 @@ func (in *instantiationsImp) expandAllNestedTypes() {
 		if !in.doneNestedType[m] {
 			in.doneNestedType[m] = true
 			in.changed = true
-			in.expandNestedTypes(m)
+			in.tolerate(m, func() { in.expandNestedTypes(m) })
 		}
 	}
 }
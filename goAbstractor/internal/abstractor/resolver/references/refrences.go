@@ -10,6 +10,7 @@ import (
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/querier"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/kind"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/logger"
 )
 
@@ -52,12 +53,21 @@ func (r *ref) clearAllTemps() {
 	}
 }
 
+// tolerate runs the given handle for the given construct.
+// If the abstraction is tolerant and the handle fails, the failure is
+// recorded and the references for the other constructs are still resolved.
+func (r *ref) tolerate(c constructs.Construct, handle func()) {
+	constructs.Tolerate(r.proj, diagnostics.References, c, handle)
+}
+
 func (r *ref) removeTempReferences() {
 	for c := range r.proj.Enumerate().Seq() {
 		if trc, has := c.(constructs.TempReferenceContainer); has {
-			if trc.RemoveTempReferences(r.required) {
-				r.changed = true
-			}
+			r.tolerate(c, func() {
+				if trc.RemoveTempReferences(r.required) {
+					r.changed = true
+				}
+			})
 		}
 	}
 }
@@ -65,9 +75,11 @@ func (r *ref) removeTempReferences() {
 func (r *ref) removeTempDeclRefs() {
 	for c := range r.proj.Enumerate().Seq() {
 		if trc, has := c.(constructs.TempDeclRefContainer); has {
-			if trc.RemoveTempDeclRefs(r.required) {
-				r.changed = true
-			}
+			r.tolerate(c, func() {
+				if trc.RemoveTempDeclRefs(r.required) {
+					r.changed = true
+				}
+			})
 		}
 	}
 }
@@ -75,7 +87,8 @@ func (r *ref) removeTempDeclRefs() {
 func (r *ref) tempReferences() {
 	refs := r.proj.TempReferences()
 	for i := range refs.Count() {
-		r.resolveTempRef(refs.Get(i))
+		ref := refs.Get(i)
+		r.tolerate(ref, func() { r.resolveTempRef(ref) })
 	}
 	r.removeTempReferences()
 }
@@ -83,7 +96,8 @@ func (r *ref) tempReferences() {
 func (r *ref) tempDeclRefs() {
 	refs := r.proj.TempDeclRefs()
 	for i := range refs.Count() {
-		r.resolveTempDeclRef(refs.Get(i))
+		ref := refs.Get(i)
+		r.tolerate(ref, func() { r.resolveTempDeclRef(ref) })
 	}
 	r.removeTempDeclRefs()
 }
@@ -153,7 +167,7 @@ func (r *ref) resolveTempRef(ref constructs.TempReference) {
 }
 
 func (r *ref) resolveTempDeclRef(ref constructs.TempDeclRef) {
-	if ref.Resolved() {
+	if ref.Resolved() || ref.Dropped() {
 		return
 	}
 
@@ -12,7 +12,10 @@ import (
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/resolver/references"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/interfaceDesc"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/logger"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/progress"
 )
 
 type resolverImp struct {
@@ -20,19 +23,25 @@ type resolverImp struct {
 	querier *querier.Querier
 	proj    constructs.Project
 	is      instantiations.Instantiations
+	roots   dce.Roots
+
+	progress  *progress.Reporter
+	iteration int
 }
 
-func Resolve(proj constructs.Project, querier *querier.Querier, log *logger.Logger, skipDead bool) {
+func Resolve(proj constructs.Project, querier *querier.Querier, log *logger.Logger, prog *progress.Reporter, roots dce.Roots, skipDead bool) {
 	resolve := &resolverImp{
-		log:     log.Group(`resolver`),
-		querier: querier,
-		proj:    proj,
-		is:      instantiations.New(log, querier, proj),
+		log:      log.Group(`resolver`),
+		querier:  querier,
+		proj:     proj,
+		progress: prog,
+		roots:    roots,
+		is:       instantiations.New(log, querier, proj),
 	}
 
 	// Resolve imports of packages and receivers in methods.
-	resolve.Imports()
-	resolve.Receivers()
+	resolve.tolerate(diagnostics.Imports, resolve.Imports)
+	resolve.tolerate(diagnostics.Receivers, resolve.Receivers)
 
 	// Expanding instantiations may require instantiating referenced objects
 	// and instantiating referenced objects may create instantiations that
@@ -45,37 +54,54 @@ func Resolve(proj constructs.Project, querier *querier.Querier, log *logger.Logg
 		if loopBreak <= 0 {
 			panic(terror.New(`resolver loop exceeded maximum loop count`))
 		}
+		resolve.iteration++
 
 		// First pass of removing references.
 		// This includes creating instances that were referenced in the metrics.
-		changed = resolve.References(false) || changed
+		resolve.tolerate(diagnostics.References, func() {
+			changed = resolve.References(false) || changed
+		})
 
 		// Fill out all instantiations of generic object, interface, and methods.
 		// Also fill out all pointer receivers that are still not defined.
-		changed = resolve.ExpandInstantiations() || changed
+		resolve.tolerate(diagnostics.Instantiations, func() {
+			changed = resolve.ExpandInstantiations() || changed
+		})
 	}
+	resolve.iteration = 0
 
 	// Second pass of removing references.
 	// This takes care of any references that the instantiation had to make.
 	// There should be none but doesn't hurt to check.
-	resolve.References(true)
+	resolve.tolerate(diagnostics.References, func() { resolve.References(true) })
 
 	// Remove any duplicates that were caused by references.
-	resolve.RemoveDuplicates()
+	resolve.tolerate(diagnostics.Duplicates, resolve.RemoveDuplicates)
 
 	// Determine interfaces for objects and object instances.
 	// Also extend the interfaces for pointers.
-	resolve.GenerateInterfaces()
+	resolve.tolerate(diagnostics.Interfaces, resolve.GenerateInterfaces)
 
 	// Determine inheritance hierarchy to solidify duck-typing.
-	resolve.Inheritance()
+	resolve.tolerate(diagnostics.Inheritance, resolve.Inheritance)
 
 	// Remove anything that isn't needed.
-	resolve.DeadCodeElimination()
+	resolve.tolerate(diagnostics.DeadCode, resolve.DeadCodeElimination)
 
-	// Update the locations and indices to prepare for outputting.
+	// Update the locations, indices, and stable identifiers
+	// to prepare for outputting.
 	resolve.Locations()
 	resolve.Indices(skipDead)
+	resolve.StableIDs()
+}
+
+// tolerate runs the given resolver phase. Each phase tolerates the failure
+// of each construct it handles itself. If the abstraction is tolerant any
+// other failure in the phase is recorded and the resolver continues on to
+// the next phase.
+func (r *resolverImp) tolerate(phase diagnostics.Phase, handle func()) {
+	r.progress.Iteration(phase, r.iteration)
+	r.proj.Diagnostics().Tolerate(phase, ``, nil, handle)
 }
 
 func (r *resolverImp) Imports() {
@@ -84,13 +110,15 @@ func (r *resolverImp) Imports() {
 	for i := range packages.Count() {
 		pkg := packages.Get(i)
 		for _, importPath := range pkg.ImportPaths() {
-			impPackage := r.proj.FindPackageByPath(importPath)
-			if impPackage == nil {
-				panic(terror.New(`import package not found`).
-					With(`package path`, pkg.Path).
-					With(`import path`, importPath))
-			}
-			pkg.AddImport(impPackage)
+			r.proj.Diagnostics().Tolerate(diagnostics.Imports, importPath, nil, func() {
+				impPackage := r.proj.FindPackageByPath(importPath)
+				if impPackage == nil {
+					panic(terror.New(`import package not found`).
+						With(`package path`, pkg.Path()).
+						With(`import path`, importPath))
+				}
+				pkg.AddImport(impPackage)
+			})
 		}
 	}
 }
@@ -99,7 +127,8 @@ func (r *resolverImp) Receivers() {
 	r.log.Log(`resolve receivers`)
 	packages := r.proj.Packages()
 	for i := range packages.Count() {
-		packages.Get(i).ResolveReceivers()
+		pkg := packages.Get(i)
+		r.proj.Diagnostics().Tolerate(diagnostics.Receivers, pkg.Path(), nil, pkg.ResolveReceivers)
 	}
 }
 
@@ -115,7 +144,10 @@ func (r *resolverImp) GenerateInterfaces() {
 
 func (r *resolverImp) Inheritance() {
 	r.log.Log(`resolve inheritance`)
-	inheritance.Resolve(r.log, interfaceDesc.Comparer(), r.proj.InterfaceDescs())
+	inheritance.Resolve(r.log, interfaceDesc.Comparer(), r.proj.InterfaceDescs(),
+		func(it constructs.InterfaceDesc, handle func()) {
+			constructs.Tolerate(r.proj, diagnostics.Inheritance, it, handle)
+		})
 }
 
 func (r *resolverImp) References(required bool) bool {
@@ -125,7 +157,7 @@ func (r *resolverImp) References(required bool) bool {
 
 func (r *resolverImp) DeadCodeElimination() {
 	r.log.Log(`dead-code elimination`)
-	dce.DeadCodeElimination(r.proj)
+	dce.DeadCodeElimination(r.proj, r.roots)
 }
 
 func (r *resolverImp) Locations() {
@@ -135,9 +167,10 @@ func (r *resolverImp) Locations() {
 	flagList(r.proj.Methods())
 	flagList(r.proj.Objects())
 	flagList(r.proj.Values())
+	flagList(r.proj.Metrics())
 }
 
-func flagList[T constructs.Declaration](c collections.ReadonlySortedSet[T]) {
+func flagList[T interface{ Location() locs.Loc }](c collections.ReadonlySortedSet[T]) {
 	for i := range c.Count() {
 		c.Get(i).Location().Flag()
 	}
@@ -155,3 +188,11 @@ func (r *resolverImp) Indices(skipDead bool) {
 	r.log.Log(`resolve indices (skipDead=`, skipDead, `)`)
 	r.proj.UpdateIndices(skipDead)
 }
+
+// StableIDs should be called after the duplicates have been removed.
+// This will update the identifiers derived from the content of the
+// constructs that may be outputted next to the indices.
+func (r *resolverImp) StableIDs() {
+	r.log.Log(`resolve stable identifiers`)
+	r.proj.UpdateStableIDs()
+}
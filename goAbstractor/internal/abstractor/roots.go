@@ -0,0 +1,127 @@
+package abstractor
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/resolver/dce"
+)
+
+// registrations are the functions that register a type or value so that
+// it may be used later via reflection, keyed by the full function name.
+var registrations = map[string]bool{
+	`database/sql.Register`:                            true,
+	`encoding/gob.Register`:                            true,
+	`encoding/gob.RegisterName`:                        true,
+	`expvar.Publish`:                                   true,
+	`github.com/golang/protobuf/proto.RegisterType`:    true,
+	`(*google.golang.org/grpc.Server).RegisterService`: true,
+}
+
+// addRoot adds the given qualified name of a declaration as a root of
+// the dead-code elimination unless that declaration is already a root.
+func (ab *abstractor) addRoot(symbol, reason string) {
+	if _, has := ab.roots[symbol]; !has {
+		ab.roots[symbol] = reason
+	}
+}
+
+// findRoots finds the declarations in the given file that must be kept
+// alive even though they may not be used in the project. This includes
+// any declaration referenced by a `//go:linkname` or `//export` directive
+// and, if enabled, any declaration used in a reflection-based registration.
+func (ab *abstractor) findRoots(f *ast.File) {
+	pkgPath := ab.curPkg.Path()
+	for _, group := range f.Comments {
+		for _, c := range group.List {
+			// i.e. `//go:linkname localname [importpath.name]`
+			fields := strings.Fields(c.Text)
+			if len(fields) >= 2 && fields[0] == `//go:linkname` {
+				ab.addRoot(pkgPath+`.`+fields[1], dce.ReasonLinkname)
+				if len(fields) >= 3 {
+					ab.addRoot(fields[2], dce.ReasonLinkname)
+				}
+			}
+		}
+	}
+
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil || fd.Doc == nil {
+			continue
+		}
+		for _, c := range fd.Doc.List {
+			// i.e. `//export Name`
+			if fields := strings.Fields(c.Text); len(fields) >= 2 && fields[0] == `//export` {
+				ab.addRoot(pkgPath+`.`+fd.Name.Name, dce.ReasonExport)
+			}
+		}
+	}
+
+	if ab.reflectionRoots {
+		ast.Inspect(f, ab.findRegistration)
+	}
+}
+
+// findRegistration checks if the given node is a call to a registration
+// function and adds any type or declaration used in the call as a root.
+func (ab *abstractor) findRegistration(node ast.Node) bool {
+	call, ok := node.(*ast.CallExpr)
+	if !ok {
+		return true
+	}
+	info := ab.querier.Info()
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok || !registrations[fn.FullName()] {
+		return true
+	}
+
+	for _, arg := range call.Args {
+		if tv, has := info.Types[arg]; has {
+			ab.addTypeRoot(tv.Type)
+		}
+		ast.Inspect(arg, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok {
+				ab.addObjectRoot(info.Uses[id])
+			}
+			return true
+		})
+	}
+	return true
+}
+
+func (ab *abstractor) addTypeRoot(t types.Type) {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	if n, ok := t.(*types.Named); ok {
+		ab.addObjectRoot(n.Origin().Obj())
+	}
+}
+
+// addObjectRoot adds the given object as a root
+// if the object is a package level declaration.
+func (ab *abstractor) addObjectRoot(obj types.Object) {
+	if obj == nil || obj.Pkg() == nil {
+		return
+	}
+	pkgPath := obj.Pkg().Path()
+	if fn, ok := obj.(*types.Func); ok {
+		if recv := fn.Signature().Recv(); recv != nil {
+			t := recv.Type()
+			if p, ok := t.(*types.Pointer); ok {
+				t = p.Elem()
+			}
+			if n, ok := t.(*types.Named); ok {
+				ab.addRoot(pkgPath+`.`+n.Obj().Name()+`.`+fn.Name(), dce.ReasonReflection)
+			}
+			return
+		}
+	}
+	if obj.Parent() == obj.Pkg().Scope() {
+		ab.addRoot(pkgPath+`.`+obj.Name(), dce.ReasonReflection)
+	}
+}
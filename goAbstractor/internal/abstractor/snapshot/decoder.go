@@ -0,0 +1,636 @@
+package snapshot
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/Snow-Gremlin/goToolbox/collections"
+	"github.com/Snow-Gremlin/goToolbox/collections/sortedSet"
+	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
+	"github.com/Snow-Gremlin/goToolbox/utils"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/baker"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/querier"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/kind"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+)
+
+type decoder struct {
+	querier *querier.Querier
+	proj    constructs.Project
+	baker   baker.Baker
+	srcs    *sources
+	snap    *Snapshot
+
+	pkgs  []*packages.Package
+	files []*token.File
+	objs  []types.Object
+	types []types.Type
+	cons  []constructs.Construct
+}
+
+func newDecoder(querier *querier.Querier, proj constructs.Project, srcs []*packages.Package, snap *Snapshot) *decoder {
+	return &decoder{
+		querier: querier,
+		proj:    proj,
+		baker:   baker.New(proj),
+		srcs:    newSources(querier.FileSet(), srcs),
+		snap:    snap,
+	}
+}
+
+func (d *decoder) decode() {
+	d.pkgs = make([]*packages.Package, len(d.snap.Packages))
+	for i, ref := range d.snap.Packages {
+		d.pkgs[i] = d.pkgRef(ref)
+	}
+	d.files = make([]*token.File, len(d.snap.Files))
+	for i, ref := range d.snap.Files {
+		d.files[i] = d.fileRef(ref)
+	}
+	d.objs = make([]types.Object, len(d.snap.Objects))
+	for i, ref := range d.snap.Objects {
+		d.objs[i] = d.objRef(ref)
+	}
+	d.types = make([]types.Type, len(d.snap.Types))
+	for i, rec := range d.snap.Types {
+		d.types[i] = d.typeRec(rec)
+	}
+	d.cons = make([]constructs.Construct, len(d.snap.Constructs))
+	for i, rec := range d.snap.Constructs {
+		d.cons[i] = d.conRec(rec)
+	}
+	for i, rec := range d.snap.Constructs {
+		d.changes(d.cons[i], rec)
+	}
+}
+
+func (d *decoder) pkgRef(ref pkgRef) *packages.Package {
+	if ref.Builtin {
+		return d.baker.BakeBuiltin().Source()
+	}
+	pkg, has := d.srcs.pkgs[ref]
+	if !has {
+		panic(terror.New(`package is not imported by the source packages`).
+			With(`unit`, ref.Unit).
+			With(`package`, ref.ID))
+	}
+	return pkg
+}
+
+func (d *decoder) fileRef(ref fileRef) *token.File {
+	pkg := d.pkg(ref.Pkg)
+	tf := d.srcs.file(pkg, ref.Name)
+	if tf == nil {
+		panic(terror.New(`file is not in the package`).
+			With(`package`, pkg.ID).
+			With(`file`, ref.Name))
+	}
+	return tf
+}
+
+func (d *decoder) objRef(ref objRef) types.Object {
+	if len(ref.Universe) > 0 {
+		if o := types.Universe.Lookup(ref.Universe); o != nil {
+			return o
+		}
+		panic(terror.New(`object is not in the universe`).
+			With(`name`, ref.Universe))
+	}
+	pkg := d.pkg(ref.Pkg)
+	if pkg == nil {
+		panic(terror.New(`no package is given for the object`).
+			With(`file`, d.file(ref.File).Name()).
+			With(`offset`, ref.Offset))
+	}
+	o := d.srcs.object(pkg, d.pos(ref.File, ref.Offset))
+	if o == nil {
+		panic(terror.New(`no object is defined at the position`).
+			With(`package`, pkg.ID).
+			With(`file`, d.file(ref.File).Name()).
+			With(`offset`, ref.Offset))
+	}
+	return o
+}
+
+func (d *decoder) pkg(index int) *packages.Package {
+	if index <= 0 {
+		return nil
+	}
+	return d.pkgs[index-1]
+}
+
+func (d *decoder) typesPkg(index int) *types.Package {
+	if pkg := d.pkg(index); pkg != nil {
+		return pkg.Types
+	}
+	return nil
+}
+
+func (d *decoder) file(index int) *token.File {
+	return d.files[index-1]
+}
+
+func (d *decoder) pos(file, offset int) token.Pos {
+	return d.file(file).Pos(offset)
+}
+
+func (d *decoder) obj(index int) types.Object {
+	return d.objs[index-1]
+}
+
+func (d *decoder) typ(index int) types.Type {
+	if index <= 0 {
+		return nil
+	}
+	return d.types[index-1]
+}
+
+func (d *decoder) tuple(index int) *types.Tuple {
+	if index <= 0 {
+		return nil
+	}
+	return d.typ(index).(*types.Tuple)
+}
+
+func (d *decoder) typeRec(rec typeRec) types.Type {
+	switch rec.Kind {
+	case typeBasic:
+		kind := types.BasicKind(rec.Basic)
+		if o := types.Universe.Lookup(rec.Name); o != nil {
+			if b, ok := o.Type().(*types.Basic); ok && b.Kind() == kind {
+				return b
+			}
+		}
+		return types.Typ[kind]
+
+	case typePointer:
+		return types.NewPointer(d.typ(rec.Elem))
+
+	case typeSlice:
+		return types.NewSlice(d.typ(rec.Elem))
+
+	case typeArray:
+		return types.NewArray(d.typ(rec.Elem), rec.Len)
+
+	case typeMap:
+		return types.NewMap(d.typ(rec.Key), d.typ(rec.Elem))
+
+	case typeChan:
+		return types.NewChan(types.ChanDir(rec.Dir), d.typ(rec.Elem))
+
+	case typeStruct:
+		fields := make([]*types.Var, len(rec.Types))
+		for i, t := range rec.Types {
+			fields[i] = types.NewField(token.NoPos, d.typesPkg(rec.Pkgs[i]), rec.Names[i], d.typ(t), rec.Flags[i])
+		}
+		return types.NewStruct(fields, rec.Tags)
+
+	case typeInterface:
+		methods := make([]*types.Func, len(rec.Names))
+		for i, name := range rec.Names {
+			methods[i] = types.NewFunc(token.NoPos, d.typesPkg(rec.Pkgs[i]), name, d.typ(rec.Types[i]).(*types.Signature))
+		}
+		embedded := make([]types.Type, len(rec.Types)-len(rec.Names))
+		for i, t := range rec.Types[len(rec.Names):] {
+			embedded[i] = d.typ(t)
+		}
+		it := types.NewInterfaceType(methods, embedded)
+		if rec.Implicit {
+			it.MarkImplicit()
+		}
+		return it.Complete()
+
+	case typeComparable:
+		return types.Universe.Lookup(`comparable`).Type().Underlying()
+
+	case typeSignature:
+		return types.NewSignatureType(nil, nil, nil, d.tuple(rec.Params), d.tuple(rec.Results), rec.Variadic)
+
+	case typeFunc:
+		return d.obj(rec.Obj).(*types.Func).Signature()
+
+	case typeNamed, typeAlias, typeParam:
+		return d.obj(rec.Obj).Type()
+
+	case typeInstance:
+		args := make([]types.Type, len(rec.Types))
+		for i, t := range rec.Types {
+			args[i] = d.typ(t)
+		}
+		inst, err := types.Instantiate(d.querier.Context(), d.typ(rec.Elem), args, false)
+		if err != nil {
+			panic(terror.New(`failed to instantiate a type`, err))
+		}
+		return inst
+
+	case typeUnion:
+		terms := make([]*types.Term, len(rec.Types))
+		for i, t := range rec.Types {
+			terms[i] = types.NewTerm(rec.Flags[i], d.typ(t))
+		}
+		return types.NewUnion(terms)
+
+	case typeTuple:
+		vars := make([]*types.Var, len(rec.Types))
+		for i, t := range rec.Types {
+			vars[i] = types.NewParam(token.NoPos, nil, rec.Names[i], d.typ(t))
+		}
+		return types.NewTuple(vars...)
+
+	default:
+		panic(terror.New(`unexpected type kind`).
+			With(`kind`, rec.Kind))
+	}
+}
+
+func (d *decoder) loc(rec *locRec) locs.Loc {
+	switch {
+	case rec == nil:
+		return nil
+	case rec.File <= 0:
+		return locs.NoLoc()
+	case rec.Range:
+		return d.proj.Locs().NewRange(d.pos(rec.File, rec.Pos), d.pos(rec.File, rec.End))
+	default:
+		return d.proj.Locs().NewLoc(d.pos(rec.File, rec.Pos))
+	}
+}
+
+func (d *decoder) node(rec *nodeRec) ast.Node {
+	if rec == nil {
+		return nil
+	}
+	n := d.srcs.node(d.file(rec.File), d.pos(rec.File, rec.Pos), d.pos(rec.File, rec.End), rec.Type)
+	if n == nil {
+		panic(terror.New(`no node is at the position`).
+			With(`file`, d.file(rec.File).Name()).
+			With(`offset`, rec.Pos).
+			With(`type`, rec.Type))
+	}
+	return n
+}
+
+func (d *decoder) con(index int) constructs.Construct {
+	if index <= 0 {
+		return nil
+	}
+	c := d.cons[index-1]
+	if utils.IsNil(c) {
+		panic(terror.New(`construct was referenced before it was restored`).
+			With(`index`, index))
+	}
+	return c
+}
+
+// conAs gets the construct at the given index as the given type
+// or the zero value if there is no construct.
+func conAs[T constructs.Construct](d *decoder, index int) T {
+	var zero T
+	if c := d.con(index); c != nil {
+		return c.(T)
+	}
+	return zero
+}
+
+// consAs gets the constructs at the given indices as the given type
+// or nil if there are no indices, the same as the constructs were
+// when the snapshot was taken, e.g. the implicit types of a reference.
+func consAs[T constructs.Construct](d *decoder, indices []int) []T {
+	if len(indices) <= 0 {
+		return nil
+	}
+	result := make([]T, len(indices))
+	for i, index := range indices {
+		result[i] = conAs[T](d, index)
+	}
+	return result
+}
+
+func (d *decoder) set(indices []int) collections.SortedSet[constructs.Construct] {
+	set := sortedSet.New(constructs.Comparer[constructs.Construct]())
+	for _, index := range indices {
+		set.Add(d.con(index))
+	}
+	return set
+}
+
+func (d *decoder) conRec(rec conRec) constructs.Construct {
+	c := d.create(rec)
+	if bc, ok := c.(constructs.BuildConfigured); ok {
+		bc.AddBuildConfigs(rec.Builds...)
+	}
+	return c
+}
+
+func (d *decoder) create(rec conRec) constructs.Construct {
+	switch rec.Kind {
+	case kind.Abstract:
+		return d.proj.NewAbstract(constructs.AbstractArgs{
+			Name:      rec.Name,
+			Exported:  rec.Exported,
+			Signature: conAs[constructs.Signature](d, rec.Signature),
+		})
+
+	case kind.Argument:
+		return d.proj.NewArgument(constructs.ArgumentArgs{
+			Name: rec.Name,
+			Type: conAs[constructs.TypeDesc](d, rec.Type),
+		})
+
+	case kind.Basic:
+		return d.proj.NewBasic(constructs.BasicArgs{
+			RealType: d.typ(rec.RealType).(*types.Basic),
+		})
+
+	case kind.Field:
+		return d.proj.NewField(constructs.FieldArgs{
+			Name:     rec.Name,
+			Exported: rec.Exported,
+			Type:     conAs[constructs.TypeDesc](d, rec.Type),
+			Embedded: rec.Embedded,
+		})
+
+	case kind.InterfaceDecl:
+		return d.proj.NewInterfaceDecl(constructs.InterfaceDeclArgs{
+			RealType:   d.typ(rec.RealType),
+			Package:    conAs[constructs.Package](d, rec.Package),
+			Name:       rec.Name,
+			Exported:   rec.Exported,
+			Location:   d.loc(rec.Location),
+			Nest:       conAs[constructs.NestType](d, rec.Nest),
+			TestCode:   rec.TestCode,
+			Generated:  rec.Generated,
+			TypeParams: consAs[constructs.TypeParam](d, rec.TypeParams),
+			Interface:  conAs[constructs.InterfaceDesc](d, rec.Interface),
+		})
+
+	case kind.InterfaceDesc:
+		return d.proj.NewInterfaceDesc(constructs.InterfaceDescArgs{
+			Hint:      rec.Hint,
+			RealType:  d.typ(rec.RealType),
+			PinnedPkg: conAs[constructs.Package](d, rec.PinnedPkg),
+			Abstracts: consAs[constructs.Abstract](d, rec.Abstracts),
+			Exact:     consAs[constructs.TypeDesc](d, rec.Exact),
+			Approx:    consAs[constructs.TypeDesc](d, rec.Approx),
+		})
+
+	case kind.InterfaceInst:
+		return d.proj.NewInterfaceInst(constructs.InterfaceInstArgs{
+			RealType:      d.typ(rec.RealType),
+			Generic:       conAs[constructs.InterfaceDecl](d, rec.Generic),
+			Resolved:      conAs[constructs.InterfaceDesc](d, rec.Resolved),
+			ImplicitTypes: consAs[constructs.TypeDesc](d, rec.Implicit),
+			InstanceTypes: consAs[constructs.TypeDesc](d, rec.Instance),
+		})
+
+	case kind.Method:
+		args := constructs.MethodArgs{
+			Package:     conAs[constructs.Package](d, rec.Package),
+			TypeParams:  []constructs.TypeParam{},
+			Name:        rec.Name,
+			Exported:    rec.Exported,
+			Location:    d.loc(rec.Location),
+			TestCode:    rec.TestCode,
+			Generated:   rec.Generated,
+			Signature:   conAs[constructs.Signature](d, rec.Signature),
+			Metrics:     conAs[constructs.Metrics](d, rec.Metrics),
+			RecvName:    rec.RecvName,
+			Receiver:    conAs[constructs.Object](d, rec.Receiver),
+			PointerRecv: rec.PointerRecv,
+		}
+		if len(rec.TypeParams) > 0 {
+			args.TypeParams = consAs[constructs.TypeParam](d, rec.TypeParams)
+		}
+		if rec.FuncType > 0 {
+			args.FuncType = d.obj(rec.FuncType).(*types.Func)
+		}
+		if rec.RealType > 0 {
+			args.SigType = d.typ(rec.RealType).(*types.Signature)
+		}
+		return d.proj.NewMethod(args)
+
+	case kind.MethodInst:
+		return d.proj.NewMethodInst(constructs.MethodInstArgs{
+			Generic:       conAs[constructs.Method](d, rec.Generic),
+			Resolved:      conAs[constructs.Signature](d, rec.Resolved),
+			InstanceTypes: consAs[constructs.TypeDesc](d, rec.Instance),
+			Metrics:       conAs[constructs.Metrics](d, rec.Metrics),
+		})
+
+	case kind.Metrics:
+		var tpReplacer map[*types.TypeParam]*types.TypeParam
+		if len(rec.TpReplacer) > 0 {
+			tpReplacer = make(map[*types.TypeParam]*types.TypeParam, len(rec.TpReplacer))
+			for _, pair := range rec.TpReplacer {
+				tpReplacer[d.typ(pair[0]).(*types.TypeParam)] = d.typ(pair[1]).(*types.TypeParam)
+			}
+		}
+		return d.proj.NewMetrics(constructs.MetricsArgs{
+			Location:   d.loc(rec.Location),
+			TestCode:   rec.TestCode,
+			Generated:  rec.Generated,
+			Node:       d.node(rec.Node),
+			TpReplacer: tpReplacer,
+			Complexity: rec.Complexity,
+			LineCount:  rec.LineCount,
+			CodeCount:  rec.CodeCount,
+			Indents:    rec.Indents,
+			Getter:     rec.Getter,
+			Setter:     rec.Setter,
+			SideEffect: rec.SideEffect,
+			Reads:      d.set(rec.Reads),
+			Writes:     d.set(rec.Writes),
+			Invokes:    d.set(rec.Invokes),
+		})
+
+	case kind.Object:
+		var fieldLocs map[string]locs.Loc
+		if rec.FieldLocs != nil {
+			fieldLocs = make(map[string]locs.Loc, len(rec.FieldLocs))
+			for name, loc := range rec.FieldLocs {
+				fieldLocs[name] = d.loc(loc)
+			}
+		}
+		return d.proj.NewObject(constructs.ObjectArgs{
+			RealType:       d.typ(rec.RealType),
+			Package:        conAs[constructs.Package](d, rec.Package),
+			Name:           rec.Name,
+			Exported:       rec.Exported,
+			Location:       d.loc(rec.Location),
+			Nest:           conAs[constructs.NestType](d, rec.Nest),
+			TestCode:       rec.TestCode,
+			Generated:      rec.Generated,
+			TypeParams:     consAs[constructs.TypeParam](d, rec.TypeParams),
+			Data:           conAs[constructs.StructDesc](d, rec.Data),
+			FieldLocations: fieldLocs,
+		})
+
+	case kind.ObjectInst:
+		return d.proj.NewObjectInst(constructs.ObjectInstArgs{
+			RealType:      d.typ(rec.RealType),
+			Generic:       conAs[constructs.Object](d, rec.Generic),
+			ResolvedData:  conAs[constructs.StructDesc](d, rec.Data),
+			ImplicitTypes: consAs[constructs.TypeDesc](d, rec.Implicit),
+			InstanceTypes: consAs[constructs.TypeDesc](d, rec.Instance),
+		})
+
+	case kind.Package:
+		src := d.pkg(rec.Source)
+		if d.snap.Packages[rec.Source-1].Builtin {
+			return d.baker.BakeBuiltin()
+		}
+		return d.proj.NewPackage(constructs.PackageArgs{
+			RealPkg:     src,
+			Path:        rec.Path,
+			Name:        rec.Name,
+			ImportPaths: rec.ImportPaths,
+			Module:      rec.Module,
+			EntryPoint:  rec.EntryPoint,
+		})
+
+	case kind.Selection:
+		return d.proj.NewSelection(constructs.SelectionArgs{
+			Name:   rec.Name,
+			Origin: d.con(rec.Origin),
+			Target: d.con(rec.Target),
+		})
+
+	case kind.Signature:
+		return d.proj.NewSignature(constructs.SignatureArgs{
+			RealType: d.typ(rec.RealType).(*types.Signature),
+			Variadic: rec.Variadic,
+			Params:   consAs[constructs.Argument](d, rec.Params),
+			Results:  consAs[constructs.Argument](d, rec.Results),
+		})
+
+	case kind.StructDesc:
+		return d.proj.NewStructDesc(constructs.StructDescArgs{
+			RealType: d.typ(rec.RealType),
+			Fields:   consAs[constructs.Field](d, rec.Fields),
+		})
+
+	case kind.TempDeclRef:
+		args := constructs.TempDeclRefArgs{
+			PackagePath:   rec.Path,
+			Name:          rec.Name,
+			Receiver:      rec.RecvName,
+			ImplicitTypes: consAs[constructs.TypeDesc](d, rec.Implicit),
+			InstanceTypes: consAs[constructs.TypeDesc](d, rec.Instance),
+			Nest:          conAs[constructs.NestType](d, rec.Nest),
+		}
+		if rec.FuncType > 0 {
+			args.FuncType = d.obj(rec.FuncType).(*types.Func)
+		}
+		return d.proj.NewTempDeclRef(args)
+
+	case kind.TempReference:
+		return d.proj.NewTempReference(constructs.TempReferenceArgs{
+			RealType:      d.typ(rec.RealType),
+			PackagePath:   rec.Path,
+			Name:          rec.Name,
+			ImplicitTypes: consAs[constructs.TypeDesc](d, rec.Implicit),
+			InstanceTypes: consAs[constructs.TypeDesc](d, rec.Instance),
+			Nest:          conAs[constructs.NestType](d, rec.Nest),
+		})
+
+	case kind.TempTypeParamRef:
+		return d.proj.NewTempTypeParamRef(constructs.TempTypeParamRefArgs{
+			RealType: d.typ(rec.RealType),
+			Context:  rec.Context,
+			Name:     rec.Name,
+		})
+
+	case kind.TypeParam:
+		return d.proj.NewTypeParam(constructs.TypeParamArgs{
+			Name: rec.Name,
+			Type: conAs[constructs.TypeDesc](d, rec.Type),
+		})
+
+	case kind.Value:
+		return d.proj.NewValue(constructs.ValueArgs{
+			Package:   conAs[constructs.Package](d, rec.Package),
+			Name:      rec.Name,
+			Exported:  rec.Exported,
+			Location:  d.loc(rec.Location),
+			Type:      conAs[constructs.TypeDesc](d, rec.Type),
+			Const:     rec.Const,
+			TestCode:  rec.TestCode,
+			Generated: rec.Generated,
+			Metrics:   conAs[constructs.Metrics](d, rec.Metrics),
+		})
+
+	default:
+		panic(terror.New(`unexpected construct kind`).
+			With(`kind`, rec.Kind))
+	}
+}
+
+// changes makes the changes to the given construct
+// that were made after the construct was created.
+func (d *decoder) changes(c constructs.Construct, rec conRec) {
+	switch rec.Kind {
+	case kind.Object:
+		o := c.(constructs.Object)
+		for _, m := range consAs[constructs.Method](d, rec.Methods) {
+			o.AddMethod(m)
+		}
+
+	case kind.ObjectInst:
+		i := c.(constructs.ObjectInst)
+		for _, m := range consAs[constructs.MethodInst](d, rec.Methods) {
+			i.AddMethod(m)
+		}
+		if rec.ResolvedInterface > 0 {
+			i.SetResolvedInterface(conAs[constructs.InterfaceDesc](d, rec.ResolvedInterface))
+		}
+
+	case kind.MethodInst:
+		if rec.InstReceiver > 0 {
+			c.(constructs.MethodInst).SetReceiver(conAs[constructs.ObjectInst](d, rec.InstReceiver))
+		}
+
+	case kind.Package:
+		p := c.(constructs.Package)
+		for _, imp := range consAs[constructs.Package](d, rec.Imports) {
+			p.AddImport(imp)
+		}
+		for _, m := range rec.Members {
+			switch member := d.con(m).(type) {
+			case constructs.InterfaceDecl:
+				p.AddInterfaceDecl(member)
+			case constructs.Method:
+				p.AddMethod(member)
+			case constructs.Object:
+				p.AddObject(member)
+			case constructs.Value:
+				p.AddValue(member)
+			default:
+				panic(terror.New(`unexpected member of a package`).
+					With(`member`, member))
+			}
+		}
+
+	case kind.TempDeclRef:
+		r := c.(constructs.TempDeclRef)
+		if rec.Resolution > 0 {
+			r.SetResolution(d.con(rec.Resolution))
+		}
+		if rec.Dropped {
+			r.Drop()
+		}
+
+	case kind.TempReference:
+		if rec.Resolution > 0 {
+			c.(constructs.TempReference).SetResolution(conAs[constructs.TypeDesc](d, rec.Resolution))
+		}
+
+	case kind.TempTypeParamRef:
+		if rec.Resolution > 0 {
+			c.(constructs.TempTypeParamRef).SetResolution(conAs[constructs.TypeDesc](d, rec.Resolution))
+		}
+	}
+}
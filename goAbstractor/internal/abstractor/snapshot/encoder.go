@@ -0,0 +1,619 @@
+package snapshot
+
+import (
+	"go/token"
+	"go/types"
+	"slices"
+
+	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
+	"github.com/Snow-Gremlin/goToolbox/utils"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/querier"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/innate"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/kind"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+)
+
+type encoder struct {
+	querier *querier.Querier
+	srcs    *sources
+	snap    *Snapshot
+
+	pkgs  map[*packages.Package]int
+	files map[*token.File]int
+	objs  map[types.Object]int
+	types map[types.Type]int
+	cons  map[constructs.Construct]int
+	known map[constructs.Construct]bool
+	order []constructs.Construct
+
+	builtin int
+}
+
+func newEncoder(querier *querier.Querier, srcs []*packages.Package) *encoder {
+	return &encoder{
+		querier: querier,
+		srcs:    newSources(querier.FileSet(), srcs),
+		snap:    &Snapshot{},
+		pkgs:    map[*packages.Package]int{},
+		files:   map[*token.File]int{},
+		objs:    map[types.Object]int{},
+		types:   map[types.Type]int{},
+		cons:    map[constructs.Construct]int{},
+		known:   map[constructs.Construct]bool{},
+	}
+}
+
+func (e *encoder) encode(proj constructs.Project) {
+	for c := range proj.Enumerate().Seq() {
+		e.known[c] = true
+	}
+	for c := range proj.Enumerate().Seq() {
+		e.con(c)
+	}
+	for i, c := range e.order {
+		e.changes(c, &e.snap.Constructs[i])
+	}
+}
+
+func (e *encoder) pkg(pkg *packages.Package) int {
+	if index, has := e.pkgs[pkg]; has {
+		return index
+	}
+	ref, has := e.srcs.refs[pkg]
+	if !has {
+		if pkg.PkgPath != innate.Builtin {
+			panic(terror.New(`package is not imported by the source packages`).
+				With(`package`, pkg.ID))
+		}
+		return e.builtinPkg()
+	}
+	e.snap.Packages = append(e.snap.Packages, ref)
+	index := len(e.snap.Packages)
+	e.pkgs[pkg] = index
+	return index
+}
+
+// builtinPkg gets the reference to the package that is baked in
+// for the builtin types.
+func (e *encoder) builtinPkg() int {
+	if e.builtin <= 0 {
+		e.snap.Packages = append(e.snap.Packages, pkgRef{Builtin: true})
+		e.builtin = len(e.snap.Packages)
+	}
+	return e.builtin
+}
+
+func (e *encoder) typesPkg(pkg *types.Package) int {
+	if pkg == nil {
+		return 0
+	}
+	src, has := e.srcs.types[pkg]
+	if !has {
+		if pkg.Path() != innate.Builtin {
+			panic(terror.New(`types package is not imported by the source packages`).
+				With(`package`, pkg.Path()))
+		}
+		return e.builtinPkg()
+	}
+	return e.pkg(src)
+}
+
+func (e *encoder) file(tf *token.File) int {
+	if index, has := e.files[tf]; has {
+		return index
+	}
+	pkg, has := e.srcs.owner[tf]
+	if !has {
+		panic(terror.New(`file is not in the source packages`).
+			With(`file`, tf.Name()))
+	}
+	e.snap.Files = append(e.snap.Files, fileRef{
+		Pkg:  e.pkg(pkg),
+		Name: e.srcs.names[tf],
+	})
+	index := len(e.snap.Files)
+	e.files[tf] = index
+	return index
+}
+
+func (e *encoder) pos(p token.Pos) (int, int) {
+	tf := e.querier.FileSet().File(p)
+	if tf == nil {
+		panic(terror.New(`position is not in a file`).
+			With(`pos`, p))
+	}
+	return e.file(tf), tf.Offset(p)
+}
+
+func (e *encoder) obj(o types.Object) int {
+	if index, has := e.objs[o]; has {
+		return index
+	}
+	ref := objRef{}
+	if o.Pkg() == nil && o.Parent() == types.Universe {
+		ref.Universe = o.Name()
+	} else {
+		pkg, has := e.srcs.types[o.Pkg()]
+		if !has || !o.Pos().IsValid() || e.srcs.object(pkg, o.Pos()) != o {
+			panic(terror.New(`object can not be found by its position`).
+				With(`object`, o))
+		}
+		ref.Pkg = e.pkg(pkg)
+		ref.File, ref.Offset = e.pos(o.Pos())
+	}
+	e.snap.Objects = append(e.snap.Objects, ref)
+	index := len(e.snap.Objects)
+	e.objs[o] = index
+	return index
+}
+
+func (e *encoder) typ(t types.Type) int {
+	if utils.IsNil(t) {
+		return 0
+	}
+	if index, has := e.types[t]; has {
+		return index
+	}
+	rec := e.typeRec(t)
+	e.snap.Types = append(e.snap.Types, rec)
+	index := len(e.snap.Types)
+	e.types[t] = index
+	return index
+}
+
+func (e *encoder) typeRec(t types.Type) typeRec {
+	switch t2 := t.(type) {
+	case *types.Basic:
+		return typeRec{Kind: typeBasic, Name: t2.Name(), Basic: int(t2.Kind())}
+
+	case *types.Pointer:
+		return typeRec{Kind: typePointer, Elem: e.typ(t2.Elem())}
+
+	case *types.Slice:
+		return typeRec{Kind: typeSlice, Elem: e.typ(t2.Elem())}
+
+	case *types.Array:
+		return typeRec{Kind: typeArray, Elem: e.typ(t2.Elem()), Len: t2.Len()}
+
+	case *types.Map:
+		return typeRec{Kind: typeMap, Key: e.typ(t2.Key()), Elem: e.typ(t2.Elem())}
+
+	case *types.Chan:
+		return typeRec{Kind: typeChan, Elem: e.typ(t2.Elem()), Dir: int(t2.Dir())}
+
+	case *types.Struct:
+		rec := typeRec{Kind: typeStruct}
+		for i := range t2.NumFields() {
+			f := t2.Field(i)
+			rec.Types = append(rec.Types, e.typ(f.Type()))
+			rec.Names = append(rec.Names, f.Name())
+			rec.Pkgs = append(rec.Pkgs, e.typesPkg(f.Pkg()))
+			rec.Flags = append(rec.Flags, f.Embedded())
+			rec.Tags = append(rec.Tags, t2.Tag(i))
+		}
+		return rec
+
+	case *types.Interface:
+		if t2 == types.Universe.Lookup(`comparable`).Type().Underlying() {
+			return typeRec{Kind: typeComparable}
+		}
+		rec := typeRec{Kind: typeInterface, Implicit: t2.IsImplicit()}
+		for i := range t2.NumExplicitMethods() {
+			m := t2.ExplicitMethod(i)
+			rec.Types = append(rec.Types, e.sig(m.Signature()))
+			rec.Names = append(rec.Names, m.Name())
+			rec.Pkgs = append(rec.Pkgs, e.typesPkg(m.Pkg()))
+		}
+		for i := range t2.NumEmbeddeds() {
+			rec.Types = append(rec.Types, e.typ(t2.EmbeddedType(i)))
+		}
+		return rec
+
+	case *types.Signature:
+		if fn := e.querier.FuncOf(t2); fn != nil && fn.Pos().IsValid() {
+			return typeRec{Kind: typeFunc, Obj: e.obj(fn)}
+		}
+		if t2.TypeParams().Len() > 0 || t2.RecvTypeParams().Len() > 0 {
+			panic(terror.New(`generic signature is not from a function`).
+				With(`signature`, t2))
+		}
+		return e.sigRec(t2)
+
+	case *types.Named:
+		if t2.TypeArgs().Len() > 0 {
+			return e.instRec(t2.Origin(), t2.TypeArgs())
+		}
+		return typeRec{Kind: typeNamed, Obj: e.obj(t2.Obj())}
+
+	case *types.Alias:
+		if t2.TypeArgs().Len() > 0 {
+			return e.instRec(t2.Origin(), t2.TypeArgs())
+		}
+		return typeRec{Kind: typeAlias, Obj: e.obj(t2.Obj())}
+
+	case *types.TypeParam:
+		return typeRec{Kind: typeParam, Obj: e.obj(t2.Obj())}
+
+	case *types.Union:
+		rec := typeRec{Kind: typeUnion}
+		for i := range t2.Len() {
+			term := t2.Term(i)
+			rec.Types = append(rec.Types, e.typ(term.Type()))
+			rec.Flags = append(rec.Flags, term.Tilde())
+		}
+		return rec
+
+	case *types.Tuple:
+		rec := typeRec{Kind: typeTuple}
+		for v := range t2.Variables() {
+			rec.Types = append(rec.Types, e.typ(v.Type()))
+			rec.Names = append(rec.Names, v.Name())
+		}
+		return rec
+
+	default:
+		panic(terror.New(`unexpected type`).
+			With(`type`, t))
+	}
+}
+
+// sig records the given signature by its parameters and results
+// without a receiver, e.g. for a method of an interface.
+func (e *encoder) sig(sig *types.Signature) int {
+	e.snap.Types = append(e.snap.Types, e.sigRec(sig))
+	return len(e.snap.Types)
+}
+
+func (e *encoder) sigRec(sig *types.Signature) typeRec {
+	return typeRec{
+		Kind:     typeSignature,
+		Params:   e.tuple(sig.Params()),
+		Results:  e.tuple(sig.Results()),
+		Variadic: sig.Variadic(),
+	}
+}
+
+func (e *encoder) tuple(t *types.Tuple) int {
+	if t.Len() <= 0 {
+		return 0
+	}
+	return e.typ(t)
+}
+
+func (e *encoder) instRec(origin types.Type, args *types.TypeList) typeRec {
+	rec := typeRec{Kind: typeInstance, Elem: e.typ(origin)}
+	for arg := range args.Types() {
+		rec.Types = append(rec.Types, e.typ(arg))
+	}
+	return rec
+}
+
+func (e *encoder) loc(loc locs.Loc) *locRec {
+	if utils.IsNil(loc) {
+		return nil
+	}
+	if !loc.Pos().IsValid() {
+		return &locRec{}
+	}
+	rec := &locRec{}
+	rec.File, rec.Pos = e.pos(loc.Pos())
+	if loc.End().IsValid() {
+		rec.End = e.querier.FileSet().File(loc.Pos()).Offset(loc.End())
+		rec.Range = true
+	}
+	return rec
+}
+
+func (e *encoder) con(c constructs.Construct) int {
+	if utils.IsNil(c) {
+		return 0
+	}
+	if index, has := e.cons[c]; has {
+		if index <= 0 {
+			panic(terror.New(`construct depends on itself`).
+				With(`construct`, c))
+		}
+		return index
+	}
+	if !e.known[c] {
+		panic(terror.New(`construct is not in the project`).
+			With(`construct`, c))
+	}
+
+	e.cons[c] = 0
+	rec := e.conRec(c)
+	e.snap.Constructs = append(e.snap.Constructs, rec)
+	index := len(e.snap.Constructs)
+	e.cons[c] = index
+	e.order = append(e.order, c)
+	return index
+}
+
+func encodeAll[T constructs.Construct](e *encoder, cs []T) []int {
+	if len(cs) <= 0 {
+		return nil
+	}
+	result := make([]int, len(cs))
+	for i, c := range cs {
+		result[i] = e.con(c)
+	}
+	return result
+}
+
+func (e *encoder) conRec(c constructs.Construct) conRec {
+	rec := conRec{Kind: c.Kind()}
+	if bc, ok := c.(constructs.BuildConfigured); ok {
+		rec.Builds = slices.Clone(bc.BuildConfigs())
+	}
+
+	switch c.Kind() {
+	case kind.Abstract:
+		a := c.(constructs.Abstract)
+		rec.Name = a.Name()
+		rec.Exported = a.Exported()
+		rec.Signature = e.con(a.Signature())
+
+	case kind.Argument:
+		a := c.(constructs.Argument)
+		rec.Name = a.Name()
+		rec.Type = e.con(a.Type())
+
+	case kind.Basic:
+		rec.RealType = e.typ(c.(constructs.Basic).GoType())
+
+	case kind.Field:
+		f := c.(constructs.Field)
+		rec.Name = f.Name()
+		rec.Exported = f.Exported()
+		rec.Type = e.con(f.Type())
+		rec.Embedded = f.Embedded()
+
+	case kind.InterfaceDecl:
+		d := c.(constructs.InterfaceDecl)
+		rec.RealType = e.typ(d.GoType())
+		rec.Package = e.con(d.Package())
+		rec.Name = d.Name()
+		rec.Exported = d.Exported()
+		rec.Location = e.loc(d.Location())
+		rec.Nest = e.con(d.Nest())
+		rec.TestCode = d.TestCode()
+		rec.Generated = d.Generated()
+		rec.TypeParams = encodeAll(e, d.TypeParams())
+		rec.Interface = e.con(d.Interface())
+
+	case kind.InterfaceDesc:
+		d := c.(constructs.InterfaceDesc)
+		if len(d.AdditionalAbstracts()) > 0 || d.Inherits().Count() > 0 {
+			panic(terror.New(`interface has already been resolved`).
+				With(`interface`, d))
+		}
+		rec.Hint = d.Hint()
+		rec.RealType = e.typ(d.GoType())
+		rec.PinnedPkg = e.con(d.PinnedPackage())
+		rec.Abstracts = encodeAll(e, d.Abstracts())
+		rec.Exact = encodeAll(e, d.Exact())
+		rec.Approx = encodeAll(e, d.Approx())
+
+	case kind.InterfaceInst:
+		i := c.(constructs.InterfaceInst)
+		rec.RealType = e.typ(i.GoType())
+		rec.Generic = e.con(i.Generic())
+		rec.Resolved = e.con(i.Resolved())
+		rec.Implicit = encodeAll(e, i.ImplicitTypes())
+		rec.Instance = encodeAll(e, i.InstanceTypes())
+
+	case kind.Method:
+		m := c.(constructs.Method)
+		if fn := m.FuncType(); fn != nil {
+			rec.FuncType = e.obj(fn)
+		}
+		if sig, ok := c.(interface{ GoType() types.Type }).GoType().(*types.Signature); ok && sig != nil {
+			rec.RealType = e.typ(sig)
+		}
+		rec.Package = e.con(m.Package())
+		rec.Name = m.Name()
+		rec.Exported = m.Exported()
+		rec.Location = e.loc(m.Location())
+		rec.TestCode = m.TestCode()
+		rec.Generated = m.Generated()
+		rec.TypeParams = encodeAll(e, m.TypeParams())
+		rec.Signature = e.con(m.Signature())
+		rec.Metrics = e.con(m.Metrics())
+		rec.RecvName = m.ReceiverName()
+		rec.Receiver = e.con(m.Receiver())
+		rec.PointerRecv = m.PointerRecv()
+
+	case kind.MethodInst:
+		i := c.(constructs.MethodInst)
+		rec.Generic = e.con(i.Generic())
+		rec.Resolved = e.con(i.Resolved())
+		rec.Instance = encodeAll(e, i.InstanceTypes())
+		rec.Metrics = e.con(i.Metrics())
+
+	case kind.Metrics:
+		m := c.(constructs.Metrics)
+		rec.Location = e.loc(m.Location())
+		rec.TestCode = m.TestCode()
+		rec.Generated = m.Generated()
+		rec.Node = e.node(m)
+		for from, to := range m.TpReplacer() {
+			rec.TpReplacer = append(rec.TpReplacer, [2]int{e.typ(from), e.typ(to)})
+		}
+		slices.SortFunc(rec.TpReplacer, func(a, b [2]int) int { return a[0] - b[0] })
+		rec.Complexity = m.Complexity()
+		rec.LineCount = m.LineCount()
+		rec.CodeCount = m.CodeCount()
+		rec.Indents = m.Indents()
+		rec.Getter = m.Getter()
+		rec.Setter = m.Setter()
+		rec.SideEffect = m.SideEffect()
+		rec.Reads = encodeAll(e, m.Reads().ToSlice())
+		rec.Writes = encodeAll(e, m.Writes().ToSlice())
+		rec.Invokes = encodeAll(e, m.Invokes().ToSlice())
+
+	case kind.Object:
+		o := c.(constructs.Object)
+		if !utils.IsNil(o.Interface()) {
+			panic(terror.New(`object has already been resolved`).
+				With(`object`, o))
+		}
+		rec.RealType = e.typ(o.GoType())
+		rec.Package = e.con(o.Package())
+		rec.Name = o.Name()
+		rec.Exported = o.Exported()
+		rec.Location = e.loc(o.Location())
+		rec.Nest = e.con(o.Nest())
+		rec.TestCode = o.TestCode()
+		rec.Generated = o.Generated()
+		rec.TypeParams = encodeAll(e, o.TypeParams())
+		rec.Data = e.con(o.Data())
+		if fieldLocs := o.FieldLocations(); fieldLocs != nil {
+			rec.FieldLocs = make(map[string]*locRec, len(fieldLocs))
+			for name, loc := range fieldLocs {
+				rec.FieldLocs[name] = e.loc(loc)
+			}
+		}
+
+	case kind.ObjectInst:
+		i := c.(constructs.ObjectInst)
+		rec.RealType = e.typ(i.GoType())
+		rec.Generic = e.con(i.Generic())
+		rec.Data = e.con(i.ResolvedData())
+		rec.Implicit = encodeAll(e, i.ImplicitTypes())
+		rec.Instance = encodeAll(e, i.InstanceTypes())
+
+	case kind.Package:
+		p := c.(constructs.Package)
+		rec.Source = e.pkg(p.Source())
+		rec.Path = p.Path()
+		rec.Name = p.Name()
+		rec.ImportPaths = p.ImportPaths()
+		rec.Module = p.Module()
+		rec.EntryPoint = p.EntryPoint()
+
+	case kind.Selection:
+		s := c.(constructs.Selection)
+		rec.Name = s.Name()
+		rec.Origin = e.con(s.Origin())
+		rec.Target = e.con(s.Target())
+
+	case kind.Signature:
+		s := c.(constructs.Signature)
+		rec.RealType = e.typ(s.GoType())
+		rec.Variadic = s.Variadic()
+		rec.Params = encodeAll(e, s.Params())
+		rec.Results = encodeAll(e, s.Results())
+
+	case kind.StructDesc:
+		s := c.(constructs.StructDesc)
+		rec.RealType = e.typ(s.GoType())
+		rec.Fields = encodeAll(e, s.Fields())
+
+	case kind.TempDeclRef:
+		r := c.(constructs.TempDeclRef)
+		rec.Path = r.PackagePath()
+		rec.Name = r.Name()
+		rec.RecvName = r.Receiver()
+		rec.Implicit = encodeAll(e, r.ImplicitTypes())
+		rec.Instance = encodeAll(e, r.InstanceTypes())
+		rec.Nest = e.con(r.Nest())
+		if fn := r.FuncType(); fn != nil {
+			rec.FuncType = e.obj(fn)
+		}
+
+	case kind.TempReference:
+		r := c.(constructs.TempReference)
+		rec.RealType = e.typ(r.GoType())
+		rec.Path = r.PackagePath()
+		rec.Name = r.Name()
+		rec.Implicit = encodeAll(e, r.ImplicitTypes())
+		rec.Instance = encodeAll(e, r.InstanceTypes())
+		rec.Nest = e.con(r.Nest())
+
+	case kind.TempTypeParamRef:
+		r := c.(constructs.TempTypeParamRef)
+		rec.RealType = e.typ(r.GoType())
+		rec.Context = r.Context()
+		rec.Name = r.Name()
+
+	case kind.TypeParam:
+		tp := c.(constructs.TypeParam)
+		rec.Name = tp.Name()
+		rec.Type = e.con(tp.Type())
+
+	case kind.Value:
+		v := c.(constructs.Value)
+		rec.Package = e.con(v.Package())
+		rec.Name = v.Name()
+		rec.Exported = v.Exported()
+		rec.Location = e.loc(v.Location())
+		rec.Type = e.con(v.Type())
+		rec.Const = v.Const()
+		rec.TestCode = v.TestCode()
+		rec.Generated = v.Generated()
+		rec.Metrics = e.con(v.Metrics())
+
+	default:
+		panic(terror.New(`unexpected construct kind`).
+			With(`kind`, c.Kind()).
+			With(`construct`, c))
+	}
+	return rec
+}
+
+func (e *encoder) node(m constructs.Metrics) *nodeRec {
+	n := m.Node()
+	if utils.IsNil(n) {
+		return nil
+	}
+	tf := e.querier.FileSet().File(n.Pos())
+	if tf == nil || e.srcs.node(tf, n.Pos(), n.End(), nodeType(n)) != n {
+		panic(terror.New(`node of metrics can not be found by its position`).
+			With(`metrics`, m))
+	}
+	rec := &nodeRec{Type: nodeType(n)}
+	rec.File, rec.Pos = e.pos(n.Pos())
+	rec.End = tf.Offset(n.End())
+	return rec
+}
+
+// changes records the changes made to the given construct after it
+// was created, e.g. the resolution of a temporary reference.
+func (e *encoder) changes(c constructs.Construct, rec *conRec) {
+	switch c.Kind() {
+	case kind.Object:
+		o := c.(constructs.Object)
+		rec.Methods = encodeAll(e, o.Methods().ToSlice())
+
+	case kind.ObjectInst:
+		i := c.(constructs.ObjectInst)
+		rec.Methods = encodeAll(e, i.Methods().ToSlice())
+		rec.ResolvedInterface = e.con(i.ResolvedInterface())
+
+	case kind.MethodInst:
+		rec.InstReceiver = e.con(c.(constructs.MethodInst).Receiver())
+
+	case kind.Package:
+		p := c.(constructs.Package)
+		rec.Imports = encodeAll(e, p.Imports().ToSlice())
+		rec.Members = slices.Concat(
+			encodeAll(e, p.InterfaceDecls().ToSlice()),
+			encodeAll(e, p.Methods().ToSlice()),
+			encodeAll(e, p.Objects().ToSlice()),
+			encodeAll(e, p.Values().ToSlice()))
+
+	case kind.TempDeclRef:
+		r := c.(constructs.TempDeclRef)
+		rec.Resolution = e.con(r.ResolvedType())
+		rec.Dropped = r.Dropped()
+
+	case kind.TempReference:
+		rec.Resolution = e.con(c.(constructs.TempReference).ResolvedType())
+
+	case kind.TempTypeParamRef:
+		rec.Resolution = e.con(c.(constructs.TempTypeParamRef).ResolvedType())
+	}
+}
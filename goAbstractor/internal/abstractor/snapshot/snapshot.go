@@ -0,0 +1,227 @@
+package snapshot
+
+import (
+	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/querier"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/hint"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/kind"
+)
+
+// Snapshot is the constructs abstracted from some packages in a form
+// that can be cached and restored into a project in a later run.
+//
+// Anything that refers to the read packages, e.g. the real types, the
+// locations, and the nodes that metrics were read from, is kept as a
+// reference to a position in a file of one of the packages, so the
+// snapshot may only be restored for packages read from the same files.
+//
+// All references are one-based indices into the tables of the snapshot
+// where zero is no reference.
+type Snapshot struct {
+	Packages   []pkgRef  `json:"packages,omitempty"`
+	Files      []fileRef `json:"files,omitempty"`
+	Objects    []objRef  `json:"objects,omitempty"`
+	Types      []typeRec `json:"types,omitempty"`
+	Constructs []conRec  `json:"constructs,omitempty"`
+}
+
+// pkgRef is a package found in the imports of the source package at
+// the given unit index by the package's identifier, or the builtin
+// package that is baked in.
+type pkgRef struct {
+	Unit    int    `json:"unit,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Builtin bool   `json:"builtin,omitempty"`
+}
+
+// fileRef is a file in a package by the file's base name.
+// The base name is used, instead of the full path, since a package's
+// files may be parsed more than once, e.g. for a test variant.
+type fileRef struct {
+	Pkg  int    `json:"pkg"`
+	Name string `json:"name"`
+}
+
+// objRef is an object in the universe by name or an object defined at
+// the offset in a file by a package. The package is needed since the
+// variants of a package, e.g. `foo` and `foo [foo.test]`, share files
+// but each defines its own objects.
+type objRef struct {
+	Universe string `json:"universe,omitempty"`
+	Pkg      int    `json:"pkg,omitempty"`
+	File     int    `json:"file,omitempty"`
+	Offset   int    `json:"offset,omitempty"`
+}
+
+// typeRec is a real type. The fields used depend on the kind of type.
+type typeRec struct {
+	Kind     string   `json:"kind"`
+	Name     string   `json:"name,omitempty"`
+	Basic    int      `json:"basic,omitempty"`
+	Obj      int      `json:"obj,omitempty"`
+	Pkg      int      `json:"pkg,omitempty"`
+	Elem     int      `json:"elem,omitempty"`
+	Key      int      `json:"key,omitempty"`
+	Len      int64    `json:"len,omitempty"`
+	Dir      int      `json:"dir,omitempty"`
+	Types    []int    `json:"types,omitempty"`
+	Names    []string `json:"names,omitempty"`
+	Pkgs     []int    `json:"pkgs,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Flags    []bool   `json:"flags,omitempty"`
+	Params   int      `json:"params,omitempty"`
+	Results  int      `json:"results,omitempty"`
+	Variadic bool     `json:"variadic,omitempty"`
+	Implicit bool     `json:"implicit,omitempty"`
+}
+
+// The kinds of type records.
+const (
+	typeBasic      = `basic`
+	typePointer    = `pointer`
+	typeSlice      = `slice`
+	typeArray      = `array`
+	typeMap        = `map`
+	typeChan       = `chan`
+	typeStruct     = `struct`
+	typeInterface  = `interface`
+	typeComparable = `comparable`
+	typeSignature  = `signature`
+	typeFunc       = `func`
+	typeNamed      = `named`
+	typeAlias      = `alias`
+	typeParam      = `typeParam`
+	typeInstance   = `instance`
+	typeUnion      = `union`
+	typeTuple      = `tuple`
+)
+
+// locRec is a location in a file. When the file is zero the location
+// has no position. When not a range, the location has no end.
+type locRec struct {
+	File  int  `json:"file,omitempty"`
+	Pos   int  `json:"pos,omitempty"`
+	End   int  `json:"end,omitempty"`
+	Range bool `json:"range,omitempty"`
+}
+
+// nodeRec is the first node of the given type, e.g. `*ast.FuncDecl`,
+// with the given position and end in a file.
+type nodeRec struct {
+	File int    `json:"file"`
+	Pos  int    `json:"pos"`
+	End  int    `json:"end"`
+	Type string `json:"type"`
+}
+
+// conRec is a construct. The fields used depend on the kind of construct.
+//
+// The constructs are recorded in an order that any construct needed to
+// create another construct is recorded before it. The fields at the end
+// are changes made to a construct after it was created, which may refer
+// to constructs that are recorded after it.
+type conRec struct {
+	Kind kind.Kind `json:"kind"`
+
+	Name        string             `json:"name,omitempty"`
+	Path        string             `json:"path,omitempty"`
+	Module      string             `json:"module,omitempty"`
+	ImportPaths []string           `json:"importPaths,omitempty"`
+	Context     string             `json:"context,omitempty"`
+	RecvName    string             `json:"recvName,omitempty"`
+	Hint        hint.Hint          `json:"hint,omitempty"`
+	Exported    bool               `json:"exported,omitempty"`
+	EntryPoint  bool               `json:"entryPoint,omitempty"`
+	TestCode    bool               `json:"testCode,omitempty"`
+	Generated   bool               `json:"generated,omitempty"`
+	Embedded    bool               `json:"embedded,omitempty"`
+	Variadic    bool               `json:"variadic,omitempty"`
+	Const       bool               `json:"const,omitempty"`
+	PointerRecv bool               `json:"pointerRecv,omitempty"`
+	Source      int                `json:"source,omitempty"`
+	RealType    int                `json:"realType,omitempty"`
+	FuncType    int                `json:"funcType,omitempty"`
+	Location    *locRec            `json:"loc,omitempty"`
+	FieldLocs   map[string]*locRec `json:"fieldLocs,omitempty"`
+	Builds      []string           `json:"builds,omitempty"`
+
+	Package    int   `json:"package,omitempty"`
+	Type       int   `json:"type,omitempty"`
+	Signature  int   `json:"signature,omitempty"`
+	Nest       int   `json:"nest,omitempty"`
+	Generic    int   `json:"generic,omitempty"`
+	Resolved   int   `json:"resolved,omitempty"`
+	Interface  int   `json:"interface,omitempty"`
+	Data       int   `json:"data,omitempty"`
+	Metrics    int   `json:"metrics,omitempty"`
+	Receiver   int   `json:"receiver,omitempty"`
+	Origin     int   `json:"origin,omitempty"`
+	Target     int   `json:"target,omitempty"`
+	PinnedPkg  int   `json:"pinnedPkg,omitempty"`
+	TypeParams []int `json:"typeParams,omitempty"`
+	Implicit   []int `json:"implicit,omitempty"`
+	Instance   []int `json:"instance,omitempty"`
+	Params     []int `json:"params,omitempty"`
+	Results    []int `json:"results,omitempty"`
+	Abstracts  []int `json:"abstracts,omitempty"`
+	Exact      []int `json:"exact,omitempty"`
+	Approx     []int `json:"approx,omitempty"`
+	Fields     []int `json:"fields,omitempty"`
+
+	Node       *nodeRec `json:"node,omitempty"`
+	TpReplacer [][2]int `json:"tpReplacer,omitempty"`
+	Complexity int      `json:"complexity,omitempty"`
+	LineCount  int      `json:"lineCount,omitempty"`
+	CodeCount  int      `json:"codeCount,omitempty"`
+	Indents    int      `json:"indents,omitempty"`
+	Getter     bool     `json:"getter,omitempty"`
+	Setter     bool     `json:"setter,omitempty"`
+	SideEffect bool     `json:"sideEffect,omitempty"`
+	Reads      []int    `json:"reads,omitempty"`
+	Writes     []int    `json:"writes,omitempty"`
+	Invokes    []int    `json:"invokes,omitempty"`
+
+	Resolution        int   `json:"resolution,omitempty"`
+	Dropped           bool  `json:"dropped,omitempty"`
+	Imports           []int `json:"imports,omitempty"`
+	Members           []int `json:"members,omitempty"`
+	Methods           []int `json:"methods,omitempty"`
+	ResolvedInterface int   `json:"resolvedInterface,omitempty"`
+	InstReceiver      int   `json:"instReceiver,omitempty"`
+}
+
+// Take takes a snapshot of the constructs in the given project that were
+// abstracted from the given source packages. The project must not have
+// been resolved yet, other than any temporary references resolved while
+// abstracting. An error is returned if any construct can't be recorded,
+// e.g. a real type that was created while abstracting and therefore
+// can't be found again in a later run.
+func Take(querier *querier.Querier, proj constructs.Project, srcs []*packages.Package) (snap *Snapshot, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			snap, err = nil, terror.New(`failed to take a snapshot`, terror.RecoveredPanic(r))
+		}
+	}()
+	e := newEncoder(querier, srcs)
+	e.encode(proj)
+	return e.snap, nil
+}
+
+// Restore creates the constructs of this snapshot in the given project.
+// The source packages must be the packages, in the same order, that the
+// snapshot was taken from, read from the same files. An error is returned
+// if anything referred to can't be found in the source packages, in which
+// case the project should be discarded.
+func (snap *Snapshot) Restore(querier *querier.Querier, proj constructs.Project, srcs []*packages.Package) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = terror.New(`failed to restore a snapshot`, terror.RecoveredPanic(r))
+		}
+	}()
+	d := newDecoder(querier, proj, srcs, snap)
+	d.decode()
+	return nil
+}
@@ -0,0 +1,129 @@
+package snapshot
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// sources are the source packages and every package they import
+// with the files parsed for them.
+type sources struct {
+	fset  *token.FileSet
+	refs  map[*packages.Package]pkgRef
+	pkgs  map[pkgRef]*packages.Package
+	types map[*types.Package]*packages.Package
+	files map[*token.File]*ast.File
+	names map[*token.File]string
+	owner map[*token.File]*packages.Package
+	nodes map[*ast.File]map[nodeKey]ast.Node
+	defs  map[*packages.Package]map[token.Pos]types.Object
+}
+
+// nodeKey is the position, end, and type of a node.
+type nodeKey struct {
+	pos, end token.Pos
+	typ      string
+}
+
+// newSources finds the packages imported by each of the given source
+// packages in order. A package imported by more than one source package
+// is referenced by the first source package that imports it.
+func newSources(fset *token.FileSet, srcs []*packages.Package) *sources {
+	s := &sources{
+		fset:  fset,
+		refs:  map[*packages.Package]pkgRef{},
+		pkgs:  map[pkgRef]*packages.Package{},
+		types: map[*types.Package]*packages.Package{},
+		files: map[*token.File]*ast.File{},
+		names: map[*token.File]string{},
+		owner: map[*token.File]*packages.Package{},
+		nodes: map[*ast.File]map[nodeKey]ast.Node{},
+		defs:  map[*packages.Package]map[token.Pos]types.Object{},
+	}
+	for unit, src := range srcs {
+		packages.Visit([]*packages.Package{src}, func(pkg *packages.Package) bool {
+			if _, has := s.refs[pkg]; has {
+				return false
+			}
+			ref := pkgRef{Unit: unit, ID: pkg.ID}
+			s.refs[pkg] = ref
+			if _, has := s.pkgs[ref]; !has {
+				s.pkgs[ref] = pkg
+			}
+			if _, has := s.types[pkg.Types]; !has && pkg.Types != nil {
+				s.types[pkg.Types] = pkg
+			}
+			for _, f := range pkg.Syntax {
+				if tf := fset.File(f.FileStart); tf != nil {
+					s.files[tf] = f
+					s.names[tf] = filepath.Base(tf.Name())
+					s.owner[tf] = pkg
+				}
+			}
+			return true
+		}, nil)
+	}
+	return s
+}
+
+// file finds the file with the given base name in the given package.
+func (s *sources) file(pkg *packages.Package, name string) *token.File {
+	for _, f := range pkg.Syntax {
+		if tf := s.fset.File(f.FileStart); tf != nil && s.names[tf] == name {
+			return tf
+		}
+	}
+	return nil
+}
+
+// object finds the object defined by the given package at the given
+// position, or nil if the package doesn't define an object there.
+func (s *sources) object(pkg *packages.Package, pos token.Pos) types.Object {
+	index, has := s.defs[pkg]
+	if !has {
+		index = map[token.Pos]types.Object{}
+		if pkg.TypesInfo != nil {
+			for id, obj := range pkg.TypesInfo.Defs {
+				if obj != nil {
+					index[id.Pos()] = obj
+				}
+			}
+		}
+		s.defs[pkg] = index
+	}
+	return index[pos]
+}
+
+// node finds the first node of the given type with the given position
+// and end in the given file, or nil if there isn't one.
+func (s *sources) node(tf *token.File, pos, end token.Pos, typ string) ast.Node {
+	f, has := s.files[tf]
+	if !has {
+		return nil
+	}
+	index, has := s.nodes[f]
+	if !has {
+		index = map[nodeKey]ast.Node{}
+		ast.Inspect(f, func(n ast.Node) bool {
+			if n == nil {
+				return false
+			}
+			key := nodeKey{pos: n.Pos(), end: n.End(), typ: nodeType(n)}
+			if _, has := index[key]; !has {
+				index[key] = n
+			}
+			return true
+		})
+		s.nodes[f] = index
+	}
+	return index[nodeKey{pos: pos, end: end, typ: typ}]
+}
+
+func nodeType(n ast.Node) string {
+	return fmt.Sprintf(`%T`, n)
+}
@@ -0,0 +1,329 @@
+package abstractor
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/baker"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/snapshot"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/cache"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/project"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/logger"
+)
+
+// shard is the units for one package path, e.g. the package read for each
+// build configuration, that are abstracted by one worker into a project
+// of their own. The units for the same package path must be abstracted
+// together since they add to the same package construct.
+type shard struct {
+	units []int
+	proj  constructs.Project
+
+	// key is the key the shard is cached by, empty when not cached.
+	key string
+
+	// roots and diags are the roots and diagnostics found
+	// while abstracting each unit, keyed by the unit index.
+	roots map[int]map[string]string
+	diags map[int][]diagnostics.Diagnostic
+}
+
+// shards groups the indices of the given units by package path
+// in the order each package path is first found.
+func shards(units []unit) []*shard {
+	result := []*shard{}
+	byPath := map[string]*shard{}
+	for i, u := range units {
+		sh, has := byPath[u.src.PkgPath]
+		if !has {
+			sh = &shard{
+				roots: map[int]map[string]string{},
+				diags: map[int][]diagnostics.Diagnostic{},
+			}
+			byPath[u.src.PkgPath] = sh
+			result = append(result, sh)
+		}
+		sh.units = append(sh.units, i)
+	}
+	return result
+}
+
+// abstractConcurrently abstracts the given units using up to the number of
+// workers. Each shard of units is abstracted into a project of its own,
+// which only refers to the other packages by temporary references. Once all
+// the shards are abstracted, the projects, the roots, and the diagnostics are
+// joined in the order the units would have been abstracted serially so that
+// the result is identical to abstracting the units serially.
+//
+// When there is a cache, any shard that was cached is restored from
+// the cache instead of being abstracted, and only the packages for the
+// shards that weren't cached are prepared.
+//
+// The context is checked before each shard is abstracted. Any panic
+// from a worker is panicked again once all the workers have stopped.
+func (ab *abstractor) abstractConcurrently(units []unit, log, log2 *logger.Logger) {
+	ctx := ab.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	all := shards(units)
+	srcs := []*packages.Package{}
+	for _, sh := range all {
+		sh.key = ab.shardKey(sh, units)
+		if !ab.cache.Has(sh.key, cacheName) {
+			for _, i := range sh.units {
+				srcs = append(srcs, units[i].src)
+			}
+		}
+	}
+	ab.prepare(log, srcs)
+
+	workers := max(ab.workers, 1)
+	todo := make(chan *shard)
+	results := make(chan *shard)
+	panics := make(chan any, workers)
+
+	wg := &sync.WaitGroup{}
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panics <- r
+					// Drain the remaining shards so the feeder isn't blocked.
+					for range todo {
+					}
+				}
+			}()
+			for sh := range todo {
+				if err := ctx.Err(); err != nil {
+					panic(err)
+				}
+				ab.abstractShard(sh, units, log2)
+				results <- sh
+			}
+		}()
+	}
+
+	go func() {
+		for _, sh := range all {
+			todo <- sh
+		}
+		close(todo)
+		wg.Wait()
+		close(results)
+		close(panics)
+	}()
+
+	done, total := 0, len(units)
+	ab.progress.Packages(diagnostics.Abstract, done, total)
+	for sh := range results {
+		done += len(sh.units)
+		ab.progress.Packages(diagnostics.Abstract, done, total)
+	}
+	if r := <-panics; r != nil {
+		panic(r)
+	}
+	if ab.cache != nil {
+		log.Logf(`cached packages (hits=%d, misses=%d)`, ab.cache.Hits(), ab.cache.Misses())
+	}
+
+	projects := make([]constructs.Project, len(all))
+	for i, sh := range all {
+		projects[i] = sh.proj
+	}
+	ab.proj.Join(projects...)
+
+	unitShards := make([]*shard, len(units))
+	for _, sh := range all {
+		for _, i := range sh.units {
+			unitShards[i] = sh
+		}
+	}
+	for i, sh := range unitShards {
+		for symbol, reason := range sh.roots[i] {
+			ab.addRoot(symbol, reason)
+		}
+		for _, d := range sh.diags[i] {
+			ab.proj.Diagnostics().Add(d)
+		}
+	}
+}
+
+// abstractShard abstracts the units in the given shard into a new project
+// and resolves the receivers of the methods. If the shard was cached, the
+// constructs are restored from the cache instead of being abstracted.
+// Otherwise, the abstracted constructs are added to the cache.
+func (ab *abstractor) abstractShard(sh *shard, units []unit, log *logger.Logger) {
+	if ab.restoreShard(sh, units, log) {
+		return
+	}
+
+	proj := ab.newShardProject()
+	worker := &abstractor{
+		querier:   ab.querier,
+		baker:     baker.New(proj),
+		proj:      proj,
+		prepared:  ab.prepared,
+		typeCache: map[any]any{},
+
+		reflectionRoots: ab.reflectionRoots,
+	}
+	failed := false
+	for _, i := range sh.units {
+		prior := len(proj.Diagnostics().Diagnostics())
+		worker.roots = map[string]string{}
+		worker.curBuild = units[i].build
+		worker.abstractPackage(units[i].src, log)
+		sh.roots[i] = worker.roots
+		sh.diags[i] = proj.Diagnostics().Diagnostics()[prior:]
+		failed = failed || len(sh.diags[i]) > 0
+	}
+
+	// Any shard with failures isn't cached so that
+	// the failures are reported again in the next run.
+	if !failed {
+		ab.storeShard(sh, units, proj, log)
+	}
+	resolveReceivers(proj)
+	sh.proj = proj
+}
+
+// newShardProject creates a project for a shard that
+// shares the locations with the resulting project.
+func (ab *abstractor) newShardProject() constructs.Project {
+	proj := project.New(ab.proj.Locs())
+	proj.Diagnostics().SetTolerant(ab.proj.Diagnostics().Tolerant())
+	proj.Diagnostics().SetContext(ab.ctx)
+	return proj
+}
+
+// resolveReceivers resolves the receivers while the methods and objects
+// are in the smaller project. Any failure is left to be found again and
+// reported when the receivers are resolved in the joined project.
+func resolveReceivers(proj constructs.Project) {
+	for pkg := range proj.Packages().Enumerate().Seq() {
+		func() {
+			defer func() { _ = recover() }()
+			pkg.ResolveReceivers()
+		}()
+	}
+}
+
+// cacheName is the name the abstracted shards are cached with.
+const cacheName = `abstract`
+
+// cachedShard is the abstraction of a shard that is cached.
+type cachedShard struct {
+
+	// Roots are the roots found while abstracting each unit in the shard.
+	Roots []map[string]string `json:"roots"`
+
+	// Snapshot is the constructs abstracted from the units.
+	Snapshot *snapshot.Snapshot `json:"snapshot"`
+}
+
+// shardKey gets the key to cache the given shard by. The key depends on
+// the packages in the shard and anything else that changes how they are
+// abstracted, or is empty when there isn't a cache.
+func (ab *abstractor) shardKey(sh *shard, units []unit) string {
+	if ab.cache == nil {
+		return ``
+	}
+	parts := []string{
+		`reflectionRoots=` + strconv.FormatBool(ab.reflectionRoots),
+		`runtime=` + strconv.FormatBool(ab.querier.IsAbstracted(`runtime`)),
+		`syscall=` + strconv.FormatBool(ab.querier.IsAbstracted(`syscall`)),
+	}
+	for _, i := range sh.units {
+		src := units[i].src
+		key := ab.cache.Key(src)
+		if len(key) <= 0 {
+			return ``
+		}
+		parts = append(parts, `unit `+units[i].build+` `+key+` `+strconv.FormatBool(ab.querier.IsRoot(src)))
+	}
+	return cache.Join(parts...)
+}
+
+// shardSources gets the packages for the units in the given shard.
+func shardSources(sh *shard, units []unit) []*packages.Package {
+	srcs := make([]*packages.Package, len(sh.units))
+	for j, i := range sh.units {
+		srcs[j] = units[i].src
+	}
+	return srcs
+}
+
+// restoreShard restores the given shard from the cache.
+// Returns false if the shard wasn't cached or couldn't be restored.
+func (ab *abstractor) restoreShard(sh *shard, units []unit, log *logger.Logger) bool {
+	if ab.cache == nil {
+		return false
+	}
+	cached := cachedShard{}
+	if !ab.cache.Load(sh.key, cacheName, &cached) {
+		return false
+	}
+
+	srcs := shardSources(sh, units)
+	log.Logf(`restore package: %s`, srcs[0].PkgPath)
+	if cached.Snapshot == nil || len(cached.Roots) != len(srcs) {
+		log.Warnf(`cached package is invalid: %s`, srcs[0].PkgPath)
+		ab.cache.Reject(sh.key, cacheName)
+		return false
+	}
+
+	// The package's construct has the source of the first unit,
+	// so the files of every unit are aliased with that source.
+	for _, src := range srcs {
+		for _, f := range src.Syntax {
+			ab.aliasFile(srcs[0], f)
+		}
+	}
+
+	proj := ab.newShardProject()
+	if err := cached.Snapshot.Restore(ab.querier, proj, srcs); err != nil {
+		log.Warnf(`failed to restore cached package %s: %v`, srcs[0].PkgPath, err)
+		ab.cache.Reject(sh.key, cacheName)
+		return false
+	}
+	for j, i := range sh.units {
+		sh.roots[i] = cached.Roots[j]
+	}
+	resolveReceivers(proj)
+	sh.proj = proj
+	return true
+}
+
+// storeShard adds the constructs abstracted for the given shard
+// to the cache. Any failure to store the shard is only logged
+// since the shard will simply be abstracted again in the next run.
+func (ab *abstractor) storeShard(sh *shard, units []unit, proj constructs.Project, log *logger.Logger) {
+	if ab.cache == nil || len(sh.key) <= 0 {
+		return
+	}
+	srcs := shardSources(sh, units)
+	snap, err := snapshot.Take(ab.querier, proj, srcs)
+	if err != nil {
+		log.Warnf(`failed to cache package %s: %v`, srcs[0].PkgPath, err)
+		return
+	}
+	cached := cachedShard{
+		Roots:    make([]map[string]string, len(sh.units)),
+		Snapshot: snap,
+	}
+	for j, i := range sh.units {
+		cached.Roots[j] = sh.roots[i]
+	}
+	if err := ab.cache.Store(sh.key, cacheName, cached); err != nil {
+		log.Warnf(`failed to cache package %s: %v`, srcs[0].PkgPath, err)
+	}
+}
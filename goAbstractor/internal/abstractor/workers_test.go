@@ -0,0 +1,156 @@
+package abstractor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/baker"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/querier"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/cache"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/kind"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/project"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/reader"
+)
+
+func Test_Snapshot_RoundTrip(t *testing.T) {
+	dirs, err := filepath.Glob(`../../../testData/go/test*`)
+	check.NoError(t).Require(err)
+	check.NotEmpty(t).Name(`fixtures`).Require(dirs)
+
+	// Every kind of construct must be restored by at least one fixture
+	// so that a field missing from the snapshot of any kind is found.
+	// The object and method instances aren't since those are only created
+	// once the references are resolved, after the snapshot is taken.
+	kinds := map[string]bool{}
+	for _, dir := range dirs {
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			rc := &reader.Config{
+				Dir:        dir,
+				Patterns:   fixturePatterns(t, dir),
+				BuildFlags: []string{`-tags=test`},
+				Tests:      true,
+				SkipBroken: true,
+			}
+			ps, _, err := reader.Read(rc)
+			check.NoError(t).Require(err)
+
+			c, err := cache.New(t.TempDir())
+			check.NoError(t).Require(err)
+			check.NoError(t).Require(c.Add(rc.BuildFlags, ps))
+
+			// The shards are abstracted and cached, then restored from
+			// the cache, and each restored shard must be the same.
+			// Any shard with a failure isn't cached so is abstracted again.
+			abstracted, failed := abstractFixtureShards(t, dir, c, ps)
+			misses := c.Misses()
+			restored, _ := abstractFixtureShards(t, dir, c, ps)
+			check.Equal(t, misses+failed).Name(`restored misses`).Assert(c.Misses())
+			check.Equal(t, len(abstracted)).Name(`shards`).Require(len(restored))
+			for i := range abstracted {
+				check.Equal(t, abstracted[i]).Name(`restored shard`).With(`index`, i).Assert(restored[i])
+			}
+
+			for _, shard := range restored {
+				data := map[string]any{}
+				check.NoError(t).Require(json.Unmarshal([]byte(shard), &data))
+				for key := range data {
+					kinds[key] = true
+				}
+			}
+		})
+	}
+
+	missing := []string{}
+	for _, k := range []kind.Kind{
+		kind.Abstract, kind.Argument, kind.Basic, kind.Field,
+		kind.InterfaceDecl, kind.InterfaceDesc, kind.InterfaceInst,
+		kind.Method, kind.Metrics, kind.Object,
+		kind.Package, kind.Selection, kind.Signature,
+		kind.StructDesc, kind.TempDeclRef, kind.TempReference,
+		kind.TempTypeParamRef, kind.TypeParam, kind.Value,
+	} {
+		if !kinds[k.Plural()] {
+			missing = append(missing, k.Plural())
+		}
+	}
+	check.Empty(t).Name(`kinds not restored`).Assert(missing)
+}
+
+// fixturePatterns gets the patterns to read a fixture with. A fixture with
+// a module or workspace is read as a whole, otherwise each file is read.
+func fixturePatterns(t *testing.T, dir string) []string {
+	if _, err := os.Stat(filepath.Join(dir, `go.work`)); err == nil {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, `go.mod`)); err == nil {
+		return []string{`./...`}
+	}
+	files, err := filepath.Glob(filepath.Join(dir, `*.go`))
+	check.NoError(t).Require(err)
+	patterns := []string{}
+	for _, file := range files {
+		if !strings.HasSuffix(file, `_test.go`) {
+			patterns = append(patterns, filepath.Base(file))
+		}
+	}
+	return patterns
+}
+
+// abstractFixtureShards abstracts the shards of the packages read from
+// the fixture's directory, skipping the packages the fixture depends on,
+// using the given cache. Each shard is returned as the JSON of the shard's
+// project with all the constructs and their debug information, along with
+// the number of shards that had failures, which are tolerated.
+func abstractFixtureShards(t *testing.T, dir string, c *cache.Cache, ps []*packages.Package) ([]string, int) {
+	absDir, err := filepath.Abs(dir)
+	check.NoError(t).Require(err)
+
+	q := querier.New(ps)
+	proj := project.New(locs.NewSet(q.FileSet()))
+	proj.Diagnostics().SetTolerant(true)
+	ab := &abstractor{
+		querier:   q,
+		baker:     baker.New(proj),
+		proj:      proj,
+		typeCache: map[any]any{},
+		cache:     c,
+	}
+
+	units := ab.units()
+	result, failed := []string{}, 0
+	for _, sh := range shards(units) {
+		src := units[sh.units[0]].src
+		if len(src.GoFiles) <= 0 || !strings.HasPrefix(src.GoFiles[0], absDir) {
+			continue
+		}
+
+		sh.key = ab.shardKey(sh, units)
+		ab.abstractShard(sh, units, nil)
+		for _, diags := range sh.diags {
+			if len(diags) > 0 {
+				failed++
+				break
+			}
+		}
+		sh.proj.UpdateIndices(false)
+
+		ctx := jsonify.NewContext().
+			SetKeepDuplicates(true).
+			SetIncludeRanges(true).
+			IncludeDebugIndex(true).
+			IncludeDebugAlive(true).
+			IncludeDebugReceiver(true)
+		data, err := jsonify.Marshal(ctx, sh.proj)
+		check.NoError(t).Require(err)
+		result = append(result, string(data))
+	}
+	return result, failed
+}
@@ -0,0 +1,262 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/reader"
+)
+
+// version is part of every key so that changing the format of the
+// cached results or how the results are calculated invalidates the cache.
+const version = `3`
+
+// Cache is an on-disk cache of per-package results.
+//
+// Each package is keyed by a hash of the package path, the content of
+// the package's files, the build flags and the environment of the Go
+// toolchain the package was read with, and the keys of all the packages
+// it imports. Any change to a package will therefore change the keys for
+// it and all packages depending on it.
+//
+// The keys are added before the cache is used so that the cache may be
+// read and written concurrently.
+type Cache struct {
+	dir    string
+	keys   map[*packages.Package]string
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// New creates a cache in the given directory.
+// The directory is created if it doesn't exist.
+func New(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Cache{
+		dir:  dir,
+		keys: map[*packages.Package]string{},
+	}, nil
+}
+
+// Open creates a cache in the given directory and keys the packages read
+// with the given config. The packages read without a build configuration
+// are keyed by the build flags and the target of the read, and the packages
+// read for each build configuration are keyed by the flags for that
+// configuration, so that the results cached for one target are never used
+// for another. All the packages are also keyed by the environment of the
+// Go toolchain they were read with, e.g. the Go version, CGO_ENABLED,
+// GOFLAGS, and GOEXPERIMENT.
+func Open(dir string, rc *reader.Config, ps []*packages.Package,
+	builds map[string][]*packages.Package) (*Cache, error) {
+	c, err := New(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(ps) > 0 {
+		env, err := reader.GoEnv(rc)
+		if err != nil {
+			return nil, err
+		}
+		if err = c.Add(append(reader.DefaultFlags(rc.BuildFlags), env...), ps); err != nil {
+			return nil, err
+		}
+	}
+	for _, bc := range rc.BuildConfigs {
+		env, err := bc.GoEnv(rc)
+		if err != nil {
+			return nil, err
+		}
+		if err = c.Add(append(bc.Flags(rc.BuildFlags), env...), builds[bc.Name]); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Add keys the given packages and all their dependencies that were read
+// with the given build flags. The build flags should include anything,
+// such as the target operating system and the environment of the Go
+// toolchain, that changes how the packages are read.
+// Packages read with different flags are added separately.
+func (c *Cache) Add(buildFlags []string, pkgs []*packages.Package) error {
+	var err error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if err == nil {
+			err = c.addKey(pkg, buildFlags)
+		}
+	})
+	return err
+}
+
+// addKey determines the key for the given package.
+// The imports must have already been keyed.
+func (c *Cache) addKey(pkg *packages.Package, buildFlags []string) error {
+	h := sha256.New()
+	writeLine := func(parts ...string) {
+		_, _ = io.WriteString(h, strings.Join(parts, ` `)+"\n")
+	}
+
+	writeLine(`version`, version)
+	writeLine(`flags`, strings.Join(buildFlags, ` `))
+	writeLine(`package`, pkg.PkgPath, pkg.Name)
+
+	files := slices.Clone(pkg.CompiledGoFiles)
+	if len(files) <= 0 {
+		files = slices.Clone(pkg.GoFiles)
+	}
+	slices.Sort(files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return terror.New(`failed to read file for cache key`, err).
+				With(`package`, pkg.PkgPath).
+				With(`file`, file)
+		}
+		sum := sha256.Sum256(data)
+		writeLine(`file`, filepath.Base(file), hex.EncodeToString(sum[:]))
+	}
+
+	imports := make([]string, 0, len(pkg.Imports))
+	for _, imp := range pkg.Imports {
+		key, has := c.keys[imp]
+		if !has {
+			return terror.New(`import was not keyed prior to the package importing it`).
+				With(`package`, pkg.PkgPath).
+				With(`import`, imp.PkgPath)
+		}
+		imports = append(imports, key)
+	}
+	slices.Sort(imports)
+	for _, key := range imports {
+		writeLine(`import`, key)
+	}
+
+	c.keys[pkg] = hex.EncodeToString(h.Sum(nil))
+	return nil
+}
+
+// Key gets the key for the given package or empty if the package is unknown.
+func (c *Cache) Key(pkg *packages.Package) string {
+	if c == nil {
+		return ``
+	}
+	return c.keys[pkg]
+}
+
+// Join gets a key for results that depend on all the given parts,
+// e.g. the keys of several packages. Returns empty if any part is empty.
+func Join(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		if len(part) <= 0 {
+			return ``
+		}
+		_, _ = io.WriteString(h, part+"\n")
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Hits gets the number of results that have been loaded from the cache.
+func (c *Cache) Hits() int {
+	if c == nil {
+		return 0
+	}
+	return int(c.hits.Load())
+}
+
+// Misses gets the number of results that had to be recalculated
+// because they weren't cached or couldn't be used.
+func (c *Cache) Misses() int {
+	if c == nil {
+		return 0
+	}
+	return int(c.misses.Load())
+}
+
+func (c *Cache) path(key, name string) string {
+	return filepath.Join(c.dir, name+`-`+key+`.json`)
+}
+
+// Has determines if there are named results cached for the given key.
+func (c *Cache) Has(key, name string) bool {
+	if c == nil || len(key) <= 0 {
+		return false
+	}
+	_, err := os.Stat(c.path(key, name))
+	return err == nil
+}
+
+// Load reads the named results for the given key into the given value.
+// Returns false if there are no cached results or they could not be read,
+// in which case the results should be recalculated.
+func (c *Cache) Load(key, name string, value any) bool {
+	if c == nil {
+		return false
+	}
+	if len(key) <= 0 || !c.load(key, name, value) {
+		c.misses.Add(1)
+		return false
+	}
+	c.hits.Add(1)
+	return true
+}
+
+func (c *Cache) load(key, name string, value any) bool {
+	data, err := os.ReadFile(c.path(key, name))
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, value) == nil
+}
+
+// Reject removes the named results for the given key that were loaded but
+// could not be used, so the load is counted as a miss instead of a hit.
+func (c *Cache) Reject(key, name string) {
+	if c == nil || len(key) <= 0 {
+		return
+	}
+	c.hits.Add(-1)
+	c.misses.Add(1)
+	_ = os.Remove(c.path(key, name))
+}
+
+// Store writes the named results for the given key.
+func (c *Cache) Store(key, name string, value any) error {
+	if c == nil || len(key) <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file then rename it so that a partially written
+	// file is never read by another run using the same cache directory.
+	path := c.path(key, name)
+	tmp, err := os.CreateTemp(c.dir, filepath.Base(path)+`.*`)
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
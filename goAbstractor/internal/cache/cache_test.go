@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/reader"
+)
+
+func Test_Open_KeyedByGoEnv(t *testing.T) {
+	rc := &reader.Config{
+		Dir:        `../../../testData/go/test0001`,
+		Patterns:   []string{`main.go`},
+		BuildFlags: []string{`-tags=test`},
+	}
+	ps, _, err := reader.Read(rc)
+	check.NoError(t).Require(err)
+
+	key := func(cgo, flags, experiment string) string {
+		t.Setenv(`CGO_ENABLED`, cgo)
+		t.Setenv(`GOFLAGS`, flags)
+		t.Setenv(`GOEXPERIMENT`, experiment)
+		c, err := Open(t.TempDir(), rc, ps, nil)
+		check.NoError(t).Require(err)
+		k := c.Key(ps[0])
+		check.NotEmpty(t).Name(`key`).Assert(k)
+		return k
+	}
+
+	// The packages read with a different toolchain environment
+	// must not use the results cached for another environment.
+	base := key(`1`, ``, ``)
+	check.Equal(t, base).Name(`same environment`).Assert(key(`1`, ``, ``))
+	check.NotEqual(t, base).Name(`CGO_ENABLED`).Assert(key(`0`, ``, ``))
+	check.NotEqual(t, base).Name(`GOFLAGS`).Assert(key(`1`, `-trimpath`, ``))
+	check.NotEqual(t, base).Name(`GOEXPERIMENT`).Assert(key(`1`, ``, `loopvar`))
+}
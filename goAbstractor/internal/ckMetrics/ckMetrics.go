@@ -0,0 +1,465 @@
+package ckMetrics
+
+import (
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+)
+
+// Report is the Chidamber and Kemerer (CK) object-oriented metrics for the
+// objects, and instances of those objects, declared in the read packages.
+//
+// Go doesn't have classes or subclassing, so the metrics are interpreted
+// with an object as the class, the methods with the object as the receiver
+// as the methods of the class, and the fields of the object's struct as the
+// attributes of the class. Embedding an object and satisfying an interface
+// are used in place of extending a class.
+type Report struct {
+	Objects []*Entry
+}
+
+// Entry is the CK metrics for an object or object instance.
+type Entry struct {
+	// Name is the qualified name of the object, e.g. `example.com/foo.Bar`,
+	// or object instance, e.g. `example.com/foo.Bar[int]`.
+	Name string
+
+	// Instance indicates the metrics are for an object instance.
+	// The methods of an instance are the method instances, with the same
+	// bodies as the generic methods, and the fields are the resolved fields.
+	Instance bool
+
+	// Loc is the location of the object.
+	Loc locs.Loc
+
+	// WMC is the Weighted Methods per Class,
+	// the sum of the cyclomatic complexity of the methods.
+	WMC int
+
+	// CBO is the Coupling Between Objects, the number of other objects that
+	// this object is coupled to. Two objects are coupled when either one
+	// has a field of the other's type, has the other as a type argument,
+	// or has a method that reads, writes, or invokes the other,
+	// as determined by constructs.ObjectOf.
+	CBO int
+
+	// RFC is the Response For a Class, the number of methods in the object
+	// plus the number of other methods, including interface abstracts
+	// selected via a variable, that those methods invoke.
+	RFC int
+
+	// LCOM1 is the Lack of Cohesion in Methods as first defined by CK,
+	// the number of pairs of methods that don't use any of the same fields.
+	LCOM1 int
+
+	// LCOM2 is the Lack of Cohesion in Methods as later defined by CK,
+	// the number of pairs of methods that don't use any of the same fields
+	// minus the number of pairs that do, or zero if that is negative.
+	LCOM2 int
+
+	// LCOM3 is the Lack of Cohesion in Methods as defined by Li and Henry,
+	// the number of groups of methods connected by using the same fields.
+	LCOM3 int
+
+	// LCOM4 is the Lack of Cohesion in Methods as defined by Hitz and
+	// Montazeri, the number of groups of methods connected by using the
+	// same fields or by one method invoking the other.
+	LCOM4 int
+
+	// LCOM5 is the Lack of Cohesion in Methods as defined by Henderson-Sellers,
+	// `(a/f - m)/(1 - m)` where `m` is the number of methods, `f` is the number
+	// of fields, and `a` is the sum of the number of methods that use each field.
+	// This is zero when there are no fields or less than two methods.
+	LCOM5 float64
+
+	// DIT is the Depth of Inheritance Tree, the longest path from the object
+	// through the objects it embeds, or through the interfaces that the
+	// object's own interface inherits. The empty interface isn't counted,
+	// so an object that embeds no objects and whose interface inherits no
+	// other interface has a depth of zero.
+	DIT int
+
+	// NOC is the Number Of Children, the number of objects in the read
+	// packages that directly embed the object or a pointer to the object.
+	// For a generic object this includes embedding any of its instances.
+	NOC int
+}
+
+// subject is an object or object instance being measured.
+type subject struct {
+	entry    *Entry
+	self     constructs.TypeDesc
+	object   constructs.Object
+	data     constructs.StructDesc
+	it       constructs.InterfaceDesc
+	typeArgs []constructs.TypeDesc
+	methods  []member
+}
+
+// member is a method of a subject.
+type member struct {
+	method  constructs.Method
+	metrics constructs.Metrics
+}
+
+// New creates the CK metrics report for the given project.
+// Only the objects and methods that are measured are included,
+// such that an object's methods in test files aren't counted by default.
+func New(proj constructs.Project, measure constructs.Measure) *Report {
+	subjects := []*subject{}
+	for obj := range proj.Objects().Enumerate().Seq() {
+		if obj.Duplicate() || !obj.Package().EntryPoint() || !measure.Measured(obj) {
+			continue
+		}
+
+		s := &subject{
+			entry:  &Entry{Name: constructs.QualifiedName(obj), Loc: obj.Location()},
+			self:   obj,
+			object: obj,
+			data:   obj.Data(),
+			it:     obj.Interface(),
+		}
+		for m := range obj.Methods().Enumerate().Seq() {
+			if measure.Measured(m) {
+				s.methods = append(s.methods, member{method: m, metrics: m.Metrics()})
+			}
+		}
+		subjects = append(subjects, s)
+
+		for inst := range obj.Instances().Enumerate().Seq() {
+			if inst.Duplicate() {
+				continue
+			}
+			s := &subject{
+				entry: &Entry{
+					Name:     types.TypeString(inst.GoType(), (*types.Package).Path),
+					Instance: true,
+					Loc:      obj.Location(),
+				},
+				self:     inst,
+				object:   obj,
+				data:     inst.ResolvedData(),
+				it:       inst.ResolvedInterface(),
+				typeArgs: append(slices.Clone(inst.ImplicitTypes()), inst.InstanceTypes()...),
+			}
+			for m := range inst.Methods().Enumerate().Seq() {
+				if !measure.Measured(m.Generic()) {
+					continue
+				}
+				metrics := m.Metrics()
+				if utils.IsNil(metrics) {
+					metrics = m.Generic().Metrics()
+				}
+				s.methods = append(s.methods, member{method: m.Generic(), metrics: metrics})
+			}
+			subjects = append(subjects, s)
+		}
+	}
+
+	afferent := map[constructs.Object]map[constructs.Object]bool{}
+	efferent := make([]map[constructs.Object]bool, len(subjects))
+	children := map[constructs.Construct]int{}
+	for i, s := range subjects {
+		efferent[i] = s.coupled()
+		for other := range efferent[i] {
+			if afferent[other] == nil {
+				afferent[other] = map[constructs.Object]bool{}
+			}
+			afferent[other][s.object] = true
+		}
+		if s.entry.Instance {
+			continue
+		}
+		for _, f := range fields(s.data) {
+			if f.Embedded() {
+				children[unwrapPointer(f.Type())]++
+			}
+		}
+	}
+
+	depths := map[constructs.InterfaceDesc]int{}
+	for i, s := range subjects {
+		coupled := efferent[i]
+		for other := range afferent[s.object] {
+			coupled[other] = true
+		}
+		delete(coupled, s.object)
+
+		s.entry.WMC = s.weightedMethods()
+		s.entry.CBO = len(coupled)
+		s.entry.RFC = s.responses()
+		s.cohesion()
+		s.entry.DIT = inheritanceDepth(s.data, s.it, depths, map[constructs.Object]bool{s.object: true})
+		s.entry.NOC = children[s.self]
+		if !s.entry.Instance {
+			for inst := range s.object.Instances().Enumerate().Seq() {
+				s.entry.NOC += children[inst]
+			}
+		}
+	}
+
+	r := &Report{}
+	for _, s := range subjects {
+		r.Objects = append(r.Objects, s.entry)
+	}
+	slices.SortStableFunc(r.Objects, func(a, b *Entry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return r
+}
+
+func fields(data constructs.StructDesc) []constructs.Field {
+	if utils.IsNil(data) {
+		return nil
+	}
+	return data.Fields()
+}
+
+// unwrapPointer gets the object or object instance a pointer is to,
+// otherwise the given type is returned.
+func unwrapPointer(td constructs.TypeDesc) constructs.TypeDesc {
+	// ObjectOf only gets an object from an interface instance
+	// when the instance is a pointer to the object.
+	if it, ok := td.(constructs.InterfaceInst); ok && constructs.ObjectOf(it) != nil {
+		return it.InstanceTypes()[0]
+	}
+	return td
+}
+
+// usages enumerates the reads, writes, and invokes of the given metrics.
+func usages(metrics constructs.Metrics, handle func(c constructs.Construct)) {
+	if utils.IsNil(metrics) {
+		return
+	}
+	for c := range metrics.Reads().Enumerate().Seq() {
+		handle(c)
+	}
+	for c := range metrics.Writes().Enumerate().Seq() {
+		handle(c)
+	}
+	for c := range metrics.Invokes().Enumerate().Seq() {
+		handle(c)
+	}
+}
+
+// coupled gets the objects that this subject uses, including itself.
+func (s *subject) coupled() map[constructs.Object]bool {
+	coupled := map[constructs.Object]bool{}
+	add := func(c constructs.Construct) {
+		if obj := constructs.ObjectOf(c); obj != nil {
+			coupled[obj] = true
+		}
+	}
+	for _, f := range fields(s.data) {
+		add(f.Type())
+	}
+	for _, td := range s.typeArgs {
+		add(td)
+	}
+	for _, m := range s.methods {
+		usages(m.metrics, add)
+	}
+	return coupled
+}
+
+func (s *subject) weightedMethods() int {
+	sum := 0
+	for _, m := range s.methods {
+		if !utils.IsNil(m.metrics) {
+			sum += m.metrics.Complexity()
+		}
+	}
+	return sum
+}
+
+// ownMethod gets the method of this subject that the given construct
+// invokes, or nil if the construct isn't one of this subject's methods.
+func (s *subject) ownMethod(c constructs.Construct) constructs.Method {
+	switch t := c.(type) {
+	case constructs.Method:
+		if t.Receiver() == s.object {
+			return t
+		}
+	case constructs.MethodInst:
+		return s.ownMethod(t.Generic())
+	case constructs.Selection:
+		if constructs.ObjectOf(t.Origin()) == s.object {
+			for _, m := range s.methods {
+				if m.method.Name() == t.Name() {
+					return m.method
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (s *subject) responses() int {
+	set := map[constructs.Construct]bool{}
+	for _, m := range s.methods {
+		set[m.method] = true
+	}
+	for _, m := range s.methods {
+		if utils.IsNil(m.metrics) {
+			continue
+		}
+		for c := range m.metrics.Invokes().Enumerate().Seq() {
+			if s.ownMethod(c) != nil {
+				continue
+			}
+			switch t := c.(type) {
+			case constructs.Method, constructs.Selection:
+				set[t] = true
+			case constructs.MethodInst:
+				set[t.Generic()] = true
+			}
+		}
+	}
+	return len(set)
+}
+
+// fieldsUsed gets the names of the fields of this subject
+// that the given method reads or writes.
+func (s *subject) fieldsUsed(m member) map[string]bool {
+	names := map[string]bool{}
+	for _, f := range fields(s.data) {
+		names[f.Name()] = true
+	}
+	used := map[string]bool{}
+	usages(m.metrics, func(c constructs.Construct) {
+		if sel, ok := c.(constructs.Selection); ok && names[sel.Name()] &&
+			constructs.ObjectOf(sel.Origin()) == s.object {
+			used[sel.Name()] = true
+		}
+	})
+	return used
+}
+
+// cohesion sets the lack of cohesion in methods metrics.
+func (s *subject) cohesion() {
+	count := len(s.methods)
+	used := make([]map[string]bool, count)
+	for i, m := range s.methods {
+		used[i] = s.fieldsUsed(m)
+	}
+
+	byFields := newGroups(count)
+	byCalls := newGroups(count)
+	shared, unshared := 0, 0
+	for i := range count {
+		for j := i + 1; j < count; j++ {
+			if sharesField(used[i], used[j]) {
+				shared++
+				byFields.join(i, j)
+				byCalls.join(i, j)
+			} else {
+				unshared++
+			}
+		}
+	}
+
+	index := map[constructs.Method]int{}
+	for i, m := range s.methods {
+		index[m.method] = i
+	}
+	for i, m := range s.methods {
+		if utils.IsNil(m.metrics) {
+			continue
+		}
+		for c := range m.metrics.Invokes().Enumerate().Seq() {
+			// The invoked method may be one that isn't measured,
+			// e.g. a method in a generated file when that is excluded.
+			if j, has := index[s.ownMethod(c)]; has {
+				byCalls.join(i, j)
+			}
+		}
+	}
+
+	s.entry.LCOM1 = unshared
+	s.entry.LCOM2 = max(unshared-shared, 0)
+	s.entry.LCOM3 = byFields.count()
+	s.entry.LCOM4 = byCalls.count()
+
+	fieldCount := len(fields(s.data))
+	if fieldCount > 0 && count > 1 {
+		accesses := 0
+		for _, u := range used {
+			accesses += len(u)
+		}
+		mean := float64(accesses) / float64(fieldCount)
+		s.entry.LCOM5 = (mean - float64(count)) / (1.0 - float64(count))
+	}
+}
+
+func sharesField(a, b map[string]bool) bool {
+	for name := range a {
+		if b[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// inheritanceDepth gets the longest path through the embedded objects
+// or the interfaces that the given interface inherits.
+func inheritanceDepth(data constructs.StructDesc, it constructs.InterfaceDesc, depths map[constructs.InterfaceDesc]int, visiting map[constructs.Object]bool) int {
+	depth := interfaceDepth(it, depths)
+	for _, f := range fields(data) {
+		if !f.Embedded() {
+			continue
+		}
+		obj := constructs.ObjectOf(f.Type())
+		if obj == nil || visiting[obj] {
+			continue
+		}
+		visiting[obj] = true
+		depth = max(depth, 1+inheritanceDepth(obj.Data(), obj.Interface(), depths, visiting))
+		delete(visiting, obj)
+	}
+	return depth
+}
+
+func interfaceDepth(it constructs.InterfaceDesc, depths map[constructs.InterfaceDesc]int) int {
+	if utils.IsNil(it) {
+		return 0
+	}
+	if depth, has := depths[it]; has {
+		return depth
+	}
+	depth := 0
+	for parent := range it.Inherits().Enumerate().Seq() {
+		depth = max(depth, 1+interfaceDepth(parent, depths))
+	}
+	depths[it] = depth
+	return depth
+}
+
+func (r *Report) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	if r == nil {
+		return nil
+	}
+	return jsonify.New(ctx, r.Objects)
+}
+
+func (e *Entry) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `name`, e.Name).
+		AddNonZero(ctx, `instance`, e.Instance).
+		AddNonZero(ctx, `loc`, e.Loc).
+		Add(ctx, `wmc`, e.WMC).
+		Add(ctx, `cbo`, e.CBO).
+		Add(ctx, `rfc`, e.RFC).
+		Add(ctx, `lcom1`, e.LCOM1).
+		Add(ctx, `lcom2`, e.LCOM2).
+		Add(ctx, `lcom3`, e.LCOM3).
+		Add(ctx, `lcom4`, e.LCOM4).
+		Add(ctx, `lcom5`, e.LCOM5).
+		Add(ctx, `dit`, e.DIT).
+		Add(ctx, `noc`, e.NOC)
+}
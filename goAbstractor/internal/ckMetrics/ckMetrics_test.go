@@ -0,0 +1,98 @@
+package ckMetrics_test
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/collections"
+	"github.com/Snow-Gremlin/goToolbox/collections/sortedSet"
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/ckMetrics"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/project"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+)
+
+func Test_CKMetrics_InvokedUnmeasuredMethod(t *testing.T) {
+	fs := token.NewFileSet()
+	file := fs.AddFile(`counter.go`, -1, 100)
+	file.SetLines([]int{0, 10, 20, 30})
+	ls := locs.NewSet(fs)
+	proj := project.New(ls)
+	loc := ls.NewLoc(file.Pos(0))
+	tPkg := types.NewPackage(`example.com/a`, `a`)
+	pkg := proj.NewPackage(constructs.PackageArgs{
+		RealPkg:    &packages.Package{PkgPath: tPkg.Path(), Name: tPkg.Name(), Types: tPkg},
+		Path:       tPkg.Path(),
+		Name:       tPkg.Name(),
+		EntryPoint: true,
+	})
+	intType := proj.NewBasic(constructs.BasicArgs{RealType: types.Typ[types.Int]})
+	data := proj.NewStructDesc(constructs.StructDescArgs{
+		Fields: []constructs.Field{
+			proj.NewField(constructs.FieldArgs{Name: `a`, Type: intType}),
+			proj.NewField(constructs.FieldArgs{Name: `b`, Type: intType}),
+		},
+		Package: pkg.Source(),
+	})
+	obj := proj.NewObject(constructs.ObjectArgs{
+		RealType: types.NewNamed(types.NewTypeName(token.NoPos, tPkg, `Counter`, nil), data.GoType(), nil),
+		Package:  pkg,
+		Name:     `Counter`,
+		Exported: true,
+		Location: loc,
+		Data:     data,
+	})
+	sig := proj.NewSignature(constructs.SignatureArgs{Package: pkg.Source()})
+	usages := func(cs ...constructs.Construct) collections.SortedSet[constructs.Construct] {
+		set := sortedSet.New(constructs.Comparer[constructs.Construct]())
+		set.Add(cs...)
+		return set
+	}
+	addMethod := func(name string, offset int, generated bool, reads, invokes []constructs.Construct) constructs.Method {
+		loc := ls.NewLoc(file.Pos(offset))
+		return obj.AddMethod(proj.NewMethod(constructs.MethodArgs{
+			Package:    pkg,
+			Name:       name,
+			Exported:   true,
+			Location:   loc,
+			Generated:  generated,
+			TypeParams: []constructs.TypeParam{},
+			Signature:  sig,
+			Metrics: proj.NewMetrics(constructs.MetricsArgs{
+				Location:   loc,
+				Generated:  generated,
+				Node:       &ast.BlockStmt{},
+				Complexity: 1,
+				Reads:      usages(reads...),
+				Writes:     usages(),
+				Invokes:    usages(invokes...),
+			}),
+			Receiver: obj,
+		}))
+	}
+
+	// First and Second use different fields, and Second invokes the String
+	// method in a generated file. When the generated code is excluded,
+	// String isn't one of the measured methods, so Second invoking it
+	// doesn't connect Second to First.
+	str := addMethod(`String`, 10, true, nil, nil)
+	addMethod(`First`, 20, false, []constructs.Construct{
+		proj.NewSelection(constructs.SelectionArgs{Name: `a`, Origin: obj}),
+	}, nil)
+	addMethod(`Second`, 30, false, []constructs.Construct{
+		proj.NewSelection(constructs.SelectionArgs{Name: `b`, Origin: obj}),
+	}, []constructs.Construct{str})
+
+	lcom4 := func(measure constructs.Measure) int {
+		r := ckMetrics.New(proj, measure)
+		check.Length(t, 1).Require(r.Objects)
+		return r.Objects[0].LCOM4
+	}
+	check.Equal(t, 2).Name(`measured`).Assert(lcom4(constructs.Measure{}))
+	check.Equal(t, 2).Name(`generated excluded`).Assert(lcom4(constructs.Measure{ExcludeGenerated: true}))
+}
@@ -0,0 +1,37 @@
+package ckMetrics
+
+// groups is a disjoint set of indices used to count
+// the groups of methods that are connected.
+type groups struct {
+	parents []int
+}
+
+func newGroups(count int) *groups {
+	parents := make([]int, count)
+	for i := range parents {
+		parents[i] = i
+	}
+	return &groups{parents: parents}
+}
+
+func (g *groups) find(i int) int {
+	for g.parents[i] != i {
+		g.parents[i] = g.parents[g.parents[i]]
+		i = g.parents[i]
+	}
+	return i
+}
+
+func (g *groups) join(i, j int) {
+	g.parents[g.find(i)] = g.find(j)
+}
+
+func (g *groups) count() int {
+	count := 0
+	for i, p := range g.parents {
+		if i == p {
+			count++
+		}
+	}
+	return count
+}
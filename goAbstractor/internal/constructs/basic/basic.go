@@ -73,10 +73,11 @@ func (t *basicImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	if !ctx.KeepDuplicates() && t.Duplicate() {
 		return nil
 	}
-	if ctx.IsDebugKindIncluded() || ctx.IsDebugIndexIncluded() {
+	if ctx.IsDebugKindIncluded() || ctx.IsDebugIndexIncluded() || ctx.IncludeStableIDs() {
 		return jsonify.NewMap().
 			AddIf(ctx, ctx.IsDebugKindIncluded(), `kind`, t.Kind()).
 			AddIf(ctx, ctx.IsDebugIndexIncluded(), `index`, t.Index()).
+			AddNonZeroIf(ctx, ctx.IncludeStableIDs(), `id`, t.StableID()).
 			AddIf(ctx, ctx.IsDebugAliveIncluded(), `alive`, t.Alive()).
 			Add(ctx, `name`, t.realType.Name())
 	}
@@ -0,0 +1,45 @@
+package constructs
+
+import "slices"
+
+// BuildConfigured is a construct that records which of the build
+// configurations, e.g. `linux/amd64`, the construct was found in.
+type BuildConfigured interface {
+	// BuildConfigs gets the sorted names of the build configurations.
+	// This is empty when the project was read with only one unnamed
+	// build configuration.
+	BuildConfigs() []string
+
+	// AddBuildConfigs adds the given names of build configurations.
+	// Empty names are ignored.
+	AddBuildConfigs(names ...string)
+}
+
+// BuildConfigSet is the set of names of build configurations
+// that a construct was found in.
+type BuildConfigSet struct {
+	names []string
+}
+
+func (b *BuildConfigSet) BuildConfigs() []string { return b.names }
+
+func (b *BuildConfigSet) AddBuildConfigs(names ...string) {
+	for _, name := range names {
+		if len(name) <= 0 {
+			continue
+		}
+		if i, found := slices.BinarySearch(b.names, name); !found {
+			b.names = slices.Insert(b.names, i, name)
+		}
+	}
+}
+
+// mergeBuildConfigs adds the build configurations from the duplicate
+// construct to the construct the duplicate is being replaced with.
+func mergeBuildConfigs(kept, dup Construct) {
+	k, ok1 := kept.(BuildConfigured)
+	d, ok2 := dup.(BuildConfigured)
+	if ok1 && ok2 {
+		k.AddBuildConfigs(d.BuildConfigs()...)
+	}
+}
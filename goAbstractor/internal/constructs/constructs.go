@@ -11,6 +11,7 @@ import (
 	"github.com/Snow-Gremlin/goToolbox/utils"
 
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/assert"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/hint"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/kind"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/stringer"
@@ -34,6 +35,14 @@ type Construct interface {
 	// SetIndex sets the index of construct.
 	SetIndex(index int)
 
+	// StableID gets the identifier derived from the content of the
+	// construct, empty if unset. The identifier will not change when
+	// unrelated constructs are added or removed from the project.
+	StableID() string
+
+	// SetStableID sets the stable identifier of the construct.
+	SetStableID(id string)
+
 	// Duplicate is a flag set when this construct is identical to another
 	// existing construct (one of the identical ones will not be marked as
 	// a duplicate). Duplicates happen when one of the duplicates had a
@@ -50,6 +59,18 @@ type Construct interface {
 
 	// SetAlive sets if the given construct is alive.
 	SetAlive(alive bool)
+
+	// AliveReason is the reason this construct is a root of the dead-code
+	// elimination, e.g. `main` or `exported API`. This is empty if the
+	// construct is dead or was only kept alive because it was used.
+	AliveReason() string
+
+	// AliveBy is the declaration or package that used this construct and
+	// kept it alive. This is nil if the construct is dead or is a root.
+	AliveBy() Construct
+
+	// SetAliveReason sets why this construct was kept alive.
+	SetAliveReason(reason string, by Construct)
 }
 
 // TempReferenceContainer is any construct that can contain a temporary reference.
@@ -81,6 +102,26 @@ type DuplicateReplacer interface {
 	ReplaceDuplicate(m map[Construct]Construct)
 }
 
+// DuplicateMerger is a construct that collects other constructs, e.g. the
+// instances of a generic, which must not be lost when a construct from
+// another project is found to be a duplicate of it.
+type DuplicateMerger interface {
+
+	// MergeDuplicate adds the constructs collected by the given duplicate
+	// into this construct. The duplicate must be the same kind of construct.
+	MergeDuplicate(dup Construct)
+}
+
+// RolledBackRemover is a construct that collects other constructs, e.g.
+// the declarations of a package or the instances of a generic, which may
+// have been added by a declaration that failed and was rolled back.
+type RolledBackRemover interface {
+
+	// RemoveRolledBack removes any of the given rolled back constructs
+	// that have been collected by this construct.
+	RemoveRolledBack(removed map[Construct]bool)
+}
+
 // NestType is a type that can be nested inside another type.
 type NestType interface {
 	Construct
@@ -254,13 +295,19 @@ func ResolvedTempDeclRef(con Construct, required bool) (Construct, bool) {
 	return resolved, changed
 }
 
+// ResolveTempDeclRefSet replaces the temporary references in the given set
+// with what they resolved to. Any dropped reference is removed from the set.
 func ResolveTempDeclRefSet(set collections.SortedSet[Construct], required bool) bool {
 	changed := false
-	slice := slices.Clone(set.ToSlice())
-	for i, s := range slice {
+	slice := make([]Construct, 0, set.Count())
+	for _, s := range set.ToSlice() {
+		if tr, ok := s.(TempDeclRef); ok && tr.Dropped() {
+			changed = true
+			continue
+		}
 		ref, subChanged := ResolvedTempDeclRef(s, required)
 		changed = changed || subChanged
-		slice[i] = ref
+		slice = append(slice, ref)
 	}
 	assert.ArgHasNoNils(`resolved refs`, slice)
 	if changed {
@@ -300,15 +347,34 @@ func FindReplacementElems[T Construct, S ~[]T](m map[Construct]Construct, s S) {
 	}
 }
 
+// FindReplacementInSet replaces any duplicate in the given set.
+//
+// The items in the set may have changed since they were added, e.g. by
+// references being resolved, so the set may no longer be in order.
+// Instead of overwriting the duplicates in place, the set is refilled so
+// that the replacements are added in order and no duplicate is left behind.
 func FindReplacementInSet[T Construct, S collections.SortedSet[T]](m map[Construct]Construct, s S) {
-	reps := []T{}
-	for c := range s.Enumerate().Seq() {
+	items := slices.Clone(s.ToSlice())
+	found := false
+	for i, c := range items {
 		if rep, ok := m[c]; ok {
-			reps = append(reps, rep.(T))
+			items[i] = rep.(T)
+			found = true
 		}
 	}
-	if len(reps) > 0 {
-		s.Overwrite(reps...)
+	if found {
+		s.Clear()
+		s.Add(items...)
+	}
+}
+
+// RemoveFromSet removes any of the given removed constructs from the set.
+func RemoveFromSet[T Construct, S collections.SortedSet[T]](removed map[Construct]bool, s S) {
+	items := slices.Clone(s.ToSlice())
+	kept := slices.DeleteFunc(items, func(c T) bool { return removed[c] })
+	if len(kept) < s.Count() {
+		s.Clear()
+		s.Add(kept...)
 	}
 }
 
@@ -320,18 +386,81 @@ func Cast[TOut, TIn Construct, S ~[]TIn](s S) []TOut {
 	return tps
 }
 
+// ObjectOf gets the object that the given construct is a usage of,
+// either the object, an instance of the object, a pointer to the object,
+// a selection from the object, a value with the object as its type,
+// or a method with the object as its receiver.
+// Returns nil if the construct is not a usage of an object.
+func ObjectOf(c Construct) Object {
+	if utils.IsNil(c) {
+		return nil
+	}
+	switch t := c.(type) {
+	case Object:
+		return t
+	case ObjectInst:
+		return t.Generic()
+	case InterfaceInst:
+		if t.Generic().Interface().Hint() == hint.Pointer && len(t.InstanceTypes()) == 1 {
+			return ObjectOf(t.InstanceTypes()[0])
+		}
+	case Method:
+		return t.Receiver()
+	case MethodInst:
+		return ObjectOf(t.Generic())
+	case Selection:
+		return ObjectOf(t.Origin())
+	case Value:
+		return ObjectOf(t.Type())
+	}
+	return nil
+}
+
+// SelectedObject gets the object of the given construct that is being
+// selected from, similar to ObjectOf. When the construct is a selection
+// of a field, e.g. the `b` in `a.b.c`, this is the object of the type of
+// the field instead of the object the field is selected from.
+func SelectedObject(c Construct) Object {
+	sel, ok := c.(Selection)
+	if !ok {
+		return ObjectOf(c)
+	}
+	obj := SelectedObject(sel.Origin())
+	if utils.IsNil(obj) || utils.IsNil(obj.Data()) {
+		return nil
+	}
+	for _, f := range obj.Data().Fields() {
+		if f.Name() == sel.Name() {
+			return ObjectOf(f.Type())
+		}
+	}
+	return nil
+}
+
 // ConstructCore is a shared data and methods for all constructs that
 // may be embedded into a construct to quickly implement this data.
 type ConstructCore struct {
-	index     int
-	duplicate bool
-	alive     bool
+	index       int
+	stableID    string
+	duplicate   bool
+	alive       bool
+	aliveReason string
+	aliveBy     Construct
 }
 
-func (c *ConstructCore) Index() int      { return c.index }
-func (c *ConstructCore) Duplicate() bool { return c.duplicate }
-func (c *ConstructCore) Alive() bool     { return c.alive }
+func (c *ConstructCore) Index() int          { return c.index }
+func (c *ConstructCore) StableID() string    { return c.stableID }
+func (c *ConstructCore) Duplicate() bool     { return c.duplicate }
+func (c *ConstructCore) Alive() bool         { return c.alive }
+func (c *ConstructCore) AliveReason() string { return c.aliveReason }
+func (c *ConstructCore) AliveBy() Construct  { return c.aliveBy }
 
 func (c *ConstructCore) SetIndex(index int)          { c.index = index }
+func (c *ConstructCore) SetStableID(id string)       { c.stableID = id }
 func (c *ConstructCore) SetDuplicate(duplicate bool) { c.duplicate = duplicate }
 func (c *ConstructCore) SetAlive(alive bool)         { c.alive = alive }
+
+func (c *ConstructCore) SetAliveReason(reason string, by Construct) {
+	c.aliveReason = reason
+	c.aliveBy = by
+}
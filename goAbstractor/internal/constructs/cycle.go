@@ -0,0 +1,66 @@
+package constructs
+
+import (
+	"slices"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+)
+
+// DependencyKind is how one declaration depends on another.
+type DependencyKind string
+
+const (
+	// DependencyRead is a read of the other declaration in the metrics.
+	DependencyRead DependencyKind = `reads`
+
+	// DependencyWrite is a write of the other declaration in the metrics.
+	DependencyWrite DependencyKind = `writes`
+
+	// DependencyInvoke is an invocation of the other declaration in the metrics.
+	DependencyInvoke DependencyKind = `invokes`
+
+	// DependencyField is a field with a type that references the other declaration.
+	DependencyField DependencyKind = `field`
+
+	// DependencySignature is a parameter or result with a type
+	// that references the other declaration.
+	DependencySignature DependencyKind = `signature`
+)
+
+// Cycle is a strongly connected component of two or more declarations
+// that all depend on each other, directly or indirectly.
+type Cycle struct {
+	// Declarations are the declarations participating in the cycle.
+	Declarations []Declaration
+
+	// Dependencies are the dependencies between the declarations
+	// in the cycle. Dependencies leaving the cycle are not included.
+	Dependencies []*Dependency
+}
+
+// Dependency is a dependency from one declaration to another
+// with all the ways that the one declaration depends on the other.
+type Dependency struct {
+	From  Declaration
+	To    Declaration
+	Kinds []DependencyKind
+}
+
+func (c *Cycle) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `size`, len(c.Declarations)).
+		Add(ctx.Short(), `declarations`, JsonSet(ctx.Short(), c.Declarations)).
+		AddNonZero(ctx, `dependencies`, c.Dependencies)
+}
+
+func (d *Dependency) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	kinds := make([]string, len(d.Kinds))
+	for i, k := range d.Kinds {
+		kinds[i] = string(k)
+	}
+	slices.Sort(kinds)
+	return jsonify.NewMap().
+		Add(ctx.Short(), `from`, d.From).
+		Add(ctx.Short(), `to`, d.To).
+		Add(ctx, `kinds`, kinds)
+}
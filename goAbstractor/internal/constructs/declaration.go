@@ -1,10 +1,15 @@
 package constructs
 
-import "github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+import (
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+)
 
 // Declaration is a type, value, or method declaration with a name.
 type Declaration interface {
 	Construct
+	BuildConfigured
 
 	// IsDeclaration indicates that the type is a Declaration at compile time.
 	// This prevents anything else from duck-typing into a Declaration.
@@ -14,6 +19,13 @@ type Declaration interface {
 	Name() string
 	Exported() bool
 	Location() locs.Loc
+
+	// TestCode indicates the declaration is defined in a test file.
+	TestCode() bool
+
+	// Generated indicates the declaration is defined in a generated file.
+	Generated() bool
+
 	Type() TypeDesc
 }
 
@@ -24,3 +36,39 @@ type TypeDecl interface {
 	TypeDesc
 	Nestable
 }
+
+// QualifiedName gets the name of the given declaration qualified by its
+// package path, e.g. `example.com/foo.Bar`, and any receiver or nest,
+// see LocalName, e.g. `example.com/foo.Bar.Baz`.
+func QualifiedName(decl Declaration) string {
+	return decl.Package().Path() + `.` + LocalName(decl)
+}
+
+// LocalName gets the name of the given declaration within its package.
+// The name of a method with a receiver is prefixed by the receiver,
+// e.g. `Bar.Baz`, and the name of a declaration nested in a function is
+// prefixed by the local name of that function and a colon, e.g. `Baz:Bar`.
+// The init and blank functions, which are numbered in the order they
+// were found, are named as they were declared.
+func LocalName(decl Declaration) string {
+	name := decl.Name()
+	if m, ok := decl.(Method); ok {
+		switch {
+		case m.IsInit():
+			name = `init`
+		case m.IsBlank():
+			name = `_`
+		}
+		if m.HasReceiver() {
+			name = m.ReceiverName() + `.` + name
+		}
+	}
+	if n, ok := decl.(Nestable); ok && !utils.IsNil(n.Nest()) {
+		nestName := n.Nest().Name()
+		if nest, ok := n.Nest().(Declaration); ok {
+			nestName = LocalName(nest)
+		}
+		name = nestName + `:` + name
+	}
+	return name
+}
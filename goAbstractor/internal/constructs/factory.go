@@ -23,6 +23,10 @@ type FactoryCore[T Construct] struct {
 	kind     kind.Kind
 	comparer comp.Comparer[T]
 	items    collections.SortedSet[T]
+
+	// added is the constructs in the order they were added,
+	// so that the constructs added after a point can be rolled back.
+	added []T
 }
 
 var _ Factory = (*FactoryCore[Abstract])(nil)
@@ -38,10 +42,37 @@ func NewFactoryCore[T Construct](kind kind.Kind, comparer comp.Comparer[T]) *Fac
 func (f *FactoryCore[T]) Kind() kind.Kind { return f.kind }
 
 func (f *FactoryCore[T]) Add(item T) T {
-	v, _ := f.items.TryAdd(item)
+	v, added := f.items.TryAdd(item)
+	if added {
+		f.added = append(f.added, v)
+	}
 	return v
 }
 
+// Added is the number of constructs that have been added to the factory.
+func (f *FactoryCore[T]) Added() int { return len(f.added) }
+
+// AddedSince gets the constructs that were added to the factory
+// after the given number of constructs had been added.
+func (f *FactoryCore[T]) AddedSince(added int) []T {
+	return f.added[min(added, len(f.added)):]
+}
+
+// Rollback removes the constructs that were added to the factory after
+// the given number of constructs had been added. The removed constructs
+// are set in the given map.
+func (f *FactoryCore[T]) Rollback(added int, removed map[Construct]bool) {
+	since := f.AddedSince(added)
+	if len(since) <= 0 {
+		return
+	}
+	for _, c := range since {
+		removed[c] = true
+	}
+	f.added = f.added[:added]
+	RemoveFromSet(removed, f.items)
+}
+
 func (f *FactoryCore[T]) Items() collections.SortedSet[T] { return f.items }
 
 func (f *FactoryCore[T]) Enumerate() collections.Enumerator[Construct] {
@@ -53,6 +84,7 @@ func (f *FactoryCore[T]) Dedup(m map[Construct]Construct) {
 	dupFound := false
 	for c := range f.items.Enumerate().Seq() {
 		if kept, added := reduced.TryAdd(c); !added {
+			mergeBuildConfigs(kept, c)
 			c.SetDuplicate(true)
 			dupFound = true
 			m[c] = kept
@@ -66,6 +98,24 @@ func (f *FactoryCore[T]) Dedup(m map[Construct]Construct) {
 	f.items = reduced
 }
 
+// Merge adds the items from the other factory, which must be for the same
+// kind, into this factory. Any item from the other factory that is the same
+// as an item in this factory is added to the given map with the item it
+// should be replaced with, and anything the duplicate collected is merged
+// into the item it is replaced with.
+func (f *FactoryCore[T]) Merge(other Factory, m map[Construct]Construct) {
+	for c := range other.Enumerate().Seq() {
+		if kept, added := f.items.TryAdd(c.(T)); !added {
+			mergeBuildConfigs(kept, c)
+			if dm, ok := any(kept).(DuplicateMerger); ok {
+				dm.MergeDuplicate(c)
+			}
+			c.SetDuplicate(true)
+			m[c] = kept
+		}
+	}
+}
+
 func (f *FactoryCore[T]) String() string {
 	buf := &strings.Builder{}
 	buf.WriteString(f.Kind().Plural())
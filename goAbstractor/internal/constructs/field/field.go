@@ -28,6 +28,7 @@ func newField(args constructs.FieldArgs) constructs.Field {
 		name:     args.Name,
 		exported: args.Exported,
 		typ:      args.Type,
+		embedded: args.Embedded,
 	}
 }
 
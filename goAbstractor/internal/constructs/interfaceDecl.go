@@ -42,6 +42,12 @@ type InterfaceDeclArgs struct {
 	Location locs.Loc
 	Nest     NestType
 
+	// TestCode indicates the declaration is defined in a test file.
+	TestCode bool
+
+	// Generated indicates the declaration is defined in a generated file.
+	Generated bool
+
 	TypeParams []TypeParam
 	Interface  InterfaceDesc
 }
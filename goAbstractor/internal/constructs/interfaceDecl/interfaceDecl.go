@@ -21,12 +21,15 @@ import (
 
 type interfaceDeclImp struct {
 	constructs.ConstructCore
-	realType types.Type
-	pkg      constructs.Package
-	name     string
-	exported bool
-	loc      locs.Loc
-	nest     constructs.NestType
+	constructs.BuildConfigSet
+	realType  types.Type
+	pkg       constructs.Package
+	name      string
+	exported  bool
+	loc       locs.Loc
+	testCode  bool
+	generated bool
+	nest      constructs.NestType
 
 	typeParams []constructs.TypeParam
 	inter      constructs.InterfaceDesc
@@ -56,6 +59,8 @@ func newInterfaceDecl(args constructs.InterfaceDeclArgs) constructs.InterfaceDec
 		name:       args.Name,
 		exported:   args.Exported,
 		loc:        args.Location,
+		testCode:   args.TestCode,
+		generated:  args.Generated,
 		typeParams: args.TypeParams,
 		inter:      args.Interface,
 		nest:       args.Nest,
@@ -72,6 +77,8 @@ func (d *interfaceDeclImp) GoType() types.Type { return d.realType }
 func (d *interfaceDeclImp) Name() string       { return d.name }
 func (d *interfaceDeclImp) Exported() bool     { return d.exported }
 func (d *interfaceDeclImp) Location() locs.Loc { return d.loc }
+func (d *interfaceDeclImp) TestCode() bool     { return d.testCode }
+func (d *interfaceDeclImp) Generated() bool    { return d.generated }
 
 func (d *interfaceDeclImp) Package() constructs.Package         { return d.pkg }
 func (d *interfaceDeclImp) Type() constructs.TypeDesc           { return d.inter }
@@ -99,6 +106,10 @@ func (d *interfaceDeclImp) AddInstance(inst constructs.InterfaceInst) constructs
 	return v
 }
 
+func (d *interfaceDeclImp) MergeDuplicate(dup constructs.Construct) {
+	d.instances.AddFrom(dup.(*interfaceDeclImp).instances.Enumerate())
+}
+
 func (d *interfaceDeclImp) RemoveTempDeclRefs(required bool) bool {
 	if !utils.IsNil(d.nest) {
 		nest, changed := constructs.ResolvedTempDeclRef(d.nest, required)
@@ -136,6 +147,10 @@ func (d *interfaceDeclImp) ReplaceDuplicate(m map[constructs.Construct]construct
 	constructs.FindReplacementInSet(m, d.instances)
 }
 
+func (d *interfaceDeclImp) RemoveRolledBack(removed map[constructs.Construct]bool) {
+	constructs.RemoveFromSet(removed, d.instances)
+}
+
 func (d *interfaceDeclImp) CompareTo(other constructs.Construct) int {
 	return constructs.CompareTo[constructs.InterfaceDecl](d, other, Comparer())
 }
@@ -172,12 +187,19 @@ func (d *interfaceDeclImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	return jsonify.NewMap().
 		AddIf(ctx, ctx.IsDebugKindIncluded(), `kind`, d.Kind()).
 		AddIf(ctx, ctx.IsDebugIndexIncluded(), `index`, d.Index()).
+		AddNonZeroIf(ctx, ctx.IncludeStableIDs(), `id`, d.StableID()).
 		AddIf(ctx, ctx.IsDebugAliveIncluded(), `alive`, d.Alive()).
 		Add(ctx.OnlyIndex(), `package`, d.pkg).
 		Add(ctx, `name`, d.name).
 		Add(ctx.OnlyIndex(), `interface`, d.inter).
 		AddNonZero(ctx, `loc`, d.loc).
+		AddNonZeroIf(ctx, ctx.IncludeRanges(), `range`, locs.RangeOf(d.loc)).
 		AddNonZeroIf(ctx, d.exported, `vis`, `exported`).
+		AddNonZero(ctx, `test`, d.testCode).
+		AddNonZero(ctx, `generated`, d.generated).
+		AddNonZero(ctx, `builds`, d.BuildConfigs()).
+		AddNonZeroIf(ctx, ctx.IncludeAliveReasons(), `aliveReason`, d.AliveReason()).
+		AddNonZeroIf(ctx.Short(), ctx.IncludeAliveReasons(), `aliveBy`, d.AliveBy()).
 		AddNonZero(ctx.OnlyIndex(), `typeParams`, d.typeParams).
 		AddNonZero(ctx.OnlyIndex(), `nest`, d.nest).
 		AddNonZero(ctx.OnlyIndex(), `instances`, constructs.JsonSet(ctx.OnlyIndex(), d.instances.ToSlice()))
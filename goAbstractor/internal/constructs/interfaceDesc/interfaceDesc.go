@@ -232,6 +232,7 @@ func (id *interfaceDescImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	return jsonify.NewMap().
 		AddIf(ctx, ctx.IsDebugKindIncluded(), `kind`, id.Kind()).
 		AddIf(ctx, ctx.IsDebugIndexIncluded(), `index`, id.Index()).
+		AddNonZeroIf(ctx, ctx.IncludeStableIDs(), `id`, id.StableID()).
 		AddIf(ctx, ctx.IsDebugAliveIncluded(), `alive`, id.Alive()).
 		AddNonZero(ctx.Short(), `pin`, id.pinnedPkg).
 		AddNonZero(ctx.OnlyIndex(), `abstracts`, constructs.JsonSet(ctx.OnlyIndex(), ab)).
@@ -207,6 +207,7 @@ func (i *instanceImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	return jsonify.NewMap().
 		AddIf(ctx, ctx.IsDebugKindIncluded(), `kind`, i.Kind()).
 		AddIf(ctx, ctx.IsDebugIndexIncluded(), `index`, i.Index()).
+		AddNonZeroIf(ctx, ctx.IncludeStableIDs(), `id`, i.StableID()).
 		AddIf(ctx, ctx.IsDebugAliveIncluded(), `alive`, i.Alive()).
 		Add(ctx.OnlyIndex(), `generic`, i.generic).
 		Add(ctx.OnlyIndex(), `resolved`, i.resolved).
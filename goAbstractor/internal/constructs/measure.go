@@ -0,0 +1,21 @@
+package constructs
+
+// Measure is which declarations the reports measure, e.g. the CK metrics,
+// code smells, package metrics, and participation. The declarations that
+// aren't measured are still abstracted and their usages are still kept,
+// they are only left out of the reports.
+type Measure struct {
+	// Tests indicates that the declarations in test files should be measured.
+	// By default test code isn't measured.
+	Tests bool
+
+	// ExcludeGenerated indicates that the declarations in generated files
+	// shouldn't be measured. By default generated code is measured.
+	ExcludeGenerated bool
+}
+
+// Measured determines if the given declaration should be measured.
+func (m Measure) Measured(decl Declaration) bool {
+	return (!decl.TestCode() || m.Tests) &&
+		(!decl.Generated() || !m.ExcludeGenerated)
+}
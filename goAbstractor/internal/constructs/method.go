@@ -32,6 +32,10 @@ type Method interface {
 	// i.e `func init() { ... }`.
 	IsInit() bool
 
+	// IsBlank indicates this method is a blank function or method,
+	// i.e `func _() { ... }`, which there may be many of in a package.
+	IsBlank() bool
+
 	// IsMain indicates this method is the main function,
 	// i.e `func main() { ... }` in `main` package.
 	IsMain() bool
@@ -91,6 +95,12 @@ type MethodArgs struct {
 	Exported bool
 	Location locs.Loc
 
+	// TestCode indicates the declaration is defined in a test file.
+	TestCode bool
+
+	// Generated indicates the declaration is defined in a generated file.
+	Generated bool
+
 	TypeParams []TypeParam
 	Signature  Signature
 	Metrics    Metrics
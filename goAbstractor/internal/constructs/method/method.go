@@ -22,12 +22,15 @@ import (
 
 type methodImp struct {
 	constructs.ConstructCore
-	funcType *types.Func
-	sigType  *types.Signature
-	pkg      constructs.Package
-	name     string
-	exported bool
-	loc      locs.Loc
+	constructs.BuildConfigSet
+	funcType  *types.Func
+	sigType   *types.Signature
+	pkg       constructs.Package
+	name      string
+	exported  bool
+	loc       locs.Loc
+	testCode  bool
+	generated bool
 
 	typeParams []constructs.TypeParam
 	signature  constructs.Signature
@@ -63,6 +66,8 @@ func newMethod(args constructs.MethodArgs) constructs.Method {
 		name:       args.Name,
 		exported:   args.Exported,
 		loc:        args.Location,
+		testCode:   args.TestCode,
+		generated:  args.Generated,
 		typeParams: args.TypeParams,
 		signature:  args.Signature,
 		metrics:    args.Metrics,
@@ -86,6 +91,8 @@ func (m *methodImp) GoType() types.Type { return m.sigType }
 func (m *methodImp) Name() string       { return m.name }
 func (m *methodImp) Exported() bool     { return m.exported }
 func (m *methodImp) Location() locs.Loc { return m.loc }
+func (m *methodImp) TestCode() bool     { return m.testCode }
+func (m *methodImp) Generated() bool    { return m.generated }
 
 func (m *methodImp) FuncType() *types.Func              { return m.funcType }
 func (m *methodImp) Package() constructs.Package        { return m.pkg }
@@ -125,6 +132,10 @@ func (m *methodImp) IsInit() bool {
 		m.signature.IsVacant()
 }
 
+func (m *methodImp) IsBlank() bool {
+	return strings.HasPrefix(m.name, `_#`)
+}
+
 func (m *methodImp) IsMain() bool {
 	return m.name == `main` &&
 		m.pkg.Name() == `main` &&
@@ -219,6 +230,10 @@ func (m *methodImp) ReplaceDuplicate(mp map[constructs.Construct]constructs.Cons
 	constructs.FindReplacementInSet(mp, m.instances)
 }
 
+func (m *methodImp) RemoveRolledBack(removed map[constructs.Construct]bool) {
+	constructs.RemoveFromSet(removed, m.instances)
+}
+
 func (m *methodImp) CompareTo(other constructs.Construct) int {
 	return constructs.CompareTo[constructs.Method](m, other, Comparer())
 }
@@ -235,6 +250,7 @@ func Comparer() comp.Comparer[constructs.Method] {
 			comp.DefaultPend(aImp.recvName, bImp.recvName),
 			constructs.SliceComparerPend(aImp.typeParams, bImp.typeParams),
 			constructs.ComparerPend(aImp.signature, bImp.signature),
+			constructs.ComparerPend(aImp.metrics, bImp.metrics),
 		)
 	}
 }
@@ -255,11 +271,18 @@ func (m *methodImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	return jsonify.NewMap().
 		AddIf(ctx, ctx.IsDebugKindIncluded(), `kind`, m.Kind()).
 		AddIf(ctx, ctx.IsDebugIndexIncluded(), `index`, m.Index()).
+		AddNonZeroIf(ctx, ctx.IncludeStableIDs(), `id`, m.StableID()).
 		AddIf(ctx, ctx.IsDebugAliveIncluded(), `alive`, m.Alive()).
 		Add(ctx.OnlyIndex(), `package`, m.pkg).
 		Add(ctx, `name`, m.name).
 		AddNonZero(ctx, `loc`, m.loc).
+		AddNonZeroIf(ctx, ctx.IncludeRanges(), `range`, locs.RangeOf(m.loc)).
 		AddNonZeroIf(ctx, m.exported, `vis`, `exported`).
+		AddNonZero(ctx, `test`, m.testCode).
+		AddNonZero(ctx, `generated`, m.generated).
+		AddNonZero(ctx, `builds`, m.BuildConfigs()).
+		AddNonZeroIf(ctx, ctx.IncludeAliveReasons(), `aliveReason`, m.AliveReason()).
+		AddNonZeroIf(ctx.Short(), ctx.IncludeAliveReasons(), `aliveBy`, m.AliveBy()).
 		AddNonZero(ctx.OnlyIndex(), `typeParams`, m.typeParams).
 		Add(ctx.OnlyIndex(), `signature`, m.signature).
 		AddNonZero(ctx.OnlyIndex(), `metrics`, m.metrics).
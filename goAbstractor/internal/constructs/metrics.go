@@ -11,10 +11,13 @@ import (
 
 type Metrics interface {
 	Construct
+	BuildConfigured
 	TempDeclRefContainer
 	IsMetrics()
 
 	Location() locs.Loc
+	TestCode() bool
+	Generated() bool
 	Complexity() int
 	LineCount() int
 	CodeCount() int
@@ -39,6 +42,12 @@ type MetricsArgs struct {
 	// (`var _ = func() int { ⋯ }`) or (`var x, y = func()(int, int) { ⋯ }`).
 	Location locs.Loc
 
+	// TestCode indicates the expression or method body is in a test file.
+	TestCode bool
+
+	// Generated indicates the expression or method body is in a generated file.
+	Generated bool
+
 	// Node is the node that was read for this metrics.
 	Node ast.Node
 
@@ -17,7 +17,10 @@ import (
 
 type metricsImp struct {
 	constructs.ConstructCore
-	loc locs.Loc
+	constructs.BuildConfigSet
+	loc       locs.Loc
+	testCode  bool
+	generated bool
 
 	complexity int
 	lineCount  int
@@ -42,7 +45,9 @@ func newMetrics(args constructs.MetricsArgs) constructs.Metrics {
 	assert.ArgHasNoNils(`invokes`, args.Invokes.ToSlice())
 
 	return &metricsImp{
-		loc: args.Location,
+		loc:       args.Location,
+		testCode:  args.TestCode,
+		generated: args.Generated,
 
 		complexity: args.Complexity,
 		lineCount:  args.LineCount,
@@ -64,6 +69,8 @@ func (m *metricsImp) IsMetrics() {}
 
 func (m *metricsImp) Kind() kind.Kind    { return kind.Metrics }
 func (m *metricsImp) Location() locs.Loc { return m.loc }
+func (m *metricsImp) TestCode() bool     { return m.testCode }
+func (m *metricsImp) Generated() bool    { return m.generated }
 func (m *metricsImp) Complexity() int    { return m.complexity }
 func (m *metricsImp) LineCount() int     { return m.lineCount }
 func (m *metricsImp) CodeCount() int     { return m.codeCount }
@@ -130,23 +137,49 @@ func (m *metricsImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	if !ctx.KeepDuplicates() && m.Duplicate() {
 		return nil
 	}
+	measured := (!m.testCode || ctx.IncludeTestMetrics()) &&
+		(!m.generated || !ctx.ExcludeGeneratedMetrics())
 	return jsonify.NewMap().
 		AddIf(ctx, ctx.IsDebugKindIncluded(), `kind`, m.Kind()).
 		AddIf(ctx, ctx.IsDebugIndexIncluded(), `index`, m.Index()).
 		AddIf(ctx, ctx.IsDebugAliveIncluded(), `alive`, m.Alive()).
 		AddNonZero(ctx, `loc`, m.loc). // Should only be zero for unit-tests.
-		AddNonZero(ctx, `complexity`, m.complexity).
-		AddNonZero(ctx, `lineCount`, m.lineCount).
-		AddNonZero(ctx, `codeCount`, m.codeCount).
-		AddNonZero(ctx, `indents`, m.indents).
-		AddNonZero(ctx, `getter`, m.getter).
-		AddNonZero(ctx, `setter`, m.setter).
+		AddNonZeroIf(ctx, ctx.IncludeRanges(), `range`, locs.RangeOf(m.loc)).
+		AddNonZero(ctx, `test`, m.testCode).
+		AddNonZero(ctx, `generated`, m.generated).
+		AddNonZero(ctx, `builds`, m.BuildConfigs()).
+		AddNonZeroIf(ctx, measured, `complexity`, m.complexity).
+		AddNonZeroIf(ctx, measured, `lineCount`, m.lineCount).
+		AddNonZeroIf(ctx, measured, `codeCount`, m.codeCount).
+		AddNonZeroIf(ctx, measured, `indents`, m.indents).
+		AddNonZeroIf(ctx, measured, `getter`, m.getter).
+		AddNonZeroIf(ctx, measured, `setter`, m.setter).
 		AddNonZero(ctx.Short(), `reads`, constructs.JsonSet(ctx.Short(), m.reads.ToSlice())).
 		AddNonZero(ctx.Short(), `writes`, constructs.JsonSet(ctx.Short(), m.writes.ToSlice())).
 		AddNonZero(ctx.Short(), `invokes`, constructs.JsonSet(ctx.Short(), m.invokes.ToSlice())).
+		AddNonZero(ctx.Short(), `tests`, constructs.JsonSet(ctx.Short(), m.testedInvokes())).
 		AddNonZero(ctx, `sideEffect`, m.sideEffect)
 }
 
+// testedInvokes gets the production code invoked by this test code.
+// This will be empty if this isn't test code.
+func (m *metricsImp) testedInvokes() []constructs.Construct {
+	if !m.testCode {
+		return nil
+	}
+	tested := []constructs.Construct{}
+	for c := range m.invokes.Enumerate().Seq() {
+		decl, ok := c.(constructs.Declaration)
+		if inst, isInst := c.(constructs.MethodInst); isInst {
+			decl, ok = inst.Generic(), true
+		}
+		if ok && !decl.TestCode() {
+			tested = append(tested, c)
+		}
+	}
+	return tested
+}
+
 func (m *metricsImp) ToStringer(s stringer.Stringer) {
 	s.Write(m.String())
 }
@@ -24,6 +24,8 @@ type Object interface {
 	IsObject()
 
 	Data() StructDesc
+	FieldLocation(name string) locs.Loc
+	FieldLocations() map[string]locs.Loc
 	Methods() collections.ReadonlySortedSet[Method]
 	Interface() InterfaceDesc
 
@@ -48,8 +50,19 @@ type ObjectArgs struct {
 	Location locs.Loc
 	Nest     NestType
 
+	// TestCode indicates the declaration is defined in a test file.
+	TestCode bool
+
+	// Generated indicates the declaration is defined in a generated file.
+	Generated bool
+
 	TypeParams []TypeParam
 	Data       StructDesc
+
+	// FieldLocations are the locations of the fields in the data keyed
+	// by the field name. Since fields are shared by any structs with the
+	// same fields, the location of a field is kept by the object.
+	FieldLocations map[string]locs.Loc
 }
 
 type ObjectFactory interface {
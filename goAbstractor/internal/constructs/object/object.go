@@ -21,14 +21,18 @@ import (
 
 type objectImp struct {
 	constructs.ConstructCore
-	realType types.Type
-	pkg      constructs.Package
-	name     string
-	exported bool
-	loc      locs.Loc
+	constructs.BuildConfigSet
+	realType  types.Type
+	pkg       constructs.Package
+	name      string
+	exported  bool
+	loc       locs.Loc
+	testCode  bool
+	generated bool
 
 	typeParams []constructs.TypeParam
 	data       constructs.StructDesc
+	fieldLocs  map[string]locs.Loc
 	inter      constructs.InterfaceDesc
 	nest       constructs.NestType
 
@@ -49,8 +53,11 @@ func newObject(args constructs.ObjectArgs) constructs.Object {
 		name:       args.Name,
 		exported:   args.Exported,
 		loc:        args.Location,
+		testCode:   args.TestCode,
+		generated:  args.Generated,
 		typeParams: args.TypeParams,
 		data:       args.Data,
+		fieldLocs:  args.FieldLocations,
 		nest:       args.Nest,
 		methods:    sortedSet.New(method.Comparer()),
 		instances:  sortedSet.New(objectInst.Comparer()),
@@ -66,6 +73,8 @@ func (d *objectImp) GoType() types.Type { return d.realType }
 func (d *objectImp) Name() string       { return d.name }
 func (d *objectImp) Exported() bool     { return d.exported }
 func (d *objectImp) Location() locs.Loc { return d.loc }
+func (d *objectImp) TestCode() bool     { return d.testCode }
+func (d *objectImp) Generated() bool    { return d.generated }
 
 func (d *objectImp) Package() constructs.Package        { return d.pkg }
 func (d *objectImp) Type() constructs.TypeDesc          { return d.data }
@@ -144,6 +153,11 @@ func (d *objectImp) ReplaceDuplicate(m map[constructs.Construct]constructs.Const
 	constructs.FindReplacementInSet(m, d.instances)
 }
 
+func (d *objectImp) RemoveRolledBack(removed map[constructs.Construct]bool) {
+	constructs.RemoveFromSet(removed, d.methods)
+	constructs.RemoveFromSet(removed, d.instances)
+}
+
 func (d *objectImp) CompareTo(other constructs.Construct) int {
 	return constructs.CompareTo[constructs.Object](d, other, Comparer())
 }
@@ -180,19 +194,55 @@ func (d *objectImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	return jsonify.NewMap().
 		AddIf(ctx, ctx.IsDebugKindIncluded(), `kind`, d.Kind()).
 		AddIf(ctx, ctx.IsDebugIndexIncluded(), `index`, d.Index()).
+		AddNonZeroIf(ctx, ctx.IncludeStableIDs(), `id`, d.StableID()).
 		AddIf(ctx, ctx.IsDebugAliveIncluded(), `alive`, d.Alive()).
 		Add(ctx.OnlyIndex(), `package`, d.pkg).
 		Add(ctx, `name`, d.name).
 		AddNonZero(ctx, `loc`, d.loc).
+		AddNonZeroIf(ctx, ctx.IncludeRanges(), `range`, locs.RangeOf(d.loc)).
 		AddNonZeroIf(ctx, d.exported, `vis`, `exported`).
+		AddNonZero(ctx, `test`, d.testCode).
+		AddNonZero(ctx, `generated`, d.generated).
+		AddNonZero(ctx, `builds`, d.BuildConfigs()).
+		AddNonZeroIf(ctx, ctx.IncludeAliveReasons(), `aliveReason`, d.AliveReason()).
+		AddNonZeroIf(ctx.Short(), ctx.IncludeAliveReasons(), `aliveBy`, d.AliveBy()).
 		AddNonZero(ctx.OnlyIndex(), `typeParams`, d.typeParams).
 		Add(ctx.OnlyIndex(), `data`, d.data).
+		AddNonZeroIf(ctx, ctx.IncludeRanges(), `fieldRanges`, d.fieldRanges(ctx)).
 		AddNonZero(ctx.OnlyIndex(), `instances`, constructs.JsonSet(ctx.OnlyIndex(), d.instances.ToSlice())).
 		AddNonZero(ctx.OnlyIndex(), `methods`, constructs.JsonSet(ctx.OnlyIndex(), d.methods.ToSlice())).
 		AddNonZero(ctx.Short(), `nest`, d.nest).
 		Add(ctx.OnlyIndex(), `interface`, d.inter)
 }
 
+// FieldLocation gets the location of the field with the given name
+// or the location of the object if the field location isn't known.
+func (d *objectImp) FieldLocation(name string) locs.Loc {
+	if loc, has := d.fieldLocs[name]; has {
+		return loc
+	}
+	return d.loc
+}
+
+// FieldLocations gets the locations of the fields keyed by the field name.
+func (d *objectImp) FieldLocations() map[string]locs.Loc {
+	return d.fieldLocs
+}
+
+// fieldRanges gets the ranges of the fields in the order of the fields.
+func (d *objectImp) fieldRanges(ctx *jsonify.Context) *jsonify.Map {
+	m := jsonify.NewMap()
+	if utils.IsNil(d.data) {
+		return m
+	}
+	for _, f := range d.data.Fields() {
+		if loc, has := d.fieldLocs[f.Name()]; has {
+			m.AddNonZero(ctx, f.Name(), locs.RangeOf(loc))
+		}
+	}
+	return m
+}
+
 func (d *objectImp) ToStringer(s stringer.Stringer) {
 	s.Write(d.pkg.Path(), `.`)
 	if !utils.IsNil(d.nest) {
@@ -102,6 +102,10 @@ func (i *instanceImp) ReplaceDuplicate(m map[constructs.Construct]constructs.Con
 	constructs.FindReplacementInSet(m, i.methods)
 }
 
+func (i *instanceImp) RemoveRolledBack(removed map[constructs.Construct]bool) {
+	constructs.RemoveFromSet(removed, i.methods)
+}
+
 func (i *instanceImp) CompareTo(other constructs.Construct) int {
 	return constructs.CompareTo[constructs.ObjectInst](i, other, Comparer())
 }
@@ -151,6 +155,7 @@ func (i *instanceImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	return jsonify.NewMap().
 		AddIf(ctx, ctx.IsDebugKindIncluded(), `kind`, i.Kind()).
 		AddIf(ctx, ctx.IsDebugIndexIncluded(), `index`, i.Index()).
+		AddNonZeroIf(ctx, ctx.IncludeStableIDs(), `id`, i.StableID()).
 		AddIf(ctx, ctx.IsDebugAliveIncluded(), `alive`, i.Alive()).
 		Add(ctx.OnlyIndex(), `generic`, i.generic).
 		Add(ctx.OnlyIndex(), `resData`, i.resolvedData).
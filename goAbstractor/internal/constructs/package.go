@@ -13,9 +13,11 @@ type Package interface {
 	Source() *packages.Package
 	Path() string
 	Name() string
+	Module() string
 	EntryPoint() bool
 	ImportPaths() []string
 	InitCount() int
+	BlankCount() int
 
 	AddImport(p Package) Package
 	AddInterfaceDecl(it InterfaceDecl) InterfaceDecl
@@ -41,6 +43,16 @@ type PackageArgs struct {
 	Path        string
 	Name        string
 	ImportPaths []string
+
+	// Module is the optional path of the module the package is in.
+	Module string
+
+	// EntryPoint indicates the package was one of the packages read,
+	// as opposed to only being read as a dependency, so it is used
+	// as a root when determining what is alive. The main method is the
+	// root of a main package, and the exported API is the root of any
+	// other package unless the package's module has a main package read.
+	EntryPoint bool
 }
 
 type PackageFactory interface {
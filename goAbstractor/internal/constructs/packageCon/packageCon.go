@@ -26,6 +26,8 @@ type packageImp struct {
 
 	path        string
 	name        string
+	module      string
+	entryPoint  bool
 	importPaths []string
 
 	imports    collections.SortedSet[constructs.Package]
@@ -44,6 +46,8 @@ func newPackage(args constructs.PackageArgs) constructs.Package {
 		pkg:         args.RealPkg,
 		path:        args.Path,
 		name:        args.Name,
+		module:      args.Module,
+		entryPoint:  args.EntryPoint,
 		importPaths: args.ImportPaths,
 		imports:     sortedSet.New(Comparer()),
 		interfaces:  sortedSet.New(interfaceDecl.Comparer()),
@@ -58,12 +62,11 @@ func (p *packageImp) IsPackage() {}
 func (p *packageImp) Kind() kind.Kind { return kind.Package }
 func (p *packageImp) Path() string    { return p.path }
 func (p *packageImp) Name() string    { return p.name }
+func (p *packageImp) Module() string  { return p.module }
 
 func (p *packageImp) Source() *packages.Package { return p.pkg }
 
-func (p *packageImp) EntryPoint() bool {
-	return p.pkg.PkgPath == `command-line-arguments`
-}
+func (p *packageImp) EntryPoint() bool { return p.entryPoint }
 
 func (p *packageImp) ImportPaths() []string { return p.importPaths }
 
@@ -93,6 +96,21 @@ func (p *packageImp) InitCount() int {
 		Count()
 }
 
+func (p *packageImp) BlankCount() int {
+	return p.methods.Enumerate().
+		Where(func(m constructs.Method) bool { return m.IsBlank() }).
+		Count()
+}
+
+func (p *packageImp) MergeDuplicate(dup constructs.Construct) {
+	d := dup.(*packageImp)
+	p.imports.AddFrom(d.imports.Enumerate())
+	p.interfaces.AddFrom(d.interfaces.Enumerate())
+	p.methods.AddFrom(d.methods.Enumerate())
+	p.objects.AddFrom(d.objects.Enumerate())
+	p.values.AddFrom(d.values.Enumerate())
+}
+
 func (p *packageImp) AddImport(i constructs.Package) constructs.Package {
 	v, _ := p.imports.TryAdd(i)
 	return v
@@ -214,6 +232,13 @@ func (p *packageImp) ReplaceDuplicate(m map[constructs.Construct]constructs.Cons
 	constructs.FindReplacementInSet(m, p.values)
 }
 
+func (p *packageImp) RemoveRolledBack(removed map[constructs.Construct]bool) {
+	constructs.RemoveFromSet(removed, p.interfaces)
+	constructs.RemoveFromSet(removed, p.methods)
+	constructs.RemoveFromSet(removed, p.objects)
+	constructs.RemoveFromSet(removed, p.values)
+}
+
 func (p *packageImp) CompareTo(other constructs.Construct) int {
 	return constructs.CompareTo[constructs.Package](p, other, Comparer())
 }
@@ -244,9 +269,11 @@ func (p *packageImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	return jsonify.NewMap().
 		AddIf(ctx, ctx.IsDebugKindIncluded(), `kind`, p.Kind()).
 		AddIf(ctx, ctx.IsDebugIndexIncluded(), `index`, p.Index()).
+		AddNonZeroIf(ctx, ctx.IncludeStableIDs(), `id`, p.StableID()).
 		AddIf(ctx, ctx.IsDebugAliveIncluded(), `alive`, p.Alive()).
 		Add(ctx, `path`, p.path).
 		Add(ctx, `name`, p.name).
+		AddNonZero(ctx, `module`, p.module).
 		AddNonZero(ctx.OnlyIndex(), `imports`, constructs.JsonSet(ctx.OnlyIndex(), p.imports.ToSlice())).
 		AddNonZero(ctx.OnlyIndex(), `interfaces`, constructs.JsonSet(ctx.OnlyIndex(), p.interfaces.ToSlice())).
 		AddNonZero(ctx.OnlyIndex(), `methods`, constructs.JsonSet(ctx.OnlyIndex(), p.methods.ToSlice())).
@@ -3,8 +3,10 @@ package constructs
 import (
 	"github.com/Snow-Gremlin/goToolbox/collections"
 
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/metadata"
 )
 
 type Project interface {
@@ -38,11 +40,60 @@ type Project interface {
 	TypeParamFactory
 
 	Locs() locs.Set
+	Diagnostics() diagnostics.Set
+
+	// Metadata is the optional provenance of the abstraction.
+	Metadata() *metadata.Metadata
+	SetMetadata(m *metadata.Metadata)
+
 	Enumerate() collections.Enumerator[Construct]
-	EntryPoint() Package
+	EntryPoints() []Package
 	FindType(pkgPath, name string, nest NestType, implicitTypes, instanceTypes []TypeDesc, allowRef, panicOnNotFound bool) (TypeDesc, bool)
 	FindDecl(pkgPath, name string, nest NestType, implicitTypes, instanceTypes []TypeDesc, allowRef, panicOnNotFound bool) (Construct, bool)
 	RemoveDuplicates()
+
+	// Checkpoint gets the number of constructs that have been added to each
+	// factory so that the constructs added after it can be rolled back.
+	Checkpoint() Checkpoint
+
+	// Rollback removes the constructs added after the given checkpoint from
+	// the factories and from any construct that collected them, e.g. the
+	// package they were declared in. Returns the removed constructs.
+	Rollback(cp Checkpoint) map[Construct]bool
+
+	// Join moves the constructs from the given projects into this project,
+	// in the order of the given projects. Any construct that is the same as
+	// one already in this project is replaced by the one in this project.
+	// The given projects must use the same locations as this project
+	// and shouldn't be used after being merged.
+	Join(others ...Project)
+
 	UpdateIndices(skipDead bool)
+	UpdateStableIDs()
 	String() string
 }
+
+// Checkpoint is the number of constructs that had been added
+// to each factory of a project, in the order of the factories.
+type Checkpoint []int
+
+// Tolerate runs the given handle for the given construct while resolving
+// the given phase of the project. If the abstraction is tolerant and the
+// handle fails, the failure is recorded with the construct and the location
+// of the construct, or of the generic declaration for an instance, so that
+// the rest of the constructs can still be resolved.
+// Returns true if the handle finished.
+func Tolerate(proj Project, phase diagnostics.Phase, c Construct, handle func()) bool {
+	var loc locs.Loc
+	switch t := c.(type) {
+	case Declaration:
+		loc = t.Location()
+	case InterfaceInst:
+		loc = t.Generic().Location()
+	case MethodInst:
+		loc = t.Generic().Location()
+	case ObjectInst:
+		loc = t.Generic().Location()
+	}
+	return proj.Diagnostics().Tolerate(phase, c.String(), loc, handle)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
 
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/assert"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/ckMetrics"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/abstract"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/argument"
@@ -33,8 +34,13 @@ import (
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/tempTypeParamRef"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/typeParam"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/value"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/cycles"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/deadCode"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/metadata"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/packageMetrics"
 )
 
 type projectImp struct {
@@ -62,7 +68,9 @@ type projectImp struct {
 	constructs.TempTypeParamRefFactory
 	constructs.TypeParamFactory
 
-	locations locs.Set
+	locations   locs.Set
+	diagnostics diagnostics.Set
+	metadata    *metadata.Metadata
 }
 
 func New(locs locs.Set) constructs.Project {
@@ -91,7 +99,8 @@ func New(locs locs.Set) constructs.Project {
 		TempTypeParamRefFactory: tempTypeParamRef.New(),
 		TypeParamFactory:        typeParam.New(),
 
-		locations: locs,
+		locations:   locs,
+		diagnostics: diagnostics.NewSet(),
 	}
 }
 
@@ -99,6 +108,12 @@ func (p *projectImp) Kind() kind.Kind { return kind.Project }
 
 func (p *projectImp) Locs() locs.Set { return p.locations }
 
+func (p *projectImp) Diagnostics() diagnostics.Set { return p.diagnostics }
+
+func (p *projectImp) Metadata() *metadata.Metadata { return p.metadata }
+
+func (p *projectImp) SetMetadata(m *metadata.Metadata) { p.metadata = m }
+
 func (p *projectImp) Factories() collections.Enumerator[constructs.Factory] {
 	return enumerator.Enumerate[constructs.Factory](
 		p.AbstractFactory,
@@ -135,11 +150,10 @@ func (p *projectImp) Dedup(m map[constructs.Construct]constructs.Construct) {
 	p.Factories().Foreach(func(f constructs.Factory) { f.Dedup(m) })
 }
 
-func (p *projectImp) EntryPoint() constructs.Package {
-	pkg, _ := p.Packages().Enumerate().Where(func(pkg constructs.Package) bool {
+func (p *projectImp) EntryPoints() []constructs.Package {
+	return p.Packages().Enumerate().Where(func(pkg constructs.Package) bool {
 		return pkg.EntryPoint()
-	}).First()
-	return pkg
+	}).ToSlice()
 }
 
 func (p *projectImp) FindType(pkgPath, name string, nest constructs.NestType,
@@ -148,21 +162,39 @@ func (p *projectImp) FindType(pkgPath, name string, nest constructs.NestType,
 
 	assert.ArgNotEmpty(`pkgPath`, pkgPath)
 
-	if allowRef {
-		ref, found := p.TempReferences().Enumerate().Where(func(ref constructs.TempReference) bool {
-			return comp.Or(
-				comp.DefaultPend(ref.PackagePath(), pkgPath),
-				comp.DefaultPend(ref.Name(), name),
-				constructs.SliceComparerPend(ref.ImplicitTypes(), implicitTypes),
-				constructs.SliceComparerPend(ref.InstanceTypes(), instanceTypes),
-				constructs.ComparerPend(ref.Nest(), nest),
-			) == 0
-		}).First()
-		if found {
-			return ref, true
+	if !allowRef {
+		return p.findType(pkgPath, name, nest, implicitTypes, instanceTypes, panicOnNotFound)
+	}
+
+	// Without any type arguments a temporary reference would resolve to the
+	// declared type, so the declared type is preferred. This keeps the type
+	// found from depending on if another package, that was abstracted before
+	// the type was declared, had referenced it.
+	if len(implicitTypes) <= 0 && len(instanceTypes) <= 0 {
+		if typ, found := p.findType(pkgPath, name, nest, nil, nil, false); found {
+			return typ, true
 		}
 	}
 
+	ref, found := p.TempReferences().Enumerate().Where(func(ref constructs.TempReference) bool {
+		return comp.Or(
+			comp.DefaultPend(ref.PackagePath(), pkgPath),
+			comp.DefaultPend(ref.Name(), name),
+			constructs.SliceComparerPend(ref.ImplicitTypes(), implicitTypes),
+			constructs.SliceComparerPend(ref.InstanceTypes(), instanceTypes),
+			constructs.ComparerPend(ref.Nest(), nest),
+		) == 0
+	}).First()
+	if found {
+		return ref, true
+	}
+	return p.findType(pkgPath, name, nest, implicitTypes, instanceTypes, panicOnNotFound)
+}
+
+func (p *projectImp) findType(pkgPath, name string, nest constructs.NestType,
+	implicitTypes, instanceTypes []constructs.TypeDesc,
+	panicOnNotFound bool) (constructs.TypeDesc, bool) {
+
 	pkg := p.FindPackageByPath(pkgPath)
 	if pkg == nil {
 		if !panicOnNotFound {
@@ -246,10 +278,13 @@ func (p *projectImp) FindDecl(pkgPath, name string, nest constructs.NestType,
 	assert.ArgNotEmpty(`pkgPath`, pkgPath)
 
 	if allowRef {
+		// The placeholders for methods with receivers are skipped since
+		// they aren't found by name, e.g. `Kind.String` isn't `String`.
 		ref, found := p.TempDeclRefs().Enumerate().Where(func(ref constructs.TempDeclRef) bool {
 			return comp.Or(
 				comp.DefaultPend(ref.PackagePath(), pkgPath),
 				comp.DefaultPend(ref.Name(), name),
+				comp.DefaultPend(ref.Receiver(), ``),
 				constructs.SliceComparerPend(ref.ImplicitTypes(), implicitTypes),
 				constructs.SliceComparerPend(ref.InstanceTypes(), instanceTypes),
 				constructs.ComparerPend(ref.Nest(), nest),
@@ -341,12 +376,67 @@ func (p *projectImp) RemoveDuplicates() {
 		// Now with the replacement map, replace duplicates.
 		for c := range p.Enumerate().Seq() {
 			if dr, ok := c.(constructs.DuplicateReplacer); ok {
-				dr.ReplaceDuplicate(m)
+				constructs.Tolerate(p, diagnostics.Duplicates, c, func() {
+					dr.ReplaceDuplicate(m)
+				})
 			}
 		}
 	}
 }
 
+// rollbacker is a factory that can remove the items added after a point.
+type rollbacker interface {
+	Added() int
+	Rollback(added int, removed map[constructs.Construct]bool)
+}
+
+func (p *projectImp) Checkpoint() constructs.Checkpoint {
+	return enumerator.Select(p.Factories(), func(f constructs.Factory) int {
+		return f.(rollbacker).Added()
+	}).ToSlice()
+}
+
+func (p *projectImp) Rollback(cp constructs.Checkpoint) map[constructs.Construct]bool {
+	removed := map[constructs.Construct]bool{}
+	for i, f := range p.Factories().ToSlice() {
+		f.(rollbacker).Rollback(cp[i], removed)
+	}
+	if len(removed) <= 0 {
+		return removed
+	}
+
+	for c := range p.Enumerate().Seq() {
+		if rr, ok := c.(constructs.RolledBackRemover); ok {
+			rr.RemoveRolledBack(removed)
+		}
+	}
+	return removed
+}
+
+// merger is a factory that can merge the items from another factory.
+type merger interface {
+	Merge(other constructs.Factory, m map[constructs.Construct]constructs.Construct)
+}
+
+func (p *projectImp) Join(others ...constructs.Project) {
+	m := map[constructs.Construct]constructs.Construct{}
+	factories := p.Factories().ToSlice()
+	for _, other := range others {
+		for i, f := range other.(*projectImp).Factories().ToSlice() {
+			factories[i].(merger).Merge(f, m)
+		}
+	}
+	if len(m) <= 0 {
+		return
+	}
+
+	for c := range p.Enumerate().Seq() {
+		if dr, ok := c.(constructs.DuplicateReplacer); ok {
+			dr.ReplaceDuplicate(m)
+		}
+	}
+}
+
 func (p *projectImp) UpdateIndices(skipDead bool) {
 	for f := range p.Factories().Seq() {
 		index := 0
@@ -364,11 +454,29 @@ func (p *projectImp) UpdateIndices(skipDead bool) {
 func (p *projectImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	m := jsonify.NewMap().
 		Add(ctx, `language`, `go`).
+		AddNonZero(ctx, `metadata`, p.metadata).
 		AddNonZero(ctx, `locs`, p.locations)
 	for f := range p.Factories().Seq() {
 		list := f.Enumerate().WhereNot(constructs.Construct.Duplicate).ToSlice()
 		m.AddNonZero(ctx, f.Kind().Plural(), list)
 	}
+	m.AddNonZero(ctx, `diagnostics`, p.diagnostics)
+	if ctx.IncludeDeadReport() {
+		m.AddNonZero(ctx, `deadCode`, deadCode.New(p))
+	}
+	measure := constructs.Measure{
+		Tests:            ctx.IncludeTestMetrics(),
+		ExcludeGenerated: ctx.ExcludeGeneratedMetrics(),
+	}
+	if ctx.IncludeCKMetrics() {
+		m.AddNonZero(ctx, `ckMetrics`, ckMetrics.New(p, measure))
+	}
+	if ctx.IncludePackageMetrics() {
+		m.AddNonZero(ctx, `packageMetrics`, packageMetrics.New(p, ctx.ExcludeStdlib(), measure))
+	}
+	if ctx.IncludeCycles() {
+		m.AddNonZero(ctx, `cycles`, cycles.Find(p, ctx.SkipDead()))
+	}
 	return m
 }
 
@@ -0,0 +1,179 @@
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/stringer"
+)
+
+// stableIDLength is the number of hexadecimal digits of
+// the content hash to use in a stable identifier.
+const stableIDLength = 16
+
+// distinctIDLength is the number of hexadecimal digits of the hash of the
+// distinguishing content appended to the identifiers with the same key.
+const distinctIDLength = 8
+
+// distinctLevels is the number of levels of content
+// that distinguish constructs with the same key.
+const distinctLevels = 2
+
+// UpdateStableIDs sets the stable identifier of each package, declaration,
+// and type description in the project.
+//
+// The identifier is the kind followed by a hash of a key for the construct.
+// The key of a package is its path. The key of a declaration is the package
+// path, nest, receiver, and name, where the numbered `init` and blank
+// functions have the name they were declared with. The key of an instance
+// is the key of the generic declaration with the type arguments. The key of
+// any other type description is its type, e.g. `func(x int) string`.
+//
+// If more than one construct of the same kind has the same key, the
+// identifiers are extended with a hash of the content that distinguishes
+// them. The type of a declaration, e.g. the signature of a function, is used
+// first, then the file and the position in the file that the declaration
+// is at. The declarations that are expected to repeat, e.g. `init`
+// functions and blank values, are always extended with both so that their
+// identifiers don't change when another is added. Only the constructs that
+// can't be told apart by their content are numbered in index order.
+func (p *projectImp) UpdateStableIDs() {
+	for f := range p.Factories().Seq() {
+		ids := []string{}
+		groups := map[string][]constructs.Construct{}
+		for c := range f.Enumerate().Seq() {
+			key, ok := stableKey(c)
+			if !ok || c.Duplicate() {
+				c.SetStableID(``)
+				continue
+			}
+			id := string(c.Kind()) + `:` + hashKey(key)[:stableIDLength]
+			if repeatable(c) {
+				id += `-` + hashKey(distinction(c, 0) + "\n" + distinction(c, 1))[:distinctIDLength]
+			}
+			if _, has := groups[id]; !has {
+				ids = append(ids, id)
+			}
+			groups[id] = append(groups[id], c)
+		}
+		for _, id := range ids {
+			setStableIDs(id, groups[id], 0)
+		}
+	}
+}
+
+// setStableIDs sets the given identifier to the given constructs with the
+// same key. If there is more than one construct, the identifiers are
+// extended by the distinguishing content starting at the given level.
+func setStableIDs(id string, group []constructs.Construct, level int) {
+	if len(group) == 1 {
+		group[0].SetStableID(id)
+		return
+	}
+
+	if level >= distinctLevels {
+		for i, c := range group {
+			if i > 0 {
+				c.SetStableID(id + `-` + strconv.Itoa(i+1))
+			} else {
+				c.SetStableID(id)
+			}
+		}
+		return
+	}
+
+	digests := []string{}
+	subgroups := map[string][]constructs.Construct{}
+	for _, c := range group {
+		digest := hashKey(distinction(c, level))[:distinctIDLength]
+		if _, has := subgroups[digest]; !has {
+			digests = append(digests, digest)
+		}
+		subgroups[digest] = append(subgroups[digest], c)
+	}
+	if len(digests) == 1 {
+		setStableIDs(id, group, level+1)
+		return
+	}
+	for _, digest := range digests {
+		setStableIDs(id+`-`+digest, subgroups[digest], level+1)
+	}
+}
+
+// distinction gets the content at the given level that distinguishes
+// the given construct from other constructs with the same key.
+// The first level is the type of a declaration, e.g. the signature of a
+// function, and the second is the file and position of the declaration.
+func distinction(c constructs.Construct, level int) string {
+	decl, ok := c.(constructs.Declaration)
+	if !ok {
+		return ``
+	}
+	switch level {
+	case 0:
+		if typ := decl.Type(); !utils.IsNil(typ) {
+			return stringer.String(typ)
+		}
+		return ``
+	default:
+		if loc := decl.Location(); !utils.IsNil(loc) {
+			pos := loc.Position()
+			return pos.Filename + `:` + strconv.Itoa(pos.Line) + `:` + strconv.Itoa(pos.Column)
+		}
+		return ``
+	}
+}
+
+// repeatable determines if more than one of the given declaration is
+// expected to be declared with the same key, e.g. `init` functions,
+// blank functions, and blank values.
+func repeatable(c constructs.Construct) bool {
+	switch t := c.(type) {
+	case constructs.Method:
+		return t.IsInit() || t.IsBlank()
+	case constructs.Declaration:
+		return t.Name() == `_`
+	default:
+		return false
+	}
+}
+
+// stableKey gets the key to create a stable identifier from.
+// Returns false if the construct doesn't get a stable identifier.
+func stableKey(c constructs.Construct) (string, bool) {
+	switch t := c.(type) {
+	case constructs.Package:
+		return t.Path(), true
+	case constructs.Declaration:
+		return constructs.QualifiedName(t), true
+	case constructs.ObjectInst:
+		return instanceKey(t.Generic(), t.ImplicitTypes(), t.InstanceTypes()), true
+	case constructs.InterfaceInst:
+		return instanceKey(t.Generic(), t.ImplicitTypes(), t.InstanceTypes()), true
+	case constructs.MethodInst:
+		return instanceKey(t.Generic(), nil, t.InstanceTypes()), true
+	case constructs.TypeDesc:
+		return stringer.String(t), true
+	default:
+		return ``, false
+	}
+}
+
+func instanceKey(generic constructs.Construct, implicitTypes, instanceTypes []constructs.TypeDesc) string {
+	key, _ := stableKey(generic)
+	return stringer.New().Write(key, `[`).
+		WriteList(``, `, `, `;`, implicitTypes).
+		WriteList(``, `, `, ``, instanceTypes).
+		Write(`]`).
+		String()
+}
+
+// hashKey gets the hexadecimal SHA-256 hash of the given key.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -9,6 +9,7 @@ import (
 type Selection interface {
 	Construct
 	TempReferenceContainer
+	TempDeclRefContainer
 	IsSelection()
 
 	Name() string
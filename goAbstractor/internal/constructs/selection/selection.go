@@ -6,6 +6,7 @@ import (
 
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/assert"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/hint"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/kind"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/stringer"
@@ -36,7 +37,7 @@ func (s *selectionImp) Name() string    { return s.name }
 func (s *selectionImp) Origin() constructs.Construct { return s.origin }
 
 func (s *selectionImp) Target() constructs.Construct {
-	if utils.IsNil(s.target) {
+	if !utils.IsNil(s.target) {
 		return s.target
 	}
 
@@ -73,6 +74,16 @@ func setTargetFromInterfaceDesc(t constructs.InterfaceDesc, name string) constru
 }
 
 func setTargetFromInterfaceInst(t constructs.InterfaceInst, name string) constructs.Construct {
+	// A selection from a pointer to an object, e.g. `p.Foo` where `p` is
+	// `*Bar`, is of the object's method or field.
+	if t.Generic().Interface().Hint() == hint.Pointer && len(t.InstanceTypes()) == 1 {
+		switch it := t.InstanceTypes()[0].(type) {
+		case constructs.Object:
+			return setTargetFromObject(it, name)
+		case constructs.ObjectInst:
+			return setTargetFromObjectInst(it, name)
+		}
+	}
 	return setTargetFromInterfaceDesc(t.Resolved(), name)
 }
 
@@ -119,6 +130,9 @@ func setTargetFromPackage(t constructs.Package, name string) constructs.Construc
 }
 
 func setTargetFromStructDesc(t constructs.StructDesc, name string) constructs.Construct {
+	if utils.IsNil(t) {
+		return nil
+	}
 	for _, f := range t.Fields() {
 		if f.Name() == name {
 			return f
@@ -135,6 +149,14 @@ func (s *selectionImp) RemoveTempReferences(required bool) bool {
 	return changed
 }
 
+func (s *selectionImp) RemoveTempDeclRefs(required bool) bool {
+	changed := false
+	if tr, ok := s.origin.(constructs.TempDeclRef); ok && !tr.Dropped() {
+		s.origin, changed = constructs.ResolvedTempDeclRef(s.origin, required)
+	}
+	return changed
+}
+
 func (s *selectionImp) ReplaceDuplicate(m map[constructs.Construct]constructs.Construct) {
 	constructs.FindReplacement(m, &s.origin)
 	constructs.FindReplacement(m, &s.target)
@@ -32,6 +32,25 @@ func createTuple(pkg *packages.Package, args []constructs.Argument) *types.Tuple
 	return types.NewTuple(vars...)
 }
 
+// variadicTuple gets the parameters for a variadic signature.
+// Since arrays and slices are both abstracted as a list, the list given for
+// the variadic parameter may have an array as its real type, e.g. when the
+// instance type of a generic function was first created for an array.
+// The last parameter is changed to a slice since Go requires it.
+func variadicTuple(pkg *packages.Package, params *types.Tuple) *types.Tuple {
+	last := params.At(params.Len() - 1)
+	arr, ok := last.Type().Underlying().(*types.Array)
+	if !ok {
+		return params
+	}
+	vars := make([]*types.Var, params.Len())
+	for i := range params.Len() - 1 {
+		vars[i] = params.At(i)
+	}
+	vars[len(vars)-1] = types.NewVar(token.NoPos, pkg.Types, last.Name(), types.NewSlice(arr.Elem()))
+	return types.NewTuple(vars...)
+}
+
 func newSignature(args constructs.SignatureArgs) constructs.Signature {
 	assert.ArgHasNoNils(`params`, args.Params)
 	assert.ArgHasNoNils(`results`, args.Results)
@@ -39,6 +58,9 @@ func newSignature(args constructs.SignatureArgs) constructs.Signature {
 	if utils.IsNil(args.RealType) {
 		assert.ArgNotNil(`package`, args.Package)
 		params := createTuple(args.Package, args.Params)
+		if args.Variadic && params.Len() > 0 {
+			params = variadicTuple(args.Package, params)
+		}
 		results := createTuple(args.Package, args.Results)
 		args.RealType = types.NewSignatureType(nil, nil, nil, params, results, args.Variadic)
 	}
@@ -105,6 +127,7 @@ func (m *signatureImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	return jsonify.NewMap().
 		AddIf(ctx, ctx.IsDebugKindIncluded(), `kind`, m.Kind()).
 		AddIf(ctx, ctx.IsDebugIndexIncluded(), `index`, m.Index()).
+		AddNonZeroIf(ctx, ctx.IncludeStableIDs(), `id`, m.StableID()).
 		AddIf(ctx, ctx.IsDebugAliveIncluded(), `alive`, m.Alive()).
 		AddNonZero(ctx, `variadic`, m.variadic).
 		AddNonZero(ctx.OnlyIndex(), `params`, m.params).
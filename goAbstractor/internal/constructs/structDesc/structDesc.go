@@ -91,6 +91,7 @@ func (d *structDescImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	return jsonify.NewMap().
 		AddIf(ctx, ctx.IsDebugKindIncluded(), `kind`, d.Kind()).
 		AddIf(ctx, ctx.IsDebugIndexIncluded(), `index`, d.Index()).
+		AddNonZeroIf(ctx, ctx.IncludeStableIDs(), `id`, d.StableID()).
 		AddIf(ctx, ctx.IsDebugAliveIncluded(), `alive`, d.Alive()).
 		AddNonZero(ctx, `synthetic`, d.Synthetic()).
 		AddNonZero(ctx.OnlyIndex(), `fields`, d.fields)
@@ -25,6 +25,12 @@ type TempDeclRef interface {
 	ResolvedType() Construct
 	Resolved() bool
 	SetResolution(con Construct)
+
+	// Drop indicates that the referenced declaration failed to be
+	// abstracted, so that instead of being resolved, this reference
+	// is removed from any usages of the declaration.
+	Drop()
+	Dropped() bool
 }
 
 type TempDeclRefArgs struct {
@@ -28,3 +28,21 @@ func (f *factoryImp) TempDeclRefs() collections.ReadonlySortedSet[constructs.Tem
 func (f *factoryImp) ClearAllTempDeclRefs() {
 	f.Items().Clear()
 }
+
+// Rollback removes the references added after the given number of
+// references had been added, except for any dropped reference. A dropped
+// reference is kept so that any usage of the declaration that failed,
+// found after the rollback, is also dropped instead of left unresolved.
+func (f *factoryImp) Rollback(added int, removed map[constructs.Construct]bool) {
+	dropped := []constructs.TempDeclRef{}
+	for _, r := range f.AddedSince(added) {
+		if r.Dropped() {
+			dropped = append(dropped, r)
+		}
+	}
+	f.FactoryCore.Rollback(added, removed)
+	for _, r := range dropped {
+		delete(removed, r)
+		f.Add(r)
+	}
+}
@@ -23,6 +23,7 @@ type tempDeclRefImp struct {
 	nest          constructs.NestType
 	con           constructs.Construct
 	funcType      *types.Func
+	dropped       bool
 }
 
 func newTempDeclRef(args constructs.TempDeclRefArgs) constructs.TempDeclRef {
@@ -71,6 +72,9 @@ func (r *tempDeclRefImp) SetResolution(con constructs.Construct) {
 	r.con = con
 }
 
+func (r *tempDeclRefImp) Drop()         { r.dropped = true }
+func (r *tempDeclRefImp) Dropped() bool { return r.dropped }
+
 func (r *tempDeclRefImp) RemoveTempDeclRefs(required bool) bool {
 	if !utils.IsNil(r.nest) {
 		nest, changed := constructs.ResolvedTempDeclRef(r.nest, required)
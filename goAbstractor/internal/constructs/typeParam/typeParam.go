@@ -95,6 +95,7 @@ func (t *typeParamImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	return jsonify.NewMap().
 		AddIf(ctx, ctx.IsDebugKindIncluded(), `kind`, t.Kind()).
 		AddIf(ctx, ctx.IsDebugIndexIncluded(), `index`, t.Index()).
+		AddNonZeroIf(ctx, ctx.IncludeStableIDs(), `id`, t.StableID()).
 		AddIf(ctx, ctx.IsDebugAliveIncluded(), `alive`, t.Alive()).
 		Add(ctx, `name`, t.name).
 		Add(ctx.Short(), `type`, t.typ)
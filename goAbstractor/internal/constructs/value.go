@@ -43,6 +43,12 @@ type ValueArgs struct {
 	Type     TypeDesc
 	Const    bool
 
+	// TestCode indicates the declaration is defined in a test file.
+	TestCode bool
+
+	// Generated indicates the declaration is defined in a generated file.
+	Generated bool
+
 	// Metrics are optional and may be nil. These metrics are for
 	// a variable initialized with an anonymous function.
 	// (e.g. `var x = func() int { ⋯ }()`)
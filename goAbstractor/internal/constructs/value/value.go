@@ -14,13 +14,16 @@ import (
 
 type valueImp struct {
 	constructs.ConstructCore
-	pkg      constructs.Package
-	name     string
-	exported bool
-	loc      locs.Loc
-	typ      constructs.TypeDesc
-	isConst  bool
-	metrics  constructs.Metrics
+	constructs.BuildConfigSet
+	pkg       constructs.Package
+	name      string
+	exported  bool
+	loc       locs.Loc
+	testCode  bool
+	generated bool
+	typ       constructs.TypeDesc
+	isConst   bool
+	metrics   constructs.Metrics
 }
 
 func newValue(args constructs.ValueArgs) constructs.Value {
@@ -33,13 +36,15 @@ func newValue(args constructs.ValueArgs) constructs.Value {
 	assert.ArgNotNil(`location`, args.Location)
 
 	return &valueImp{
-		pkg:      args.Package,
-		name:     args.Name,
-		exported: args.Exported,
-		loc:      args.Location,
-		typ:      args.Type,
-		isConst:  args.Const,
-		metrics:  args.Metrics,
+		pkg:       args.Package,
+		name:      args.Name,
+		exported:  args.Exported,
+		loc:       args.Location,
+		testCode:  args.TestCode,
+		generated: args.Generated,
+		typ:       args.Type,
+		isConst:   args.Const,
+		metrics:   args.Metrics,
 	}
 }
 
@@ -51,6 +56,8 @@ func (v *valueImp) Const() bool        { return v.isConst }
 func (v *valueImp) Name() string       { return v.name }
 func (v *valueImp) Exported() bool     { return v.exported }
 func (v *valueImp) Location() locs.Loc { return v.loc }
+func (v *valueImp) TestCode() bool     { return v.testCode }
+func (v *valueImp) Generated() bool    { return v.generated }
 
 func (v *valueImp) Package() constructs.Package        { return v.pkg }
 func (v *valueImp) Type() constructs.TypeDesc          { return v.typ }
@@ -107,13 +114,20 @@ func (v *valueImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	return jsonify.NewMap().
 		AddIf(ctx, ctx.IsDebugKindIncluded(), `kind`, v.Kind()).
 		AddIf(ctx, ctx.IsDebugIndexIncluded(), `index`, v.Index()).
+		AddNonZeroIf(ctx, ctx.IncludeStableIDs(), `id`, v.StableID()).
 		AddIf(ctx, ctx.IsDebugAliveIncluded(), `alive`, v.Alive()).
 		Add(ctx.OnlyIndex(), `package`, v.pkg).
 		Add(ctx, `name`, v.name).
 		Add(ctx.Short(), `type`, v.typ).
 		AddNonZero(ctx, `loc`, v.loc).
+		AddNonZeroIf(ctx, ctx.IncludeRanges(), `range`, locs.RangeOf(v.loc)).
 		AddNonZero(ctx, `const`, v.isConst).
 		AddNonZeroIf(ctx, v.exported, `vis`, `exported`).
+		AddNonZero(ctx, `test`, v.testCode).
+		AddNonZero(ctx, `generated`, v.generated).
+		AddNonZero(ctx, `builds`, v.BuildConfigs()).
+		AddNonZeroIf(ctx, ctx.IncludeAliveReasons(), `aliveReason`, v.AliveReason()).
+		AddNonZeroIf(ctx.Short(), ctx.IncludeAliveReasons(), `aliveBy`, v.AliveBy()).
 		AddNonZero(ctx.OnlyIndex(), `metrics`, v.metrics)
 }
 
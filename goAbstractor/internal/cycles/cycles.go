@@ -0,0 +1,205 @@
+package cycles
+
+import (
+	"slices"
+
+	"github.com/Snow-Gremlin/goToolbox/collections"
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+)
+
+// Find determines the dependency cycles between the declarations
+// in the read packages. The dead-code elimination must have already been
+// run. The cycles are only found when they are outputted since finding them
+// requires building the dependency graph of every declaration.
+//
+// A declaration depends on another declaration when the other declaration,
+// or an instance, pointer, or selection of it, is read, written, or invoked
+// in the metrics of the declaration, or is referenced by the type of a field,
+// parameter, or result of the declaration. The cycles are the strongly
+// connected components with two or more declarations. If skipDead is true,
+// the declarations found to be dead are not included.
+func Find(proj constructs.Project, skipDead bool) []*constructs.Cycle {
+	g := &graph{
+		index: map[constructs.Declaration]int{},
+		deps:  map[constructs.Declaration]map[constructs.Declaration]*constructs.Dependency{},
+		order: map[constructs.Declaration][]constructs.Declaration{},
+	}
+	addNodes(g, proj.InterfaceDecls(), skipDead)
+	addNodes(g, proj.Objects(), skipDead)
+	addNodes(g, proj.Methods(), skipDead)
+	addNodes(g, proj.Values(), skipDead)
+
+	for _, decl := range g.nodes {
+		g.addDependencies(decl)
+	}
+
+	cycles := []*constructs.Cycle{}
+	for _, comp := range stronglyConnected(g.nodes, g.targets) {
+		if len(comp) > 1 {
+			cycles = append(cycles, g.newCycle(comp))
+		}
+	}
+	slices.SortStableFunc(cycles, func(a, b *constructs.Cycle) int {
+		return len(b.Declarations) - len(a.Declarations)
+	})
+	return cycles
+}
+
+// graph is the dependency graph between declarations.
+type graph struct {
+	nodes []constructs.Declaration
+	index map[constructs.Declaration]int
+	deps  map[constructs.Declaration]map[constructs.Declaration]*constructs.Dependency
+	order map[constructs.Declaration][]constructs.Declaration
+}
+
+func addNodes[T constructs.Declaration](g *graph, decls collections.ReadonlySortedSet[T], skipDead bool) {
+	for decl := range decls.Enumerate().Seq() {
+		if decl.Duplicate() || !decl.Package().EntryPoint() || (skipDead && !decl.Alive()) {
+			continue
+		}
+		g.index[decl] = len(g.nodes)
+		g.nodes = append(g.nodes, decl)
+	}
+}
+
+func (g *graph) addDependencies(decl constructs.Declaration) {
+	switch t := decl.(type) {
+	case constructs.InterfaceDecl:
+		g.addType(decl, constructs.DependencySignature, t.Interface(), map[constructs.Construct]bool{})
+	case constructs.Object:
+		if data := t.Data(); !utils.IsNil(data) {
+			for _, f := range data.Fields() {
+				g.addType(decl, constructs.DependencyField, f.Type(), map[constructs.Construct]bool{})
+			}
+		}
+	case constructs.Method:
+		g.addType(decl, constructs.DependencySignature, t.Signature(), map[constructs.Construct]bool{})
+		g.addMetrics(decl, t.Metrics())
+	case constructs.Value:
+		g.addMetrics(decl, t.Metrics())
+	}
+}
+
+func (g *graph) addMetrics(decl constructs.Declaration, metrics constructs.Metrics) {
+	if utils.IsNil(metrics) {
+		return
+	}
+	for c := range metrics.Reads().Enumerate().Seq() {
+		g.addUsage(decl, constructs.DependencyRead, c)
+	}
+	for c := range metrics.Writes().Enumerate().Seq() {
+		g.addUsage(decl, constructs.DependencyWrite, c)
+	}
+	for c := range metrics.Invokes().Enumerate().Seq() {
+		g.addUsage(decl, constructs.DependencyInvoke, c)
+	}
+}
+
+// addUsage adds a dependency for a construct used in the metrics.
+// An instance or selection is a usage of the declaration it is from.
+// A selection of a method is also a usage of the selected method.
+func (g *graph) addUsage(from constructs.Declaration, kind constructs.DependencyKind, c constructs.Construct) {
+	switch t := c.(type) {
+	case constructs.Declaration:
+		g.addDependency(from, kind, t)
+	case constructs.Selection:
+		g.addUsage(from, kind, t.Origin())
+		if obj := constructs.SelectedObject(t.Origin()); !utils.IsNil(obj) {
+			for m := range obj.Methods().Enumerate().Seq() {
+				if m.Name() == t.Name() {
+					g.addDependency(from, kind, m)
+				}
+			}
+		}
+	case constructs.MethodInst:
+		g.addDependency(from, kind, t.Generic())
+	case constructs.TypeDesc:
+		g.addType(from, kind, t, map[constructs.Construct]bool{})
+	}
+}
+
+// addType adds a dependency for each declaration referenced by a type,
+// including the type arguments of instances and the types of anonymous
+// structs, signatures, and interfaces.
+func (g *graph) addType(from constructs.Declaration, kind constructs.DependencyKind, c constructs.Construct, visited map[constructs.Construct]bool) {
+	if utils.IsNil(c) || visited[c] {
+		return
+	}
+	visited[c] = true
+
+	switch t := c.(type) {
+	case constructs.Declaration:
+		g.addDependency(from, kind, t)
+	case constructs.ObjectInst:
+		g.addDependency(from, kind, t.Generic())
+		for _, it := range t.InstanceTypes() {
+			g.addType(from, kind, it, visited)
+		}
+	case constructs.InterfaceInst:
+		g.addDependency(from, kind, t.Generic())
+		for _, it := range t.InstanceTypes() {
+			g.addType(from, kind, it, visited)
+		}
+	case constructs.StructDesc:
+		for _, f := range t.Fields() {
+			g.addType(from, kind, f.Type(), visited)
+		}
+	case constructs.Signature:
+		for _, p := range t.Params() {
+			g.addType(from, kind, p.Type(), visited)
+		}
+		for _, r := range t.Results() {
+			g.addType(from, kind, r.Type(), visited)
+		}
+	case constructs.InterfaceDesc:
+		for _, a := range t.Abstracts() {
+			g.addType(from, kind, a.Signature(), visited)
+		}
+	}
+}
+
+// addDependency adds the kind of dependency from one declaration to another.
+// Dependencies on declarations outside of the graph are ignored.
+func (g *graph) addDependency(from constructs.Declaration, kind constructs.DependencyKind, to constructs.Declaration) {
+	if _, has := g.index[to]; !has {
+		return
+	}
+	deps, has := g.deps[from]
+	if !has {
+		deps = map[constructs.Declaration]*constructs.Dependency{}
+		g.deps[from] = deps
+	}
+	dep, has := deps[to]
+	if !has {
+		dep = &constructs.Dependency{From: from, To: to}
+		deps[to] = dep
+		g.order[from] = append(g.order[from], to)
+	}
+	if !slices.Contains(dep.Kinds, kind) {
+		dep.Kinds = append(dep.Kinds, kind)
+	}
+}
+
+// targets gets the declarations that the given declaration depends on
+// in the order the dependencies were added.
+func (g *graph) targets(decl constructs.Declaration) []constructs.Declaration {
+	return g.order[decl]
+}
+
+func (g *graph) newCycle(comp []constructs.Declaration) *constructs.Cycle {
+	slices.SortFunc(comp, func(a, b constructs.Declaration) int {
+		return g.index[a] - g.index[b]
+	})
+	cycle := &constructs.Cycle{Declarations: comp}
+	for _, from := range comp {
+		for _, to := range comp {
+			if dep, has := g.deps[from][to]; has {
+				cycle.Dependencies = append(cycle.Dependencies, dep)
+			}
+		}
+	}
+	return cycle
+}
@@ -0,0 +1,63 @@
+package cycles
+
+// stronglyConnected determines the strongly connected components of the
+// directed graph with the given nodes and edges using Tarjan's algorithm.
+//
+// The components are returned in reverse topological order, such that
+// a component only has edges to itself and the components before it.
+// Each node is in exactly one component, so a node that isn't part of
+// any cycle is returned in a component by itself.
+func stronglyConnected[T comparable](nodes []T, edges func(T) []T) [][]T {
+	s := &scc[T]{
+		edges:   edges,
+		index:   map[T]int{},
+		low:     map[T]int{},
+		onStack: map[T]bool{},
+	}
+	for _, node := range nodes {
+		if _, has := s.index[node]; !has {
+			s.connect(node)
+		}
+	}
+	return s.comps
+}
+
+type scc[T comparable] struct {
+	edges   func(T) []T
+	index   map[T]int
+	low     map[T]int
+	onStack map[T]bool
+	stack   []T
+	comps   [][]T
+}
+
+func (s *scc[T]) connect(node T) {
+	s.index[node] = len(s.index)
+	s.low[node] = s.index[node]
+	s.stack = append(s.stack, node)
+	s.onStack[node] = true
+
+	for _, other := range s.edges(node) {
+		if _, has := s.index[other]; !has {
+			s.connect(other)
+			s.low[node] = min(s.low[node], s.low[other])
+		} else if s.onStack[other] {
+			s.low[node] = min(s.low[node], s.index[other])
+		}
+	}
+
+	if s.low[node] == s.index[node] {
+		comp := []T{}
+		for {
+			last := len(s.stack) - 1
+			other := s.stack[last]
+			s.stack = s.stack[:last]
+			s.onStack[other] = false
+			comp = append(comp, other)
+			if other == node {
+				break
+			}
+		}
+		s.comps = append(s.comps, comp)
+	}
+}
@@ -0,0 +1,48 @@
+package cycles
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+)
+
+func Test_StronglyConnected(t *testing.T) {
+	checkSCC(t, `A`, `A`)
+	checkSCC(t, `A->A`, `A`)
+	checkSCC(t, `A->B`, `B`, `A`)
+	checkSCC(t, `A->B B->A`, `AB`)
+	checkSCC(t, `A->B B->C C->A C->D D->E E->D`, `DE`, `ABC`)
+	checkSCC(t, `A->B B->C C->B A->D D->A`, `BC`, `AD`)
+	checkSCC(t, `A->B B->C C->D D->B D->E E->F F->E F->A`, `ABCDEF`)
+}
+
+// checkSCC checks the strongly connected components of a graph
+// given as space separated edges, e.g. `A->B`, or lone nodes, e.g. `A`.
+// The nodes in each expected component are sorted.
+func checkSCC(t *testing.T, graph string, exp ...string) {
+	nodes := []string{}
+	edges := map[string][]string{}
+	addNode := func(node string) {
+		if !slices.Contains(nodes, node) {
+			nodes = append(nodes, node)
+		}
+	}
+	for _, part := range strings.Fields(graph) {
+		from, to, hasEdge := strings.Cut(part, `->`)
+		addNode(from)
+		if hasEdge {
+			addNode(to)
+			edges[from] = append(edges[from], to)
+		}
+	}
+
+	result := []string{}
+	comps := stronglyConnected(nodes, func(node string) []string { return edges[node] })
+	for _, comp := range comps {
+		slices.Sort(comp)
+		result = append(result, strings.Join(comp, ``))
+	}
+	check.Equal(t, exp).Name(graph).Assert(result)
+}
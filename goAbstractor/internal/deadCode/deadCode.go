@@ -0,0 +1,289 @@
+package deadCode
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Snow-Gremlin/goToolbox/collections"
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/hint"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/kind"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+)
+
+// Report is the declarations that the dead-code elimination found to be
+// unreachable, grouped by package. Only the packages that were read are
+// reported, not the dependencies of the read packages.
+type Report struct {
+	Packages []*Package
+}
+
+// Package is the dead declarations in one package.
+type Package struct {
+	Path    string
+	Entries []*Entry
+}
+
+// Entry is a dead declaration, method, value, or field.
+//
+// The dead-code elimination doesn't determine the liveness of each field,
+// only of the object the field is in. The fields of a dead object are dead
+// with the object so they aren't reported. A field of an alive object is
+// reported as dead if no alive metrics selects it, i.e. the field is never
+// read or written, directly or through an embedded field.
+type Entry struct {
+	// Kind is the kind of the dead construct.
+	Kind kind.Kind
+
+	// Name is the name of the dead construct within its package,
+	// see constructs.LocalName, e.g. `Foo.Bar` for a method with a receiver.
+	// The name of a field is the name of its object and the field name,
+	// e.g. `Foo.bar`.
+	Name string
+
+	// Loc is the location of the dead construct.
+	// The location of a field is the location of its object.
+	Loc locs.Loc
+
+	// Complexity, LineCount, and CodeCount are the size metrics of the
+	// dead method or value. These are zero for types.
+	Complexity int
+	LineCount  int
+	CodeCount  int
+}
+
+// New creates a report of the dead code in the given project.
+// The dead-code elimination must have already been run.
+func New(proj constructs.Project) *Report {
+	pkgs := map[constructs.Package]*Package{}
+	add := func(decl constructs.Declaration, e *Entry) {
+		if decl.Duplicate() || !decl.Package().EntryPoint() {
+			return
+		}
+		p, has := pkgs[decl.Package()]
+		if !has {
+			p = &Package{Path: decl.Package().Path()}
+			pkgs[decl.Package()] = p
+		}
+		p.Entries = append(p.Entries, e)
+	}
+	addDead := func(decl constructs.Declaration, metrics constructs.Metrics) {
+		if !decl.Alive() {
+			add(decl, newEntry(decl, metrics))
+		}
+	}
+
+	for it := range proj.InterfaceDecls().Enumerate().Seq() {
+		addDead(it, nil)
+	}
+
+	used := usedFields(proj)
+	for obj := range proj.Objects().Enumerate().Seq() {
+		if !obj.Alive() {
+			addDead(obj, nil)
+			continue
+		}
+		for _, f := range unusedFields(obj, used) {
+			add(obj, &Entry{
+				Kind: kind.Field,
+				Name: constructs.LocalName(obj) + `.` + f.Name(),
+				Loc:  obj.Location(),
+			})
+		}
+	}
+
+	for m := range proj.Methods().Enumerate().Seq() {
+		if !m.IsNamed() {
+			continue
+		}
+		addDead(m, m.Metrics())
+	}
+
+	for v := range proj.Values().Enumerate().Seq() {
+		addDead(v, v.Metrics())
+	}
+
+	r := &Report{Packages: slices.Collect(maps.Values(pkgs))}
+	slices.SortFunc(r.Packages, func(a, b *Package) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	for _, p := range r.Packages {
+		slices.SortFunc(p.Entries, compareEntries)
+	}
+	return r
+}
+
+func newEntry(decl constructs.Declaration, metrics constructs.Metrics) *Entry {
+	e := &Entry{Kind: decl.Kind(), Name: constructs.LocalName(decl), Loc: decl.Location()}
+	if !utils.IsNil(metrics) {
+		e.Complexity = metrics.Complexity()
+		e.LineCount = metrics.LineCount()
+		e.CodeCount = metrics.CodeCount()
+	}
+	return e
+}
+
+// fieldKey is a field, by name, of an object.
+type fieldKey struct {
+	obj  constructs.Object
+	name string
+}
+
+// usedFields gets the fields that are selected, i.e. read or written,
+// in any alive metrics. A field selected through an embedded field,
+// e.g. `a.x` where `x` is in the embedded `B`, is the field in `B`
+// and the embedded field `B` in `a` is also used.
+func usedFields(proj constructs.Project) map[fieldKey]bool {
+	used := map[fieldKey]bool{}
+	for m := range proj.Metrics().Enumerate().Seq() {
+		if !m.Alive() {
+			continue
+		}
+		for _, usages := range []collections.ReadonlySortedSet[constructs.Construct]{m.Reads(), m.Writes(), m.Invokes()} {
+			for c := range usages.Enumerate().Seq() {
+				if sel, ok := c.(constructs.Selection); ok {
+					if obj := objectOf(sel.Origin()); obj != nil {
+						selectField(used, obj, sel.Name(), map[constructs.Object]bool{})
+					}
+				}
+			}
+		}
+	}
+	return used
+}
+
+// selectField marks the field with the given name in the given object as
+// used, or the embedded field that the field or method is promoted from.
+// Returns true if the object has a field or method with the given name.
+func selectField(used map[fieldKey]bool, obj constructs.Object, name string, touched map[constructs.Object]bool) bool {
+	if touched[obj] {
+		return false
+	}
+	touched[obj] = true
+
+	if obj.Methods().Enumerate().Any(func(m constructs.Method) bool { return m.Name() == name }) {
+		return true
+	}
+	data := obj.Data()
+	if utils.IsNil(data) {
+		return false
+	}
+	fields := data.Fields()
+	for _, f := range fields {
+		if f.Name() == name {
+			used[fieldKey{obj: obj, name: name}] = true
+			return true
+		}
+	}
+	for _, f := range fields {
+		if f.Embedded() && promotes(used, f.Type(), name, touched) {
+			used[fieldKey{obj: obj, name: f.Name()}] = true
+			return true
+		}
+	}
+	return false
+}
+
+// promotes determines if the type of an embedded field has a field
+// or method with the given name that is promoted from the embedded field.
+func promotes(used map[fieldKey]bool, typ constructs.TypeDesc, name string, touched map[constructs.Object]bool) bool {
+	if obj := objectOf(typ); obj != nil {
+		return selectField(used, obj, name, touched)
+	}
+	var it constructs.InterfaceDesc
+	switch t := typ.(type) {
+	case constructs.InterfaceDecl:
+		it = t.Interface()
+	case constructs.InterfaceInst:
+		it = t.Resolved()
+	case constructs.InterfaceDesc:
+		it = t
+	default:
+		return false
+	}
+	for _, ab := range it.Abstracts() {
+		if ab.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// objectOf gets the object that the given origin of a selection is,
+// an instance of, or a pointer to. Returns nil if it isn't an object.
+func objectOf(c constructs.Construct) constructs.Object {
+	switch t := c.(type) {
+	case constructs.Object:
+		return t
+	case constructs.ObjectInst:
+		return t.Generic()
+	case constructs.InterfaceInst:
+		if t.Generic().Interface().Hint() == hint.Pointer && len(t.InstanceTypes()) == 1 {
+			return objectOf(t.InstanceTypes()[0])
+		}
+	}
+	return nil
+}
+
+// unusedFields gets the fields of the given alive object that aren't used.
+// The blank fields, e.g. for padding, and the data of a synthetic
+// object, e.g. `type A []int`, can't be selected so aren't included.
+func unusedFields(obj constructs.Object, used map[fieldKey]bool) []constructs.Field {
+	data := obj.Data()
+	if utils.IsNil(data) || data.Synthetic() {
+		return nil
+	}
+	unused := []constructs.Field{}
+	for _, f := range data.Fields() {
+		if f.Name() != `_` && !used[fieldKey{obj: obj, name: f.Name()}] {
+			unused = append(unused, f)
+		}
+	}
+	return unused
+}
+
+func compareEntries(a, b *Entry) int {
+	if c := strings.Compare(a.Name, b.Name); c != 0 {
+		return c
+	}
+	return strings.Compare(string(a.Kind), string(b.Kind))
+}
+
+// LineCount is the total number of lines of dead methods and values.
+func (p *Package) LineCount() int {
+	count := 0
+	for _, e := range p.Entries {
+		count += e.LineCount
+	}
+	return count
+}
+
+func (r *Report) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	if r == nil {
+		return nil
+	}
+	return jsonify.New(ctx, r.Packages)
+}
+
+func (p *Package) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `path`, p.Path).
+		Add(ctx, `count`, len(p.Entries)).
+		AddNonZero(ctx, `lineCount`, p.LineCount()).
+		Add(ctx, `entries`, p.Entries)
+}
+
+func (e *Entry) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `kind`, string(e.Kind)).
+		Add(ctx, `name`, e.Name).
+		AddNonZero(ctx, `loc`, e.Loc).
+		AddNonZeroIf(ctx, ctx.IncludeRanges(), `range`, locs.RangeOf(e.Loc)).
+		AddNonZero(ctx, `complexity`, e.Complexity).
+		AddNonZero(ctx, `lineCount`, e.LineCount).
+		AddNonZero(ctx, `codeCount`, e.CodeCount)
+}
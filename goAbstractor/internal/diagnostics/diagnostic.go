@@ -0,0 +1,126 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+)
+
+// Phase is the part of the abstraction that a failure occurred in.
+type Phase string
+
+const (
+	Load           Phase = `load`
+	Abstract       Phase = `abstract`
+	Analyze        Phase = `analyze`
+	Imports        Phase = `imports`
+	Receivers      Phase = `receivers`
+	References     Phase = `references`
+	Instantiations Phase = `instantiations`
+	Duplicates     Phase = `duplicates`
+	Interfaces     Phase = `interfaces`
+	Inheritance    Phase = `inheritance`
+	DeadCode       Phase = `deadCode`
+)
+
+// Diagnostic is a failure that was recovered from so that
+// the abstraction could continue without the failed part.
+type Diagnostic struct {
+	// Phase is the part of the abstraction the failure occurred in.
+	Phase Phase
+
+	// Name is the name of the declaration or construct that failed.
+	// This may be empty if the failure wasn't specific to one construct.
+	Name string
+
+	// Loc is the optional location of the failed declaration.
+	Loc locs.Loc
+
+	// Err is the recovered error.
+	Err error
+}
+
+func (d Diagnostic) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	m := jsonify.NewMap().
+		Add(ctx, `phase`, string(d.Phase)).
+		AddNonZero(ctx, `name`, d.Name)
+	if !utils.IsNil(d.Loc) {
+		_, file, line := d.Loc.Info()
+		m.AddNonZero(ctx, `file`, file).
+			AddNonZero(ctx, `line`, line)
+	}
+	if d.Err != nil {
+		m.Add(ctx, `error`, d.Err.Error())
+	}
+	return m
+}
+
+// phaseError is a recovered panic that has been tagged with
+// the phase it occurred in while being passed to an outer phase.
+type phaseError struct {
+	phase Phase
+	name  string
+	loc   locs.Loc
+	err   error
+}
+
+func (e *phaseError) Error() string { return e.err.Error() }
+func (e *phaseError) Unwrap() error { return e.err }
+
+// inPhase runs the given handle such that any panic from it is tagged with
+// the given phase unless it was already tagged by an inner phase.
+// The optional name and location of the construct being handled are also
+// tagged unless already tagged by an inner, more specific, construct.
+func inPhase(phase Phase, name string, loc locs.Loc, handle func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			err := toError(r)
+			pe := (*phaseError)(nil)
+			if !errors.As(err, &pe) {
+				pe = &phaseError{phase: phase, err: err}
+				err = pe
+			}
+			if len(pe.name) <= 0 {
+				pe.name = name
+			}
+			if utils.IsNil(pe.loc) {
+				pe.loc = loc
+			}
+			panic(err)
+		}
+	}()
+	handle()
+}
+
+// FromPanic creates a diagnostic for a recovered panic. If the panic was
+// tagged while passing through phases, the diagnostic has the phase,
+// name, and location of the inner most phase and construct that failed.
+func FromPanic(r any) Diagnostic {
+	err := toError(r)
+	if pe := (*phaseError)(nil); errors.As(err, &pe) {
+		return Diagnostic{
+			Phase: pe.phase,
+			Name:  pe.name,
+			Loc:   pe.loc,
+			Err:   pe.err,
+		}
+	}
+	return Diagnostic{Err: err}
+}
+
+// isCancellation determines if the given error is from a cancelled context.
+func isCancellation(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func toError(r any) error {
+	if err, ok := r.(error); ok {
+		return err
+	}
+	return terror.RecoveredPanic(r)
+}
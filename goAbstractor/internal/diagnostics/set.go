@@ -0,0 +1,157 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"go/token"
+	"slices"
+
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+)
+
+// Set is the collection of diagnostics for a project.
+type Set interface {
+	jsonify.Jsonable
+
+	// Tolerant indicates that failures are recovered and recorded
+	// instead of panicking out of the abstraction.
+	Tolerant() bool
+
+	// SetTolerant sets if failures should be recovered and recorded.
+	SetTolerant(tolerant bool)
+
+	// SetContext sets the optional context that cancels the abstraction.
+	// The context is checked before each handle is run by Tolerate or
+	// InPhase and, once cancelled, the context's error is panicked.
+	// A cancellation is never recovered, even when tolerant.
+	SetContext(ctx context.Context)
+
+	// CheckCancelled panics with the context's error, tagged with the given
+	// phase, name, and optional location, if the context has been cancelled.
+	// This is used inside of loops whose items aren't run by Tolerate.
+	CheckCancelled(phase Phase, name string, loc locs.Loc)
+
+	// Tolerate runs the given handle. When tolerant, any panic from the
+	// handle is recovered and recorded as a diagnostic with the given
+	// phase, name, and optional location, otherwise the panic continues
+	// tagged with the phase, name, and location (see FromPanic).
+	// Returns true if the handle finished without a failure.
+	Tolerate(phase Phase, name string, loc locs.Loc, handle func()) bool
+
+	// InPhase runs the given handle. Any panic from the handle is tagged
+	// with the given phase, unless already tagged by an inner phase,
+	// so that when it is recovered by an outer call to Tolerate,
+	// or by FromPanic, the failure is reported with the inner phase.
+	InPhase(phase Phase, handle func())
+
+	// Add adds a diagnostic to this set. A diagnostic with the same phase,
+	// position, and error as one already in the set isn't added again,
+	// e.g. a declaration that fails in more than one build configuration.
+	// A diagnostic without a position is matched by its name instead.
+	Add(d Diagnostic)
+
+	// Diagnostics gets the diagnostics in the order they were added.
+	Diagnostics() []Diagnostic
+}
+
+type setImp struct {
+	tolerant bool
+	ctx      context.Context
+	diags    []Diagnostic
+	added    map[diagKey]bool
+}
+
+// diagKey is the phase, position, or name if there isn't a position,
+// and error text that a diagnostic is deduplicated by. The position is
+// the file, line, and column so that the same declaration read for more
+// than one build configuration has the same position. The error text keeps
+// different failures at the same position from being merged into one.
+type diagKey struct {
+	phase Phase
+	pos   token.Position
+	name  string
+	err   string
+}
+
+// NewSet creates a new set of diagnostics that isn't tolerant.
+func NewSet() Set {
+	return &setImp{
+		added: map[diagKey]bool{},
+	}
+}
+
+func (s *setImp) Tolerant() bool            { return s.tolerant }
+func (s *setImp) SetTolerant(tolerant bool) { s.tolerant = tolerant }
+
+func (s *setImp) SetContext(ctx context.Context) { s.ctx = ctx }
+
+func (s *setImp) CheckCancelled(phase Phase, name string, loc locs.Loc) {
+	if s.ctx == nil {
+		return
+	}
+	if err := s.ctx.Err(); err != nil {
+		panic(&phaseError{phase: phase, name: name, loc: loc, err: err})
+	}
+}
+
+func (s *setImp) Tolerate(phase Phase, name string, loc locs.Loc, handle func()) (ok bool) {
+	s.CheckCancelled(phase, name, loc)
+	if !s.tolerant {
+		inPhase(phase, name, loc, handle)
+		return true
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err := toError(r)
+			if isCancellation(err) {
+				panic(err)
+			}
+			if pe := (*phaseError)(nil); errors.As(err, &pe) {
+				phase, err = pe.phase, pe.err
+			}
+			s.Add(Diagnostic{
+				Phase: phase,
+				Name:  name,
+				Loc:   loc,
+				Err:   err,
+			})
+			ok = false
+		}
+	}()
+	handle()
+	return true
+}
+
+func (s *setImp) InPhase(phase Phase, handle func()) {
+	s.CheckCancelled(phase, ``, nil)
+	inPhase(phase, ``, nil, handle)
+}
+
+func (s *setImp) Add(d Diagnostic) {
+	key := diagKey{phase: d.Phase}
+	if d.Err != nil {
+		key.err = d.Err.Error()
+	}
+	if !utils.IsNil(d.Loc) && d.Loc.Pos().IsValid() {
+		key.pos = d.Loc.Position()
+	} else {
+		key.name = d.Name
+	}
+	if s.added[key] {
+		return
+	}
+	s.added[key] = true
+	s.diags = append(s.diags, d)
+}
+
+func (s *setImp) Diagnostics() []Diagnostic {
+	return slices.Clone(s.diags)
+}
+
+func (s *setImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewListWith(ctx, s.diags)
+}
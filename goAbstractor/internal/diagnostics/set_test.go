@@ -0,0 +1,40 @@
+package diagnostics
+
+import (
+	"errors"
+	"go/token"
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+)
+
+func Test_Set_Add(t *testing.T) {
+	fs := token.NewFileSet()
+	file := fs.AddFile(`foo.go`, -1, 100)
+	file.SetLines([]int{0, 10, 20})
+	ls := locs.NewSet(fs)
+	loc := ls.NewLoc(file.Pos(12))
+
+	s := NewSet()
+	s.Add(Diagnostic{Phase: Abstract, Name: `foo`, Loc: loc, Err: errors.New(`first`)})
+	s.Add(Diagnostic{Phase: Abstract, Name: `foo`, Loc: loc, Err: errors.New(`first`)})  // identical, skipped
+	s.Add(Diagnostic{Phase: Abstract, Name: `foo`, Loc: loc, Err: errors.New(`second`)}) // different error
+	s.Add(Diagnostic{Phase: Analyze, Name: `foo`, Loc: loc, Err: errors.New(`first`)})   // different phase
+	s.Add(Diagnostic{Phase: References, Name: `bar`, Err: errors.New(`first`)})
+	s.Add(Diagnostic{Phase: References, Name: `bar`, Err: errors.New(`first`)}) // identical, skipped
+	s.Add(Diagnostic{Phase: References, Name: `baz`, Err: errors.New(`first`)}) // different name
+
+	errs := []string{}
+	for _, d := range s.Diagnostics() {
+		errs = append(errs, string(d.Phase)+` `+d.Name+` `+d.Err.Error())
+	}
+	check.Equal(t, errs).Assert([]string{
+		`abstract foo first`,
+		`abstract foo second`,
+		`analyze foo first`,
+		`references bar first`,
+		`references baz first`,
+	})
+}
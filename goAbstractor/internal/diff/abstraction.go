@@ -0,0 +1,194 @@
+package diff
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// measures are the named values, such as complexity or
+// the number of fields, to compare for a construct.
+type measures map[string]int
+
+// entry is the measures of a construct with the qualified name of
+// the construct and the stable identifier, if the abstraction was
+// outputted with stable identifiers.
+type entry struct {
+	id       string
+	name     string
+	measures measures
+}
+
+// Abstraction is the measures of the constructs in an abstraction
+// output file in the order the constructs were outputted.
+type Abstraction struct {
+	Packages   []*entry
+	Objects    []*entry
+	Interfaces []*entry
+	Methods    []*entry
+	Values     []*entry
+}
+
+// The parts of the abstraction output that are needed to compare.
+// The references to other constructs are the one-based indices
+// into the list for that kind of construct.
+type (
+	rawProject struct {
+		Packages       []rawPackage   `json:"packages"`
+		Objects        []rawDecl      `json:"objects"`
+		InterfaceDecls []rawDecl      `json:"interfaceDecls"`
+		Methods        []rawDecl      `json:"methods"`
+		Values         []rawDecl      `json:"values"`
+		Metrics        []rawMetrics   `json:"metrics"`
+		StructDescs    []rawStruct    `json:"structDescs"`
+		InterfaceDescs []rawInterface `json:"interfaceDescs"`
+	}
+
+	rawPackage struct {
+		ID         string `json:"id"`
+		Path       string `json:"path"`
+		Objects    []int  `json:"objects"`
+		Interfaces []int  `json:"interfaces"`
+		Methods    []int  `json:"methods"`
+		Values     []int  `json:"values"`
+	}
+
+	rawDecl struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		Package   int    `json:"package"`
+		Nest      string `json:"nest"`
+		Receiver  int    `json:"receiver"`
+		Metrics   int    `json:"metrics"`
+		Data      int    `json:"data"`
+		Interface int    `json:"interface"`
+		Methods   []int  `json:"methods"`
+	}
+
+	rawMetrics struct {
+		Complexity int      `json:"complexity"`
+		LineCount  int      `json:"lineCount"`
+		CodeCount  int      `json:"codeCount"`
+		Reads      []string `json:"reads"`
+		Writes     []string `json:"writes"`
+		Invokes    []string `json:"invokes"`
+	}
+
+	rawStruct struct {
+		Fields []int `json:"fields"`
+	}
+
+	rawInterface struct {
+		Abstracts []int `json:"abstracts"`
+	}
+)
+
+// Read reads the abstraction output file at the given path.
+func Read(path string) (*Abstraction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse parses the given abstraction output.
+func Parse(data []byte) (*Abstraction, error) {
+	raw := &rawProject{}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return nil, err
+	}
+	return raw.toAbstraction(), nil
+}
+
+func (raw *rawProject) toAbstraction() *Abstraction {
+	a := &Abstraction{}
+	add := func(entries *[]*entry, id, name string, m measures) {
+		*entries = append(*entries, &entry{id: id, name: name, measures: m})
+	}
+
+	for _, p := range raw.Packages {
+		add(&a.Packages, p.ID, p.Path, measures{
+			`objects`:    len(p.Objects),
+			`interfaces`: len(p.Interfaces),
+			`methods`:    len(p.Methods),
+			`values`:     len(p.Values),
+		})
+	}
+
+	for _, d := range raw.Objects {
+		m := measures{`methods`: len(d.Methods)}
+		if s, ok := at(raw.StructDescs, d.Data); ok {
+			m[`fields`] = len(s.Fields)
+		}
+		add(&a.Objects, d.ID, raw.declName(d), m)
+	}
+
+	for _, d := range raw.InterfaceDecls {
+		m := measures{}
+		if it, ok := at(raw.InterfaceDescs, d.Interface); ok {
+			m[`abstracts`] = len(it.Abstracts)
+		}
+		add(&a.Interfaces, d.ID, raw.declName(d), m)
+	}
+
+	for _, d := range raw.Methods {
+		add(&a.Methods, d.ID, raw.methodName(d), raw.metrics(d.Metrics))
+	}
+
+	for _, d := range raw.Values {
+		add(&a.Values, d.ID, raw.declName(d), raw.metrics(d.Metrics))
+	}
+	return a
+}
+
+// at gets the construct at the given one-based index.
+func at[T any](list []T, index int) (T, bool) {
+	if index <= 0 || index > len(list) {
+		var zero T
+		return zero, false
+	}
+	return list[index-1], true
+}
+
+func (raw *rawProject) packagePath(index int) string {
+	if p, ok := at(raw.Packages, index); ok {
+		return p.Path
+	}
+	return ``
+}
+
+func (raw *rawProject) declName(d rawDecl) string {
+	prefix := raw.packagePath(d.Package) + `.`
+	// The nest is a short reference to a method, e.g. `method3`.
+	if index, err := strconv.Atoi(strings.TrimPrefix(d.Nest, `method`)); err == nil {
+		if m, ok := at(raw.Methods, index); ok {
+			prefix = raw.methodName(m) + `:`
+		}
+	}
+	return prefix + d.Name
+}
+
+func (raw *rawProject) methodName(d rawDecl) string {
+	name := raw.packagePath(d.Package) + `.`
+	if recv, ok := at(raw.Objects, d.Receiver); ok {
+		name += recv.Name + `.`
+	}
+	return name + d.Name
+}
+
+func (raw *rawProject) metrics(index int) measures {
+	mt, ok := at(raw.Metrics, index)
+	if !ok {
+		return measures{}
+	}
+	return measures{
+		`complexity`: mt.Complexity,
+		`lineCount`:  mt.LineCount,
+		`codeCount`:  mt.CodeCount,
+		`reads`:      len(mt.Reads),
+		`writes`:     len(mt.Writes),
+		`invokes`:    len(mt.Invokes),
+	}
+}
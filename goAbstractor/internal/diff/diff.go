@@ -0,0 +1,233 @@
+package diff
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+)
+
+// Status is how a construct changed between two abstractions.
+type Status string
+
+const (
+	Added   Status = `added`
+	Removed Status = `removed`
+	Changed Status = `changed`
+)
+
+// Report is the differences between an old and new abstraction of a project.
+type Report struct {
+	Packages   []*Change
+	Objects    []*Change
+	Interfaces []*Change
+	Methods    []*Change
+	Values     []*Change
+}
+
+// Change is a construct that was added, removed, or changed.
+type Change struct {
+	Status Status
+
+	// Name is the qualified name of the construct, e.g. `example.com/foo`
+	// for a package, `example.com/foo.Bar` for an object, and
+	// `example.com/foo.Bar.Baz` for a method with a receiver.
+	// A changed construct matched by its stable identifier has the new name.
+	Name string
+
+	// Deltas are the measures of the construct that are different.
+	// For an added or removed construct, these are all the measures
+	// that are not zero, with zero as the old or new value respectively.
+	Deltas []*Delta
+}
+
+// Delta is the change in one measure of a construct,
+// e.g. the complexity of a method or the number of fields in an object.
+type Delta struct {
+	Measure string
+	Old     int
+	New     int
+}
+
+// New creates the report of the differences between the given abstractions.
+func New(before, after *Abstraction) *Report {
+	return &Report{
+		Packages:   compare(before.Packages, after.Packages),
+		Objects:    compare(before.Objects, after.Objects),
+		Interfaces: compare(before.Interfaces, after.Interfaces),
+		Methods:    compare(before.Methods, after.Methods),
+		Values:     compare(before.Values, after.Values),
+	}
+}
+
+// compare gets the changes between the given constructs. The constructs
+// are matched by their stable identifiers, when both have one, so that
+// a renamed or moved construct is still matched. Any construct that isn't
+// matched by an identifier is matched by its qualified name.
+func compare(before, after []*entry) []*Change {
+	oldByID := map[string]*entry{}
+	for _, e := range before {
+		if len(e.id) > 0 {
+			oldByID[e.id] = e
+		}
+	}
+
+	matched := map[*entry]bool{}
+	changes := []*Change{}
+	for _, e := range after {
+		if old, has := oldByID[e.id]; has && len(e.id) > 0 {
+			matched[old], matched[e] = true, true
+			changes = appendChange(changes, e.name, old.measures, e.measures, true, true)
+		}
+	}
+
+	oldByName := byName(before, matched)
+	newByName := byName(after, matched)
+	names := slices.Collect(maps.Keys(oldByName))
+	for name := range newByName {
+		if _, has := oldByName[name]; !has {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		oldM, inOld := oldByName[name]
+		newM, inNew := newByName[name]
+		changes = appendChange(changes, name, oldM, newM, inOld, inNew)
+	}
+
+	slices.SortStableFunc(changes, func(a, b *Change) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return changes
+}
+
+// byName gets the measures of the constructs that haven't been matched
+// keyed by qualified name. If the name is already used, such as for
+// multiple `init` functions, the name is numbered.
+func byName(entries []*entry, matched map[*entry]bool) map[string]measures {
+	result := map[string]measures{}
+	for _, e := range entries {
+		if matched[e] {
+			continue
+		}
+		key := e.name
+		for i := 2; ; i++ {
+			if _, has := result[key]; !has {
+				break
+			}
+			key = e.name + `#` + strconv.Itoa(i)
+		}
+		result[key] = e.measures
+	}
+	return result
+}
+
+// appendChange appends the change to the construct with the given name,
+// unless the construct is in both and none of its measures changed.
+func appendChange(changes []*Change, name string, oldM, newM measures, inOld, inNew bool) []*Change {
+	status := Changed
+	switch {
+	case !inOld:
+		status = Added
+	case !inNew:
+		status = Removed
+	}
+
+	var deltas []*Delta
+	for _, measure := range measureNames(oldM, newM) {
+		if o, n := oldM[measure], newM[measure]; o != n {
+			deltas = append(deltas, &Delta{Measure: measure, Old: o, New: n})
+		}
+	}
+	if status != Changed || len(deltas) > 0 {
+		changes = append(changes, &Change{Status: status, Name: name, Deltas: deltas})
+	}
+	return changes
+}
+
+func measureNames(a, b measures) []string {
+	names := slices.Collect(maps.Keys(a))
+	for name := range b {
+		if _, has := a[name]; !has {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Empty indicates there are no differences.
+func (r *Report) Empty() bool {
+	return len(r.Packages) <= 0 && len(r.Objects) <= 0 &&
+		len(r.Interfaces) <= 0 && len(r.Methods) <= 0 && len(r.Values) <= 0
+}
+
+// Summary gets a human readable summary of the differences.
+func (r *Report) Summary() string {
+	buf := &strings.Builder{}
+	if r.Empty() {
+		buf.WriteString("no differences\n")
+		return buf.String()
+	}
+	writeSummary(buf, `packages`, r.Packages)
+	writeSummary(buf, `objects`, r.Objects)
+	writeSummary(buf, `interfaces`, r.Interfaces)
+	writeSummary(buf, `methods`, r.Methods)
+	writeSummary(buf, `values`, r.Values)
+	return buf.String()
+}
+
+func writeSummary(buf *strings.Builder, title string, changes []*Change) {
+	if len(changes) <= 0 {
+		return
+	}
+	counts := map[Status]int{}
+	for _, c := range changes {
+		counts[c.Status]++
+	}
+	fmt.Fprintf(buf, "%s: %d added, %d removed, %d changed\n",
+		title, counts[Added], counts[Removed], counts[Changed])
+	for _, c := range changes {
+		symbol := `~`
+		switch c.Status {
+		case Added:
+			symbol = `+`
+		case Removed:
+			symbol = `-`
+		}
+		fmt.Fprintf(buf, "  %s %s\n", symbol, c.Name)
+		for _, d := range c.Deltas {
+			fmt.Fprintf(buf, "      %s: %d -> %d (%+d)\n", d.Measure, d.Old, d.New, d.New-d.Old)
+		}
+	}
+}
+
+func (r *Report) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		AddNonZero(ctx, `packages`, r.Packages).
+		AddNonZero(ctx, `objects`, r.Objects).
+		AddNonZero(ctx, `interfaces`, r.Interfaces).
+		AddNonZero(ctx, `methods`, r.Methods).
+		AddNonZero(ctx, `values`, r.Values)
+}
+
+func (c *Change) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	deltas := jsonify.NewMap()
+	for _, d := range c.Deltas {
+		deltas.Add(ctx, d.Measure, d)
+	}
+	return jsonify.NewMap().
+		Add(ctx, `status`, string(c.Status)).
+		Add(ctx, `name`, c.Name).
+		AddNonZero(ctx, `deltas`, deltas)
+}
+
+func (d *Delta) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `old`, d.Old).
+		Add(ctx, `new`, d.New).
+		Add(ctx, `delta`, d.New-d.Old)
+}
@@ -0,0 +1,125 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+)
+
+func Test_Diff(t *testing.T) {
+	before, err := Parse([]byte(`{
+		"packages": [
+			{ "path": "cats", "objects": [1, 2], "methods": [1, 2, 3] }
+		],
+		"objects": [
+			{ "name": "Cat", "package": 1, "data": 1, "methods": [1] },
+			{ "name": "Dog", "package": 1 }
+		],
+		"methods": [
+			{ "name": "Meow", "package": 1, "receiver": 1, "metrics": 1 },
+			{ "name": "init", "package": 1, "metrics": 2 },
+			{ "name": "init", "package": 1, "metrics": 2 }
+		],
+		"metrics": [
+			{ "complexity": 2, "lineCount": 5, "reads": ["basic1"] },
+			{ "complexity": 1, "lineCount": 3 }
+		],
+		"structDescs": [
+			{ "fields": [1] }
+		]
+	}`))
+	check.NoError(t).Require(err)
+
+	after, err := Parse([]byte(`{
+		"packages": [
+			{ "path": "cats", "objects": [1], "methods": [1, 2, 3, 4] }
+		],
+		"objects": [
+			{ "name": "Cat", "package": 1, "data": 1, "methods": [1, 4] }
+		],
+		"methods": [
+			{ "name": "Meow", "package": 1, "receiver": 1, "metrics": 1 },
+			{ "name": "init", "package": 1, "metrics": 2 },
+			{ "name": "init", "package": 1, "metrics": 2 },
+			{ "name": "Purr", "package": 1, "receiver": 1, "metrics": 2 }
+		],
+		"metrics": [
+			{ "complexity": 4, "lineCount": 5, "reads": ["basic1"], "invokes": ["method2"] },
+			{ "complexity": 1, "lineCount": 3 }
+		],
+		"structDescs": [
+			{ "fields": [1, 2] }
+		]
+	}`))
+	check.NoError(t).Require(err)
+
+	report := New(before, after)
+	check.Equal(t, "packages: 0 added, 0 removed, 1 changed\n"+
+		"  ~ cats\n"+
+		"      methods: 3 -> 4 (+1)\n"+
+		"      objects: 2 -> 1 (-1)\n"+
+		"objects: 0 added, 1 removed, 1 changed\n"+
+		"  ~ cats.Cat\n"+
+		"      fields: 1 -> 2 (+1)\n"+
+		"      methods: 1 -> 2 (+1)\n"+
+		"  - cats.Dog\n"+
+		"methods: 1 added, 0 removed, 1 changed\n"+
+		"  ~ cats.Cat.Meow\n"+
+		"      complexity: 2 -> 4 (+2)\n"+
+		"      invokes: 0 -> 1 (+1)\n"+
+		"  + cats.Cat.Purr\n"+
+		"      complexity: 0 -> 1 (+1)\n"+
+		"      lineCount: 0 -> 3 (+3)\n").
+		Assert(report.Summary())
+
+	check.Equal(t, "no differences\n").
+		Assert(New(before, before).Summary())
+}
+
+func Test_Diff_StableIDs(t *testing.T) {
+	before, err := Parse([]byte(`{
+		"packages": [
+			{ "id": "p:cats", "path": "cats", "methods": [1, 2, 3] }
+		],
+		"methods": [
+			{ "id": "m:cats.Meow", "name": "Meow", "package": 1, "metrics": 1 },
+			{ "id": "m:cats.init#1", "name": "init", "package": 1, "metrics": 1 },
+			{ "id": "m:cats.init#2", "name": "init", "package": 1, "metrics": 2 }
+		],
+		"metrics": [
+			{ "complexity": 1, "lineCount": 3 },
+			{ "complexity": 2, "lineCount": 6 }
+		]
+	}`))
+	check.NoError(t).Require(err)
+
+	// Meow is renamed without changing its identifier, the first init is
+	// removed, and Purr is added without an identifier so is matched by name.
+	after, err := Parse([]byte(`{
+		"packages": [
+			{ "id": "p:cats", "path": "cats", "methods": [1, 2, 3] }
+		],
+		"methods": [
+			{ "id": "m:cats.Meow", "name": "Mew", "package": 1, "metrics": 2 },
+			{ "id": "m:cats.init#2", "name": "init", "package": 1, "metrics": 2 },
+			{ "name": "Purr", "package": 1, "metrics": 1 }
+		],
+		"metrics": [
+			{ "complexity": 1, "lineCount": 3 },
+			{ "complexity": 2, "lineCount": 6 }
+		]
+	}`))
+	check.NoError(t).Require(err)
+
+	check.Equal(t, "methods: 1 added, 1 removed, 1 changed\n"+
+		"  ~ cats.Mew\n"+
+		"      complexity: 1 -> 2 (+1)\n"+
+		"      lineCount: 3 -> 6 (+3)\n"+
+		"  + cats.Purr\n"+
+		"      complexity: 0 -> 1 (+1)\n"+
+		"      lineCount: 0 -> 3 (+3)\n"+
+		"  - cats.init\n"+
+		"      complexity: 1 -> 0 (-1)\n"+
+		"      lineCount: 3 -> 0 (-3)\n").
+		Assert(New(before, after).Summary())
+}
@@ -0,0 +1,102 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// git runs the local git binary in the given repository
+// and returns the standard output.
+func git(ctx context.Context, repo string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, `git`, append([]string{`-C`, repo}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return ``, fmt.Errorf(`git %s: %s`, args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return ``, fmt.Errorf(`git %s: %w`, args[0], err)
+	}
+	return string(out), nil
+}
+
+// firstParentCommits gets the first-parent commits reachable from the
+// given reference, from oldest to newest.
+func firstParentCommits(ctx context.Context, repo, ref string) ([]*Snapshot, error) {
+	out, err := git(ctx, repo, `log`, `--first-parent`, `--reverse`, `--format=%H%x09%cI`, ref, `--`)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := []*Snapshot{}
+	for line := range strings.Lines(out) {
+		parts := strings.Split(strings.TrimSpace(line), "\t")
+		if len(parts) != 2 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, parts[1])
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, &Snapshot{Commit: parts[0], Time: t})
+	}
+	return snapshots, nil
+}
+
+// taggedCommits gets the commits that are tagged,
+// from the oldest to newest commit.
+func taggedCommits(ctx context.Context, repo string) ([]*Snapshot, error) {
+	// The `*objectname` is the commit of an annotated tag
+	// and is empty for a lightweight tag.
+	out, err := git(ctx, repo, `for-each-ref`,
+		`--format=%(refname:short)%09%(objectname)%09%(*objectname)`, `refs/tags`)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := []*Snapshot{}
+	for line := range strings.Lines(out) {
+		parts := strings.Split(strings.TrimRight(line, "\r\n"), "\t")
+		if len(parts) != 3 {
+			continue
+		}
+		commit := parts[1]
+		if len(parts[2]) > 0 {
+			commit = parts[2]
+		}
+		date, err := git(ctx, repo, `show`, `-s`, `--format=%cI`, commit)
+		if err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(date))
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, &Snapshot{Commit: commit, Tag: parts[0], Time: t})
+	}
+	slices.SortStableFunc(snapshots, func(a, b *Snapshot) int {
+		return a.Time.Compare(b.Time)
+	})
+	return snapshots, nil
+}
+
+// addWorktree checks out the given commit into a new temporary worktree.
+// The returned function removes the worktree.
+func addWorktree(ctx context.Context, repo, commit string) (string, func(), error) {
+	dir, err := os.MkdirTemp(``, `goAbstractor-history-`)
+	if err != nil {
+		return ``, nil, err
+	}
+	if _, err = git(ctx, repo, `worktree`, `add`, `--detach`, `--force`, dir, commit); err != nil {
+		_ = os.RemoveAll(dir)
+		return ``, nil, err
+	}
+	remove := func() {
+		// Use a new context so the worktree is removed even when cancelled.
+		_, _ = git(context.Background(), repo, `worktree`, `remove`, `--force`, dir)
+		_ = os.RemoveAll(dir)
+	}
+	return dir, remove, nil
+}
@@ -0,0 +1,308 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/abstraction"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstracted"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+)
+
+// Config is the configuration for mining the history of a git repository.
+type Config struct {
+	// Repo is the path to the local git repository.
+	Repo string
+
+	// Dir is the optional path, relative to the repository's root,
+	// of the directory to abstract in each snapshot.
+	Dir string
+
+	// Ref is the reference to walk the first-parent commits back from.
+	// If empty, `HEAD` is used. This is ignored when Tags is set.
+	Ref string
+
+	// Tags indicates that only the tagged commits should be abstracted
+	// instead of the first-parent commits.
+	Tags bool
+
+	// Every is to only abstract every Nth commit, always including
+	// the newest commit. Zero or one will abstract every commit.
+	Every int
+
+	// Max is the maximum number of the newest snapshots to abstract.
+	// Zero will abstract all the snapshots.
+	Max int
+
+	// AllPackages indicates that the declarations in all the abstracted
+	// packages, including the dependencies such as the standard library,
+	// should be collected. By default only the declarations in the read
+	// packages, the entry points, are collected.
+	AllPackages bool
+
+	// Abstraction is the configuration to abstract each snapshot with.
+	// The directory is replaced with the directory of the snapshot.
+	Abstraction abstraction.Config
+
+	// Progress is the optional callback called before each snapshot
+	// is abstracted with the one-based number of the snapshot.
+	Progress func(number, total int, s *Snapshot)
+}
+
+// Series is the time series of the metrics of the declarations and
+// packages across the snapshots of a repository's history.
+type Series struct {
+	Snapshots    []*Snapshot
+	Declarations []*Declaration
+	Packages     []*Package
+}
+
+// Snapshot is a commit that was abstracted.
+type Snapshot struct {
+	Commit string
+	Tag    string
+	Time   time.Time
+
+	// Err is the error if the snapshot could not be abstracted.
+	// A failed snapshot has no points in the series.
+	Err error
+}
+
+// Declaration is the series of metrics for a declaration, keyed by the
+// declaration's kind, package path, and name qualified by its receiver or
+// the function it is nested in.
+//
+// The key only depends on what the declaration is named, not where it is
+// declared, so the declaration is followed across snapshots even as it,
+// or the code around it, is changed or moved. The declarations with the
+// same key in a snapshot, e.g. the init functions of a package or a
+// function declared once for each build configuration, are summed into
+// one point.
+type Declaration struct {
+	Key     string
+	Kind    string
+	Package string
+	Name    string
+	Points  []*Point
+}
+
+// Package is the series of metrics for a package.
+// The metrics are the sums of the metrics of the package's declarations.
+type Package struct {
+	Path   string
+	Points []*Point
+}
+
+// Point is the metrics of a declaration or package in one snapshot.
+type Point struct {
+	// Snapshot is the one-based index of the snapshot.
+	Snapshot int
+
+	// Declarations is the number of declarations in a package.
+	// This is zero for a declaration.
+	Declarations int
+
+	Complexity int
+	LineCount  int
+	CodeCount  int
+}
+
+// Mine abstracts the snapshots of the repository's history, from oldest
+// to newest, and collects the metrics of each snapshot into a series.
+// A snapshot that fails to abstract is recorded and skipped, but a failure
+// to read the history or a cancelled context stops the mining.
+func Mine(ctx context.Context, cfg Config) (*Series, error) {
+	snapshots, err := listSnapshots(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Series{Snapshots: snapshots}
+	decls := map[string]*Declaration{}
+	pkgs := map[string]*Package{}
+	for i, snap := range snapshots {
+		if cfg.Progress != nil {
+			cfg.Progress(i+1, len(snapshots), snap)
+		}
+		proj, err := abstractSnapshot(ctx, cfg, snap)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			snap.Err = err
+			continue
+		}
+		addPoints(abstracted.Constructs(proj), i+1, cfg.AllPackages, decls, pkgs)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(decls)) {
+		s.Declarations = append(s.Declarations, decls[key])
+	}
+	for _, path := range slices.Sorted(maps.Keys(pkgs)) {
+		s.Packages = append(s.Packages, pkgs[path])
+	}
+	return s, nil
+}
+
+func listSnapshots(ctx context.Context, cfg Config) ([]*Snapshot, error) {
+	var (
+		snapshots []*Snapshot
+		err       error
+	)
+	if cfg.Tags {
+		snapshots, err = taggedCommits(ctx, cfg.Repo)
+	} else {
+		ref := cfg.Ref
+		if len(ref) <= 0 {
+			ref = `HEAD`
+		}
+		snapshots, err = firstParentCommits(ctx, cfg.Repo, ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Every > 1 {
+		// Count back from the newest so that the newest is always included.
+		every := []*Snapshot{}
+		for i := len(snapshots) - 1; i >= 0; i -= cfg.Every {
+			every = append(every, snapshots[i])
+		}
+		slices.Reverse(every)
+		snapshots = every
+	}
+	if cfg.Max > 0 && len(snapshots) > cfg.Max {
+		snapshots = snapshots[len(snapshots)-cfg.Max:]
+	}
+	if len(snapshots) <= 0 {
+		return nil, errors.New(`no commits found to abstract`)
+	}
+	return snapshots, nil
+}
+
+func abstractSnapshot(ctx context.Context, cfg Config, snap *Snapshot) (*abstraction.Project, error) {
+	dir, remove, err := addWorktree(ctx, cfg.Repo, snap.Commit)
+	if err != nil {
+		return nil, err
+	}
+	defer remove()
+
+	ac := cfg.Abstraction
+	ac.Dir = filepath.Join(dir, cfg.Dir)
+	return abstraction.Abstract(ctx, ac)
+}
+
+func addPoints(proj constructs.Project, snapshot int, allPackages bool, decls map[string]*Declaration, pkgs map[string]*Package) {
+	add := func(decl constructs.Declaration, metrics constructs.Metrics) {
+		if decl.Duplicate() || (!allPackages && !decl.Package().EntryPoint()) {
+			return
+		}
+
+		p := &Point{Snapshot: snapshot}
+		if !utils.IsNil(metrics) {
+			p.Complexity = metrics.Complexity()
+			p.LineCount = metrics.LineCount()
+			p.CodeCount = metrics.CodeCount()
+		}
+
+		path := decl.Package().Path()
+		name := constructs.LocalName(decl)
+		key := string(decl.Kind()) + `:` + path + `.` + name
+		d, has := decls[key]
+		if !has {
+			d = &Declaration{
+				Key:     key,
+				Kind:    string(decl.Kind()),
+				Package: path,
+				Name:    name,
+			}
+			decls[key] = d
+		}
+		if last := len(d.Points) - 1; last < 0 || d.Points[last].Snapshot != snapshot {
+			d.Points = append(d.Points, &Point{Snapshot: snapshot})
+		}
+		d.Points[len(d.Points)-1].add(p)
+
+		pkg, has := pkgs[path]
+		if !has {
+			pkg = &Package{Path: path}
+			pkgs[path] = pkg
+		}
+		if last := len(pkg.Points) - 1; last < 0 || pkg.Points[last].Snapshot != snapshot {
+			pkg.Points = append(pkg.Points, &Point{Snapshot: snapshot})
+		}
+		pp := pkg.Points[len(pkg.Points)-1]
+		pp.Declarations++
+		pp.add(p)
+	}
+
+	for it := range proj.InterfaceDecls().Enumerate().Seq() {
+		add(it, nil)
+	}
+	for obj := range proj.Objects().Enumerate().Seq() {
+		add(obj, nil)
+	}
+	for m := range proj.Methods().Enumerate().Seq() {
+		add(m, m.Metrics())
+	}
+	for v := range proj.Values().Enumerate().Seq() {
+		add(v, v.Metrics())
+	}
+}
+
+// add adds the metrics of the other point to this point.
+func (p *Point) add(other *Point) {
+	p.Complexity += other.Complexity
+	p.LineCount += other.LineCount
+	p.CodeCount += other.CodeCount
+}
+
+func (s *Series) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `snapshots`, s.Snapshots).
+		AddNonZero(ctx, `declarations`, s.Declarations).
+		AddNonZero(ctx, `packages`, s.Packages)
+}
+
+func (s *Snapshot) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	m := jsonify.NewMap().
+		Add(ctx, `commit`, s.Commit).
+		AddNonZero(ctx, `tag`, s.Tag).
+		Add(ctx, `time`, s.Time.Format(time.RFC3339))
+	if s.Err != nil {
+		m.Add(ctx, `error`, strings.TrimSpace(s.Err.Error()))
+	}
+	return m
+}
+
+func (d *Declaration) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `key`, d.Key).
+		Add(ctx, `kind`, d.Kind).
+		Add(ctx, `package`, d.Package).
+		Add(ctx, `name`, d.Name).
+		Add(ctx, `points`, d.Points)
+}
+
+func (p *Package) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `path`, p.Path).
+		Add(ctx, `points`, p.Points)
+}
+
+func (p *Point) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `snapshot`, p.Snapshot).
+		AddNonZero(ctx, `declarations`, p.Declarations).
+		AddNonZero(ctx, `complexity`, p.Complexity).
+		AddNonZero(ctx, `lineCount`, p.LineCount).
+		AddNonZero(ctx, `codeCount`, p.CodeCount)
+}
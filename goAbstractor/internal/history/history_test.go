@@ -0,0 +1,234 @@
+package history
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/abstraction"
+)
+
+func Test_History(t *testing.T) {
+	ctx := context.Background()
+	repo := newRepo(t)
+
+	// The first-parent commits skip the commit on the merged branch.
+	snaps, err := firstParentCommits(ctx, repo.dir, `HEAD`)
+	check.NoError(t).Require(err)
+	check.Equal(t, repo.commits(`c1`, `c2`, `c3`, `merge`)).Assert(commits(snaps))
+
+	tags, err := taggedCommits(ctx, repo.dir)
+	check.NoError(t).Require(err)
+	check.Equal(t, repo.commits(`c2`, `c3`)).Assert(commits(tags))
+	check.Equal(t, `v0.1.0`).Assert(tags[0].Tag)
+	check.Equal(t, `v0.2.0`).Assert(tags[1].Tag)
+
+	list := func(cfg Config) []string {
+		cfg.Repo = repo.dir
+		cfg.Abstraction = testConfig
+		snaps, err := listSnapshots(ctx, cfg)
+		check.NoError(t).Require(err)
+		return commits(snaps)
+	}
+	check.Equal(t, repo.commits(`c2`, `merge`)).Assert(list(Config{Every: 2}))
+	check.Equal(t, repo.commits(`c1`, `merge`)).Assert(list(Config{Every: 3}))
+	check.Equal(t, repo.commits(`c3`, `merge`)).Assert(list(Config{Max: 2}))
+	check.Equal(t, repo.commits(`merge`)).Assert(list(Config{Every: 2, Max: 1}))
+	check.Equal(t, repo.commits(`c1`, `c2`, `c3`)).Assert(list(Config{Ref: `HEAD~1`}))
+	check.Equal(t, repo.commits(`c3`)).Assert(list(Config{Tags: true, Max: 1}))
+
+	s, err := Mine(ctx, Config{Repo: repo.dir, Max: 3, Abstraction: testConfig})
+	check.NoError(t).Require(err)
+	check.Equal(t, repo.commits(`c2`, `c3`, `merge`)).Assert(commits(s.Snapshots))
+	// The init functions are followed even though they are renumbered when
+	// the merge adds another before it, and the two are summed in the merge.
+	check.Equal(t, []string{
+		`method:Bar [1:cx2,ln6] [2:cx2,ln6] [3:cx2,ln6]`,
+		`method:Baz [3:cx1,ln1]`,
+		`method:Foo [1:cx1,ln1] [2:cx1,ln1] [3:cx1,ln1]`,
+		`method:init [1:cx1,ln1] [2:cx1,ln1] [3:cx2,ln2]`,
+		`package:test0033 [1:d3,cx4,ln8] [2:d3,cx4,ln8] [3:d5,cx6,ln10]`,
+	}).Assert(series(s))
+	check.Equal(t, `method:test0033.init`).Assert(s.Declarations[3].Key)
+}
+
+func Test_History_AllPackages(t *testing.T) {
+	ctx := context.Background()
+	repo := initRepo(t)
+	repo.copy(`test0034`)
+	repo.commit(`c1`, 1)
+
+	// Only the root package is read, so by default the package it
+	// depends on isn't collected unless all the packages are.
+	cfg := Config{
+		Repo: repo.dir,
+		Abstraction: abstraction.Config{
+			Patterns:   []string{`.`},
+			BuildFlags: []string{`-tags=test`},
+		},
+	}
+	s, err := Mine(ctx, cfg)
+	check.NoError(t).Require(err)
+	check.Equal(t, []string{
+		`method:Foo [1:cx1,ln1]`,
+		`package:test0034 [1:d1,cx1,ln1]`,
+	}).Assert(series(s))
+
+	cfg.AllPackages = true
+	s, err = Mine(ctx, cfg)
+	check.NoError(t).Require(err)
+	check.Equal(t, []string{
+		`method:Bar [1:cx1,ln1]`,
+		`method:Foo [1:cx1,ln1]`,
+		`package:test0034 [1:d1,cx1,ln1]`,
+		`package:test0034/b [1:d1,cx1,ln1]`,
+	}).Assert(series(s))
+}
+
+const pathToTestData = `../../../testData/go/`
+
+// testConfig is the configuration to abstract the snapshots of the test data
+// with since the test data files are only built with the test tag.
+var testConfig = abstraction.Config{BuildFlags: []string{`-tags=test`}}
+
+type testRepo struct {
+	t    *testing.T
+	dir  string
+	revs map[string]string
+}
+
+// newRepo creates a repository from test0033 with three commits on the main
+// branch, the second with a lightweight tag and the third with an annotated
+// tag, followed by a merge of a branch with one commit. The second commit
+// and the branch each add an init function.
+func newRepo(t *testing.T) *testRepo {
+	r := initRepo(t)
+	r.copy(`test0033`)
+	r.commit(`c1`, 1)
+
+	r.copy(`test0033/_c2`)
+	r.commit(`c2`, 2)
+	r.git(`tag`, `v0.1.0`)
+
+	r.copy(`test0033/_c3`)
+	r.commit(`c3`, 3)
+	r.git(`tag`, `-a`, `v0.2.0`, `-m`, `second release`)
+
+	r.git(`checkout`, `-q`, `-b`, `side`)
+	r.copy(`test0033/_side`)
+	r.commit(`side`, 4)
+	r.git(`checkout`, `-q`, `main`)
+	r.run(5, `merge`, `-q`, `--no-ff`, `-m`, `merge`, `side`)
+	r.revs[`merge`] = r.git(`rev-parse`, `HEAD`)
+	return r
+}
+
+// initRepo creates an empty repository with a main branch.
+func initRepo(t *testing.T) *testRepo {
+	r := &testRepo{t: t, dir: t.TempDir(), revs: map[string]string{}}
+	r.git(`init`, `-q`, `-b`, `main`)
+	return r
+}
+
+// copy writes the files of the given test data directory into the
+// repository, replacing any existing files, except for the directories
+// starting with an underscore which hold the files of later commits.
+func (r *testRepo) copy(dir string) {
+	src := filepath.Join(pathToTestData, dir)
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(r.dir, rel)
+		if d.IsDir() {
+			if path != src && strings.HasPrefix(d.Name(), `_`) {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(dst, 0o755)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(dst, data, 0o644)
+	})
+	check.NoError(r.t).Require(err)
+}
+
+func (r *testRepo) commit(name string, day int) {
+	r.git(`add`, `-A`)
+	r.run(day, `commit`, `-q`, `-m`, name)
+	r.revs[name] = r.git(`rev-parse`, `HEAD`)
+}
+
+// run runs git with the commit dates set to the given day
+// so that the commits are in a known order.
+func (r *testRepo) run(day int, args ...string) string {
+	date := `2024-01-0` + string(rune('0'+day)) + `T12:00:00Z`
+	return r.gitEnv([]string{`GIT_AUTHOR_DATE=` + date, `GIT_COMMITTER_DATE=` + date}, args...)
+}
+
+func (r *testRepo) git(args ...string) string {
+	return r.gitEnv(nil, args...)
+}
+
+func (r *testRepo) gitEnv(env []string, args ...string) string {
+	args = append([]string{`-C`, r.dir, `-c`, `user.name=test`, `-c`, `user.email=test@example.com`}, args...)
+	cmd := exec.Command(`git`, args...)
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.CombinedOutput()
+	check.NoError(r.t).With(`output`, string(out)).Require(err)
+	return strings.TrimSpace(string(out))
+}
+
+func (r *testRepo) commits(names ...string) []string {
+	revs := make([]string, len(names))
+	for i, name := range names {
+		revs[i] = r.revs[name]
+	}
+	return revs
+}
+
+func commits(snaps []*Snapshot) []string {
+	revs := make([]string, len(snaps))
+	for i, snap := range snaps {
+		revs[i] = snap.Commit
+	}
+	return revs
+}
+
+func series(s *Series) []string {
+	lines := []string{}
+	for _, d := range s.Declarations {
+		lines = append(lines, d.Kind+`:`+d.Name+points(d.Points, false))
+	}
+	for _, p := range s.Packages {
+		lines = append(lines, `package:`+p.Path+points(p.Points, true))
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+func points(ps []*Point, withDecls bool) string {
+	s := ``
+	for _, p := range ps {
+		s += ` [` + strconv.Itoa(p.Snapshot) + `:`
+		if withDecls {
+			s += `d` + strconv.Itoa(p.Declarations) + `,`
+		}
+		s += `cx` + strconv.Itoa(p.Complexity) + `,ln` + strconv.Itoa(p.LineCount) + `]`
+	}
+	return s
+}
@@ -10,6 +10,16 @@ const (
 	keyOnlyIndex
 	keyKeepDuplicates
 	keySkipDead
+	keyIncludeTestMetrics
+	keyExcludeGeneratedMetrics
+	keyAliveReasons
+	keyDeadReport
+	keyCKMetrics
+	keyPackageMetrics
+	keyExcludeStdlib
+	keyCycles
+	keyStableIDs
+	keyRanges
 	keyDebugAlive
 	keyDebugKind
 	keyDebugIndex
@@ -100,6 +110,134 @@ func (c *Context) SkipDead() bool {
 	return c.state[keySkipDead]
 }
 
+// SetIncludeTestMetrics sets the include test metrics flag.
+func (c *Context) SetIncludeTestMetrics(include bool) *Context {
+	return c.copyAndSet(keyIncludeTestMetrics, include)
+}
+
+// IncludeTestMetrics indicates that the measurements, such as complexity
+// and line counts, of test code should be outputted. By default only the
+// usages of test code are outputted so that test code is kept out of the
+// production metrics, including the CK metrics and package metrics,
+// but can still be linked to the production code.
+func (c *Context) IncludeTestMetrics() bool {
+	return c.state[keyIncludeTestMetrics]
+}
+
+// SetExcludeGeneratedMetrics sets the exclude generated metrics flag.
+func (c *Context) SetExcludeGeneratedMetrics(exclude bool) *Context {
+	return c.copyAndSet(keyExcludeGeneratedMetrics, exclude)
+}
+
+// ExcludeGeneratedMetrics indicates that the measurements, such as
+// complexity and line counts, of generated code should not be outputted.
+// Generated code isn't counted by the CK metrics and package metrics either.
+// The usages of generated code are still outputted so that generated
+// code is still part of the type graph.
+func (c *Context) ExcludeGeneratedMetrics() bool {
+	return c.state[keyExcludeGeneratedMetrics]
+}
+
+// SetIncludeAliveReasons sets the include alive reasons flag.
+func (c *Context) SetIncludeAliveReasons(include bool) *Context {
+	return c.copyAndSet(keyAliveReasons, include)
+}
+
+// IncludeAliveReasons indicates that the reason each declaration was kept
+// alive by the dead-code elimination should be outputted, either the reason
+// the declaration is a root or the construct that used the declaration.
+func (c *Context) IncludeAliveReasons() bool {
+	return c.state[keyAliveReasons]
+}
+
+// SetIncludeDeadReport sets the include dead report flag.
+func (c *Context) SetIncludeDeadReport(include bool) *Context {
+	return c.copyAndSet(keyDeadReport, include)
+}
+
+// IncludeDeadReport indicates that the project should output a report
+// of the dead declarations, methods, and values, and the unused fields,
+// with their locations and size metrics grouped by package.
+func (c *Context) IncludeDeadReport() bool {
+	return c.state[keyDeadReport]
+}
+
+// SetIncludeCKMetrics sets the include CK metrics flag.
+func (c *Context) SetIncludeCKMetrics(include bool) *Context {
+	return c.copyAndSet(keyCKMetrics, include)
+}
+
+// IncludeCKMetrics indicates that the project should output the
+// Chidamber and Kemerer object-oriented metrics, such as WMC, CBO,
+// and LCOM, for each object and object instance in the read packages.
+func (c *Context) IncludeCKMetrics() bool {
+	return c.state[keyCKMetrics]
+}
+
+// SetIncludePackageMetrics sets the include package metrics flag.
+func (c *Context) SetIncludePackageMetrics(include bool) *Context {
+	return c.copyAndSet(keyPackageMetrics, include)
+}
+
+// IncludePackageMetrics indicates that the project should output the
+// coupling and Martin metrics, such as instability and abstractness,
+// for each read package.
+func (c *Context) IncludePackageMetrics() bool {
+	return c.state[keyPackageMetrics]
+}
+
+// SetExcludeStdlib sets the exclude standard library flag.
+func (c *Context) SetExcludeStdlib(exclude bool) *Context {
+	return c.copyAndSet(keyExcludeStdlib, exclude)
+}
+
+// ExcludeStdlib indicates that the standard library packages should not
+// be counted as packages that are used in the package metrics.
+func (c *Context) ExcludeStdlib() bool {
+	return c.state[keyExcludeStdlib]
+}
+
+// SetIncludeCycles sets the include cycles flag.
+func (c *Context) SetIncludeCycles(include bool) *Context {
+	return c.copyAndSet(keyCycles, include)
+}
+
+// IncludeCycles indicates that the project should output the dependency
+// cycles, the strongly connected components of declarations that depend
+// on each other via reads, writes, invocations, fields, and signatures.
+func (c *Context) IncludeCycles() bool {
+	return c.state[keyCycles]
+}
+
+// SetIncludeStableIDs sets the include stable identifiers flag.
+func (c *Context) SetIncludeStableIDs(include bool) *Context {
+	return c.copyAndSet(keyStableIDs, include)
+}
+
+// IncludeStableIDs indicates that each package, declaration, and type
+// description should output an identifier derived from its content,
+// such as the package path, nest, name, and type arguments, next to its
+// index. Unlike the index, the identifier will not change when unrelated
+// constructs are added or removed, so it may be used to join constructs
+// across abstractions of different versions of a project.
+// Since the basic types are otherwise outputted as only their names,
+// with this set they are outputted as objects with a name and identifier.
+func (c *Context) IncludeStableIDs() bool {
+	return c.state[keyStableIDs]
+}
+
+// SetIncludeRanges sets the include ranges flag.
+func (c *Context) SetIncludeRanges(include bool) *Context {
+	return c.copyAndSet(keyRanges, include)
+}
+
+// IncludeRanges indicates that the declarations, metrics, and fields
+// should output the start and end lines and columns of their locations
+// so that the whole of a method or struct can be highlighted.
+func (c *Context) IncludeRanges() bool {
+	return c.state[keyRanges]
+}
+
 // IncludeDebugAlive indicates that the alive flag should be included
 // to the output model for debugging.
 func (c *Context) IncludeDebugAlive(include bool) *Context {
@@ -140,5 +140,9 @@ func (l *List) RawValue() any {
 }
 
 func (l *List) MarshalJSON() ([]byte, error) {
+	if l.data == nil {
+		// Output an empty list instead of null.
+		return []byte(`[]`), nil
+	}
 	return json.Marshal(l.data)
 }
@@ -14,21 +14,25 @@ type Loc interface {
 
 	Flag()
 	Pos() token.Pos
+	End() token.Pos
 	Info() (offset int, file string, line int)
+	Position() token.Position
+	Range() Range
 	String() string
 }
 
 type locImp struct {
 	s Set
 	p token.Pos
+	e token.Pos
 }
 
-func newLoc(s Set, p token.Pos) Loc {
-	return &locImp{s: s, p: p}
+func newLoc(s Set, p, e token.Pos) Loc {
+	return &locImp{s: s, p: p, e: e}
 }
 
 func NoLoc() Loc {
-	return newLoc(nil, token.NoPos)
+	return newLoc(nil, token.NoPos, token.NoPos)
 }
 
 func (c *locImp) isLoc() {}
@@ -43,6 +47,12 @@ func (c *locImp) Pos() token.Pos {
 	return c.p
 }
 
+// End is the position just after the end of the location,
+// or NoPos if the location only has a start position.
+func (c *locImp) End() token.Pos {
+	return c.e
+}
+
 func (c *locImp) Info() (offset int, file string, line int) {
 	if utils.IsNil(c.s) {
 		return 0, ``, 0
@@ -50,6 +60,26 @@ func (c *locImp) Info() (offset int, file string, line int) {
 	return c.s.infoFor(c.p)
 }
 
+// Position gets the file, line, and column of the start of the location.
+// Unlike Info, this doesn't finish the set of locations so it may be used
+// before all the locations have been flagged.
+func (c *locImp) Position() token.Position {
+	if utils.IsNil(c.s) {
+		return token.Position{}
+	}
+	return c.s.positionFor(c.p)
+}
+
+// Range gets the lines and columns that the location spans.
+// If the location doesn't have an end position, the range is
+// only the start position.
+func (c *locImp) Range() Range {
+	if utils.IsNil(c.s) {
+		return Range{}
+	}
+	return c.s.rangeFor(c.p, c.e)
+}
+
 func (c *locImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
 	offset, file, line := c.Info()
 
@@ -0,0 +1,34 @@
+package locs
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+)
+
+func Test_Range(t *testing.T) {
+	fs := token.NewFileSet()
+	file, err := parser.ParseFile(fs, `/src/foo.go`, "package foo\n\nfunc bar() {\n\tprintln(`hello`)\n}\n", 0)
+	check.NoError(t).Assert(err)
+
+	s := NewSet(fs)
+	s.Alias(`/src/foo.go`, `foo.go`)
+	decl := file.Decls[0]
+	ranged := s.NewRange(decl.Pos(), decl.End())
+	ranged.Flag()
+	single := s.NewLoc(decl.Pos())
+
+	check.String(t, ranged.String()).Assert(`foo.go:3`)
+	check.Equal(t, ranged.Range()).Assert(Range{StartLine: 3, StartColumn: 1, EndLine: 5, EndColumn: 2})
+	check.Equal(t, single.Range()).Assert(Range{StartLine: 3, StartColumn: 1, EndLine: 3, EndColumn: 1})
+	check.True(t).Assert(RangeOf(nil).IsZero())
+	check.True(t).Assert(NoLoc().Range().IsZero())
+
+	b, err := jsonify.Marshal(jsonify.NewContext().SetMinimize(true), ranged.Range())
+	check.NoError(t).Assert(err)
+	check.String(t, string(b)).Assert(`{"endColumn":2,"endLine":5,"startColumn":1,"startLine":3}`)
+}
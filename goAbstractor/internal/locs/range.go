@@ -0,0 +1,46 @@
+package locs
+
+import (
+	"fmt"
+
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+)
+
+// Range is the lines and columns that a location spans in its file.
+// The lines and columns start at one and the end column is the column
+// just after the last character, as used by go/token.
+// A zero range indicates that the span of the location is unknown.
+type Range struct {
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+}
+
+// IsZero indicates that the span of the location is unknown.
+func (r Range) IsZero() bool {
+	return r == Range{}
+}
+
+func (r Range) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		AddNonZero(ctx, `startLine`, r.StartLine).
+		AddNonZero(ctx, `startColumn`, r.StartColumn).
+		AddNonZero(ctx, `endLine`, r.EndLine).
+		AddNonZero(ctx, `endColumn`, r.EndColumn)
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf(`%d:%d-%d:%d`, r.StartLine, r.StartColumn, r.EndLine, r.EndColumn)
+}
+
+// RangeOf gets the range of the given location
+// or a zero range if the location is nil.
+func RangeOf(loc Loc) Range {
+	if utils.IsNil(loc) {
+		return Range{}
+	}
+	return loc.Range()
+}
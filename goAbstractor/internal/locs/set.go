@@ -4,6 +4,7 @@ import (
 	"go/token"
 	"path/filepath"
 	"strconv"
+	"sync"
 
 	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
 	"github.com/Snow-Gremlin/goToolbox/utils"
@@ -15,13 +16,19 @@ type Set interface {
 	FileSet() *token.FileSet
 	Alias(file, alias string)
 	NewLoc(p token.Pos) Loc
+	NewRange(p, e token.Pos) Loc
 	Reset()
 
 	flag(p token.Pos)
 	infoFor(p token.Pos) (int, string, int)
+	positionFor(p token.Pos) token.Position
+	rangeFor(p, e token.Pos) Range
 }
 
+// setImp is the set of locations. The set may be used by concurrent
+// workers abstracting packages so the aliases and flags are locked.
 type setImp struct {
+	lock     sync.Mutex
 	fs       *token.FileSet
 	aliases  map[string]string
 	flagged  map[token.Pos]bool
@@ -43,19 +50,31 @@ func (s *setImp) FileSet() *token.FileSet {
 }
 
 func (s *setImp) Alias(file, alias string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	s.aliases[file] = filepath.ToSlash(alias)
 }
 
 func (s *setImp) NewLoc(p token.Pos) Loc {
-	return newLoc(s, p)
+	return newLoc(s, p, token.NoPos)
+}
+
+// NewRange creates a location from the start position to the
+// end position, which is the position just after the location.
+func (s *setImp) NewRange(p, e token.Pos) Loc {
+	return newLoc(s, p, e)
 }
 
 func (s *setImp) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	s.flagged = map[token.Pos]bool{}
 	s.finished = false
 }
 
 func (s *setImp) flag(p token.Pos) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	if s.finished {
 		panic(terror.New(`flagging a location must be after a reset ` +
 			`and prior to any location information looked up`))
@@ -93,6 +112,8 @@ func (s *setImp) finish() {
 }
 
 func (s *setImp) infoFor(p token.Pos) (offset int, file string, line int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	s.finish()
 	if p <= token.NoPos {
 		return 0, ``, 0
@@ -104,7 +125,40 @@ func (s *setImp) infoFor(p token.Pos) (offset int, file string, line int) {
 	return offset, file, line
 }
 
+func (s *setImp) positionFor(p token.Pos) token.Position {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if p <= token.NoPos {
+		return token.Position{}
+	}
+
+	pos := s.fs.Position(p)
+	pos.Filename = s.cleanPath(pos.Filename)
+	return pos
+}
+
+func (s *setImp) rangeFor(p, e token.Pos) Range {
+	if p <= token.NoPos {
+		return Range{}
+	}
+
+	start := s.fs.Position(p)
+	r := Range{
+		StartLine:   start.Line,
+		StartColumn: start.Column,
+		EndLine:     start.Line,
+		EndColumn:   start.Column,
+	}
+	if e > p {
+		end := s.fs.Position(e)
+		r.EndLine, r.EndColumn = end.Line, end.Column
+	}
+	return r
+}
+
 func (s *setImp) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 	s.finish()
 	m := jsonify.NewMap()
 	files := utils.SortedKeys(s.offsets)
@@ -1,11 +1,15 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"maps"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Snow-Gremlin/goToolbox/terrors/terror"
 	"github.com/Snow-Gremlin/goToolbox/utils"
@@ -14,7 +18,21 @@ import (
 const defaultIndentText = `┆  `
 const defaultShowGroups = false
 
+// AllGroups is the group name that can be shown
+// to show every group that isn't otherwise shown.
+const AllGroups = `*`
+
+// The levels of logs, from least to most severe.
+// Logs below the minimum level of a group are not written.
+const (
+	LevelDebug = slog.LevelDebug
+	LevelInfo  = slog.LevelInfo
+	LevelWarn  = slog.LevelWarn
+	LevelError = slog.LevelError
+)
+
 type LogEntry struct {
+	Level      slog.Level
 	Indent     int
 	Group      string
 	Message    string
@@ -38,14 +56,44 @@ func NewWriter(out io.Writer) *Logger {
 	return NewFunc(writerToFunc(out))
 }
 
+// NewJSON creates a logger that writes each log as a line of JSON
+// to the given writer. Each line has the time, level, message,
+// and, if not empty, the group and indent of the log.
+func NewJSON(out io.Writer) *Logger {
+	if utils.IsNil(out) {
+		return nil
+	}
+	return NewSlog(slog.NewJSONHandler(out, &slog.HandlerOptions{
+		Level: LevelDebug,
+	}))
+}
+
+// NewSlog creates a logger that writes each log as a record to the given
+// structured log handler. The group and indent of the log are added to
+// the record as attributes. The handler may filter the logs further.
+func NewSlog(handler slog.Handler) *Logger {
+	if utils.IsNil(handler) {
+		return nil
+	}
+	return NewFunc(handlerToFunc(handler))
+}
+
 // NewFunc will create a logger that calls the given
 // function to handle logging a message.
+//
+// The logger may be used by concurrent workers, so the given function
+// is only called for one message at a time.
 func NewFunc(handle func(entry LogEntry)) *Logger {
 	if utils.IsNil(handle) {
 		return nil
 	}
+	lock := &sync.Mutex{}
 	return &Logger{
-		out:        handle,
+		out: func(entry LogEntry) {
+			lock.Lock()
+			defer lock.Unlock()
+			handle(entry)
+		},
 		showGroups: defaultShowGroups,
 	}
 }
@@ -60,8 +108,10 @@ func Null() *Logger {
 type Logger struct {
 	out        func(entry LogEntry)
 	indent     int
-	show       map[string]struct{}
+	level      slog.Level
+	show       map[string]slog.Level
 	curGroup   string
+	curLevel   slog.Level
 	disabled   bool
 	indentText string
 	showGroups bool
@@ -70,6 +120,9 @@ type Logger struct {
 func simpleToFunc(out func(string)) func(entry LogEntry) {
 	return func(entry LogEntry) {
 		prefix := entry.IndentText
+		if entry.Level > LevelInfo {
+			prefix += entry.Level.String() + `: `
+		}
 		if entry.ShowGroups && len(entry.Group) > 0 {
 			prefix += `[` + entry.Group + `] `
 		}
@@ -87,15 +140,35 @@ func writerToFunc(out io.Writer) func(entry LogEntry) {
 	})
 }
 
+func handlerToFunc(handler slog.Handler) func(entry LogEntry) {
+	return func(entry LogEntry) {
+		ctx := context.Background()
+		if !handler.Enabled(ctx, entry.Level) {
+			return
+		}
+		r := slog.NewRecord(time.Now(), entry.Level, entry.Message, 0)
+		if len(entry.Group) > 0 {
+			r.AddAttrs(slog.String(`group`, entry.Group))
+		}
+		if entry.Indent > 0 {
+			r.AddAttrs(slog.Int(`indent`, entry.Indent))
+		}
+		if err := handler.Handle(ctx, r); err != nil {
+			panic(terror.New(`failed to write to a log`, err))
+		}
+	}
+}
+
 func (log *Logger) copy() *Logger {
 	c := *log
 	c.show = maps.Clone(log.show)
 	return &c
 }
 
-func (log *Logger) write(msg string) *Logger {
-	if log != nil && !log.disabled {
+func (log *Logger) write(level slog.Level, msg string) *Logger {
+	if log.Enabled(level) {
 		log.out(LogEntry{
+			Level:      level,
 			Indent:     log.indent,
 			Group:      log.curGroup,
 			Message:    msg,
@@ -106,14 +179,40 @@ func (log *Logger) write(msg string) *Logger {
 	return log
 }
 
-// Log will write a log if this logger is enabled based on visible groups.
+// Log will write an info log if this logger is enabled
+// based on visible groups and levels.
 func (log *Logger) Log(args ...any) *Logger {
-	return log.write(fmt.Sprint(args...))
+	return log.write(LevelInfo, fmt.Sprint(args...))
 }
 
-// Logf will write a log if this logger is enabled based on visible groups.
+// Logf will write an info log if this logger is enabled
+// based on visible groups and levels.
 func (log *Logger) Logf(format string, args ...any) *Logger {
-	return log.write(fmt.Sprintf(format, args...))
+	return log.write(LevelInfo, fmt.Sprintf(format, args...))
+}
+
+// Debug will write a debug log if this logger is enabled
+// based on visible groups and levels.
+func (log *Logger) Debug(args ...any) *Logger {
+	return log.write(LevelDebug, fmt.Sprint(args...))
+}
+
+// Debugf will write a debug log if this logger is enabled
+// based on visible groups and levels.
+func (log *Logger) Debugf(format string, args ...any) *Logger {
+	return log.write(LevelDebug, fmt.Sprintf(format, args...))
+}
+
+// Warn will write a warning log if this logger is enabled
+// based on visible groups and levels.
+func (log *Logger) Warn(args ...any) *Logger {
+	return log.write(LevelWarn, fmt.Sprint(args...))
+}
+
+// Warnf will write a warning log if this logger is enabled
+// based on visible groups and levels.
+func (log *Logger) Warnf(format string, args ...any) *Logger {
+	return log.write(LevelWarn, fmt.Sprintf(format, args...))
 }
 
 // Indent will add to the current indent for the message.
@@ -142,13 +241,37 @@ func (log *Logger) Disabled() bool {
 	return log == nil || log.disabled
 }
 
+// Enabled indicates that logs with the given level
+// will be written for the current group.
+func (log *Logger) Enabled(level slog.Level) bool {
+	return !log.Disabled() && level >= log.curLevel
+}
+
 func (log *Logger) updateEnable() {
 	if len(log.curGroup) <= 0 {
 		log.disabled = false
+		log.curLevel = log.level
 		return
 	}
-	_, has := log.show[log.curGroup]
+	level, has := log.show[log.curGroup]
+	if !has {
+		level, has = log.show[AllGroups]
+	}
 	log.disabled = !has
+	log.curLevel = level
+}
+
+// SetLevel sets the minimum level of the logs that are written outside
+// of any group. The receiver is not modified, the returned logger will
+// have the new level. The default minimum level is info.
+func (log *Logger) SetLevel(level slog.Level) *Logger {
+	if log == nil {
+		return nil
+	}
+	c := log.copy()
+	c.level = level
+	c.updateEnable()
+	return c
 }
 
 // Group indicates that all the logs to the returned logger
@@ -165,20 +288,30 @@ func (log *Logger) Group(name string) *Logger {
 	return c
 }
 
-// Show indicates which groups will be logged. The receiver is not
+// Show indicates which groups will be logged at every level.
+// The receiver is not modified, the returned logger will have the shown
+// groups in it. This will update if the Logger is disabled or not.
+// Showing AllGroups will show every group.
+func (log *Logger) Show(groups ...string) *Logger {
+	return log.ShowLevel(LevelDebug, groups...)
+}
+
+// ShowLevel indicates which groups will be logged and the minimum level
+// of the logs that will be written in those groups. The receiver is not
 // modified, the returned logger will have the shown groups in it.
 // This will update if the Logger is disabled or not.
-func (log *Logger) Show(groups ...string) *Logger {
+// Showing AllGroups will show every group not otherwise shown.
+func (log *Logger) ShowLevel(level slog.Level, groups ...string) *Logger {
 	if log == nil {
 		return nil
 	}
 	c := log.copy()
 	if c.show == nil {
-		c.show = make(map[string]struct{})
+		c.show = make(map[string]slog.Level)
 	}
 	for _, group := range groups {
 		if len(group) > 0 {
-			c.show[group] = struct{}{}
+			c.show[group] = level
 		}
 	}
 	c.updateEnable()
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+)
+
+func Test_Logger_Levels(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := NewWriter(buf)
+	log.Debug(`debug 1`).Log(`info 1`).Warn(`warn 1`)
+
+	log = log.SetLevel(LevelDebug)
+	log.Debugf(`debug %d`, 2).Logf(`info %d`, 2)
+
+	log = log.SetLevel(LevelWarn)
+	log.Log(`info 3`).Warnf(`warn %d`, 3)
+
+	check.Equal(t, lines(
+		`info 1`,
+		`WARN: warn 1`,
+		`debug 2`,
+		`info 2`,
+		`WARN: warn 3`,
+	)).Assert(buf.String())
+}
+
+func Test_Logger_Groups(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := NewWriter(buf).Show(`cats`).ShowLevel(LevelWarn, `dogs`)
+	cats := log.Group(`cats`)
+	dogs := log.Group(`dogs`)
+	fish := log.Group(`fish`)
+
+	check.False(t).Name(`cats disabled`).Assert(cats.Disabled())
+	check.False(t).Name(`dogs disabled`).Assert(dogs.Disabled())
+	check.True(t).Name(`fish disabled`).Assert(fish.Disabled())
+
+	// Shown groups are written at debug even though the
+	// ungrouped logs are only written at info and above.
+	log.Debug(`ungrouped debug`).Log(`ungrouped info`)
+	cats.Debug(`cats debug`).Indent().Log(`cats info`)
+	dogs.Log(`dogs info`).Warn(`dogs warn`)
+	fish.Warn(`fish warn`)
+
+	log = log.Hide(`cats`)
+	log.Group(`cats`).Warn(`hidden cats warn`)
+
+	check.Equal(t, lines(
+		`ungrouped info`,
+		`cats debug`,
+		`┆  cats info`,
+		`WARN: dogs warn`,
+	)).Assert(buf.String())
+}
+
+func Test_Logger_AllGroups(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := NewWriter(buf).ShowLevel(LevelInfo, AllGroups).Show(`cats`)
+	log.Group(`cats`).Debug(`cats debug`)
+	log.Group(`dogs`).Debug(`dogs debug`).Log(`dogs info`)
+	log.Group(`fish`).Log(`fish info`)
+
+	check.Equal(t, lines(
+		`cats debug`,
+		`dogs info`,
+		`fish info`,
+	)).Assert(buf.String())
+}
+
+func Test_Logger_Null(t *testing.T) {
+	log := Null()
+	check.True(t).Assert(log.Disabled())
+	check.False(t).Assert(log.Enabled(LevelError))
+	check.Nil(t).Assert(log.Group(`cats`).Show(AllGroups).Indent().Log(`nothing`))
+}
+
+func Test_Logger_JSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := NewJSON(buf).SetLevel(LevelDebug).Show(`cats`)
+	log.Debug(`start`)
+	log.Group(`cats`).Indent().Indent().Warn(`hiss`)
+	log.Group(`dogs`).Log(`bark`)
+
+	records := []map[string]any{}
+	for line := range strings.Lines(buf.String()) {
+		r := map[string]any{}
+		check.NoError(t).Require(json.Unmarshal([]byte(line), &r))
+		check.NotEmpty(t).Name(`time`).Assert(r[slog.TimeKey])
+		delete(r, slog.TimeKey)
+		records = append(records, r)
+	}
+	check.Equal(t, []map[string]any{
+		{`level`: `DEBUG`, `msg`: `start`},
+		{`level`: `WARN`, `msg`: `hiss`, `group`: `cats`, `indent`: 2.0},
+	}).Assert(records)
+}
+
+func Test_Logger_SlogFiltered(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := slog.NewTextHandler(buf, &slog.HandlerOptions{Level: LevelWarn})
+	log := NewSlog(handler).SetLevel(LevelDebug)
+	log.Debug(`filtered by handler`)
+	log.Warn(`kept`)
+	check.True(t).Assert(strings.Contains(buf.String(), `msg=kept`))
+	check.False(t).Assert(strings.Contains(buf.String(), `filtered`))
+}
+
+func lines(lines ...string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
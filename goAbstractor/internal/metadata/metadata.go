@@ -0,0 +1,230 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+)
+
+// abstractorModule is the path of the module the abstractor is in.
+const abstractorModule = `github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor`
+
+// Metadata is the provenance of an abstraction, i.e. what produced it
+// and what it was produced from, so that it can be reproduced and compared.
+type Metadata struct {
+	// Version is the version of the abstractor module, or `(devel)`
+	// if the abstractor was built from a local checkout.
+	Version string
+
+	// Revision is the VCS revision the abstractor was built from.
+	// This is empty if the revision wasn't stamped into the build.
+	// Modified indicates the abstractor was built with uncommitted changes.
+	Revision string
+	Modified bool
+
+	// GoVersion is the version of the Go toolchain that the packages
+	// were read with, which may differ from the version the abstractor
+	// was built with, e.g. when a toolchain is selected by the module.
+	GoVersion string
+
+	// Patterns, BuildFlags, Builds, and Tests are the
+	// configuration that the packages were read with.
+	Patterns   []string
+	BuildFlags []string
+	Builds     []string
+	Tests      bool
+
+	// Modules are the modules that were read, not including dependencies.
+	Modules []*Module
+
+	// Files are the Go files that were read, not including dependencies.
+	Files []*File
+}
+
+// Module is the provenance of a module that was read.
+type Module struct {
+	Path      string
+	Version   string
+	GoVersion string
+
+	// Revision is the VCS revision of the module's directory
+	// and Modified indicates there were uncommitted changes.
+	// The revision is empty if the module isn't in a git repository.
+	Revision string
+	Modified bool
+
+	// GoModHash is the hash of the module's go.mod file.
+	GoModHash string
+}
+
+// File is a Go file that was read and the hash of its content.
+type File struct {
+	// Path is the module path joined to the path of the file relative to
+	// the module's directory, e.g. `example.com/foo/bar/baz.go`.
+	Path string
+	Hash string
+}
+
+// New creates the metadata for the given packages read with the given
+// environment of the Go toolchain, e.g. `GOVERSION=go1.24.0`, see reader.GoEnv.
+// Only the given packages are included, not any dependencies.
+// The context is used to cancel looking up the VCS revisions.
+func New(ctx context.Context, goEnv []string, pkgs []*packages.Package) *Metadata {
+	m := &Metadata{}
+	for _, value := range goEnv {
+		if version, ok := strings.CutPrefix(value, `GOVERSION=`); ok {
+			m.GoVersion = version
+		}
+	}
+	m.readBuildInfo()
+
+	modules := map[string]*Module{}
+	files := map[string]*File{}
+	for _, pkg := range pkgs {
+		mod := pkg.Module
+		if mod != nil {
+			if _, has := modules[mod.Path]; !has {
+				modules[mod.Path] = newModule(ctx, mod)
+			}
+		}
+		for _, path := range pkg.GoFiles {
+			name := filePath(mod, path)
+			if _, has := files[name]; !has {
+				files[name] = &File{Path: name, Hash: hashFile(path)}
+			}
+		}
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(modules)) {
+		m.Modules = append(m.Modules, modules[path])
+	}
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		m.Files = append(m.Files, files[path])
+	}
+	return m
+}
+
+// readBuildInfo reads the version and revision of the abstractor
+// from the build information stamped into the running binary.
+func (m *Metadata) readBuildInfo() {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	if info.Main.Path != abstractorModule {
+		// The abstractor is being used as a library.
+		for _, dep := range info.Deps {
+			if dep.Path == abstractorModule {
+				m.Version = dep.Version
+			}
+		}
+		return
+	}
+	m.Version = info.Main.Version
+	for _, s := range info.Settings {
+		switch s.Key {
+		case `vcs.revision`:
+			m.Revision = s.Value
+		case `vcs.modified`:
+			m.Modified = s.Value == `true`
+		}
+	}
+}
+
+func newModule(ctx context.Context, mod *packages.Module) *Module {
+	m := &Module{
+		Path:      mod.Path,
+		Version:   mod.Version,
+		GoVersion: mod.GoVersion,
+	}
+	if len(mod.GoMod) > 0 {
+		m.GoModHash = hashFile(mod.GoMod)
+	}
+	if len(mod.Dir) > 0 && len(mod.Version) <= 0 {
+		m.Revision, m.Modified = gitRevision(ctx, mod.Dir)
+	}
+	return m
+}
+
+// gitRevision gets the git revision of the given directory and
+// if there are any uncommitted changes. Returns an empty revision
+// if the directory isn't in a git repository or git isn't available.
+func gitRevision(ctx context.Context, dir string) (string, bool) {
+	rev, err := exec.CommandContext(ctx, `git`, `-C`, dir, `rev-parse`, `HEAD`).Output()
+	if err != nil {
+		return ``, false
+	}
+	status, err := exec.CommandContext(ctx, `git`, `-C`, dir, `status`, `--porcelain`, `--`, `.`).Output()
+	return strings.TrimSpace(string(rev)), err == nil && len(bytes.TrimSpace(status)) > 0
+}
+
+// filePath gets the path of the file that doesn't depend on
+// where the module was read from. If the file isn't in the module,
+// the file's path is used as is.
+func filePath(mod *packages.Module, path string) string {
+	if mod != nil && len(mod.Dir) > 0 {
+		if rel, err := filepath.Rel(mod.Dir, path); err == nil && !strings.HasPrefix(rel, `..`) {
+			return mod.Path + `/` + filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// hashFile gets the SHA-256 hash of the given file's content.
+// Returns an empty string if the file couldn't be read.
+func hashFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ``
+	}
+	sum := sha256.Sum256(data)
+	return `sha256:` + hex.EncodeToString(sum[:])
+}
+
+func (m *Metadata) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	if m == nil {
+		return nil
+	}
+	return jsonify.NewMap().
+		AddNonZero(ctx, `version`, m.Version).
+		AddNonZero(ctx, `revision`, m.Revision).
+		AddNonZero(ctx, `modified`, m.Modified).
+		Add(ctx, `goVersion`, m.GoVersion).
+		AddNonZero(ctx, `patterns`, m.Patterns).
+		AddNonZero(ctx, `buildFlags`, m.BuildFlags).
+		AddNonZero(ctx, `builds`, m.Builds).
+		AddNonZero(ctx, `tests`, m.Tests).
+		Add(ctx, `skipDead`, ctx.SkipDead()).
+		AddNonZero(ctx, `testMetrics`, ctx.IncludeTestMetrics()).
+		AddNonZero(ctx, `excludeGenerated`, ctx.ExcludeGeneratedMetrics()).
+		AddNonZero(ctx, `modules`, m.Modules).
+		AddNonZero(ctx, `files`, m.Files)
+}
+
+func (m *Module) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `path`, m.Path).
+		AddNonZero(ctx, `version`, m.Version).
+		AddNonZero(ctx, `goVersion`, m.GoVersion).
+		AddNonZero(ctx, `revision`, m.Revision).
+		AddNonZero(ctx, `modified`, m.Modified).
+		AddNonZero(ctx, `goModHash`, m.GoModHash)
+}
+
+func (f *File) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `path`, f.Path).
+		AddNonZero(ctx, `hash`, f.Hash)
+}
@@ -0,0 +1,73 @@
+package metadata
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+)
+
+func Test_Metadata(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, `go.mod`, "module example.com/meta\n\ngo 1.23\n")
+	writeFile(t, dir, `meta.go`, "package meta\n\nfunc Foo() int { return 1 }\n")
+	git(t, dir, `init`, `-q`)
+	git(t, dir, `add`, `-A`)
+	git(t, dir, `commit`, `-q`, `-m`, `initial`)
+	rev := git(t, dir, `rev-parse`, `HEAD`)
+
+	m := New(context.Background(), []string{`GOVERSION=go1.23.3`, `CGO_ENABLED=0`}, load(t, dir))
+	check.Equal(t, `go1.23.3`).Assert(m.GoVersion)
+	check.Length(t, 1).Assert(m.Modules)
+	mod := m.Modules[0]
+	check.Equal(t, `example.com/meta`).Assert(mod.Path)
+	check.Equal(t, rev).Assert(mod.Revision)
+	check.False(t).Assert(mod.Modified)
+	check.Equal(t, hashFile(filepath.Join(dir, `go.mod`))).Assert(mod.GoModHash)
+
+	check.Length(t, 1).Assert(m.Files)
+	check.Equal(t, `example.com/meta/meta.go`).Assert(m.Files[0].Path)
+	check.Equal(t, `sha256:07a0bf18aa8a1c120b56d835fd7321052e2eb7588a8510e5d83ff4cffc51ad02`).
+		Assert(m.Files[0].Hash)
+
+	// Editing the file changes the hash and marks the module as modified.
+	writeFile(t, dir, `meta.go`, "package meta\n\nfunc Foo() int { return 2 }\n")
+	m2 := New(context.Background(), nil, load(t, dir))
+	check.Equal(t, rev).Assert(m2.Modules[0].Revision)
+	check.True(t).Assert(m2.Modules[0].Modified)
+	check.NotEqual(t, m2.Files[0].Hash).Assert(m.Files[0].Hash)
+
+	// The skip dead flag is from the context the metadata is written with
+	// since that is what determines if the dead constructs were written.
+	b, err := jsonify.Marshal(jsonify.NewContext().SetSkipDead(true), m2)
+	check.NoError(t).Require(err)
+	check.True(t).Assert(strings.Contains(string(b), `"skipDead": true`))
+}
+
+func load(t *testing.T, dir string) []*packages.Package {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedModule,
+		Dir:  dir,
+	}, `./...`)
+	check.NoError(t).Require(err)
+	return pkgs
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644)
+	check.NoError(t).Require(err)
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	args = append([]string{`-C`, dir, `-c`, `user.name=test`, `-c`, `user.email=test@example.com`}, args...)
+	out, err := exec.Command(`git`, args...).CombinedOutput()
+	check.NoError(t).With(`output`, string(out)).Require(err)
+	return strings.TrimSpace(string(out))
+}
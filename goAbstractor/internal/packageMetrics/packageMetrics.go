@@ -0,0 +1,290 @@
+package packageMetrics
+
+import (
+	"iter"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/innate"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+)
+
+// Report is the coupling and Martin metrics for the read packages.
+//
+// The coupling is determined from the constructs that are actually used,
+// not from the import lines. A package uses another package when any of
+// its declarations reads, writes, or invokes a declaration in the other
+// package, or has a field, parameter, result, type parameter, or type
+// argument with a type declared in the other package.
+type Report struct {
+	Packages []*Entry
+}
+
+// Entry is the coupling and Martin metrics for a read package.
+type Entry struct {
+	Path string
+
+	// Uses are the paths of the packages that this package uses.
+	Uses []string
+
+	// UsedBy are the paths of the read packages that use this package.
+	UsedBy []string
+
+	// Afferent is the afferent coupling (Ca),
+	// the number of read packages that use this package.
+	Afferent int
+
+	// Efferent is the efferent coupling (Ce),
+	// the number of packages that this package uses.
+	Efferent int
+
+	// Instability is `Ce/(Ca + Ce)`, from zero for a package that is
+	// only used to one for a package that only uses other packages.
+	// This is zero if the package isn't coupled to any other package.
+	Instability float64
+
+	// Abstractness is the ratio of interface declarations to all
+	// the type declarations, both interfaces and objects, in the package.
+	// This is zero if the package has no type declarations.
+	Abstractness float64
+
+	// Distance is the distance from the main sequence,
+	// `|A + I - 1|`, where `A` is the abstractness and
+	// `I` is the instability.
+	Distance float64
+}
+
+// New creates the package metrics report for the given project.
+// If excludeStdlib is true, the standard library packages are
+// not counted as packages that are used.
+//
+// Only the declarations that are measured are counted, and packages
+// with only declarations that aren't measured, e.g. external test
+// packages by default, are left out.
+func New(proj constructs.Project, excludeStdlib bool, measure constructs.Measure) *Report {
+	entries := map[constructs.Package]*Entry{}
+	uses := map[constructs.Package]map[constructs.Package]bool{}
+	for pkg := range proj.Packages().Enumerate().Seq() {
+		if pkg.Duplicate() || !pkg.EntryPoint() || !measured(pkg, measure) {
+			continue
+		}
+		entries[pkg] = &Entry{Path: pkg.Path()}
+
+		u := &usages{
+			measure:  measure,
+			visited:  map[constructs.Construct]bool{},
+			packages: map[constructs.Package]bool{},
+		}
+		u.declarations(pkg)
+		delete(u.packages, pkg)
+		for other := range u.packages {
+			if other.Path() == innate.Builtin || (excludeStdlib && isStdlib(other)) {
+				delete(u.packages, other)
+			}
+		}
+		uses[pkg] = u.packages
+	}
+
+	for pkg, e := range entries {
+		for other := range uses[pkg] {
+			e.Uses = append(e.Uses, other.Path())
+			if oe, has := entries[other]; has {
+				oe.UsedBy = append(oe.UsedBy, pkg.Path())
+			}
+		}
+	}
+
+	r := &Report{}
+	for pkg, e := range entries {
+		slices.Sort(e.Uses)
+		slices.Sort(e.UsedBy)
+		e.Afferent = len(e.UsedBy)
+		e.Efferent = len(e.Uses)
+		if total := e.Afferent + e.Efferent; total > 0 {
+			e.Instability = float64(e.Efferent) / float64(total)
+		}
+		e.Abstractness = abstractness(pkg, measure)
+		e.Distance = math.Abs(e.Abstractness + e.Instability - 1.0)
+		r.Packages = append(r.Packages, e)
+	}
+	slices.SortFunc(r.Packages, func(a, b *Entry) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return r
+}
+
+// isStdlib determines if the given package is from the standard library,
+// a package, other than a read package, that isn't in a module and
+// that doesn't have a domain as the first part of its path.
+func isStdlib(pkg constructs.Package) bool {
+	first, _, _ := strings.Cut(pkg.Path(), `/`)
+	return !pkg.EntryPoint() && len(pkg.Module()) <= 0 && !strings.Contains(first, `.`)
+}
+
+// measured determines if the package is empty or has
+// at least one declaration that is measured.
+func measured(pkg constructs.Package, measure constructs.Measure) bool {
+	decls := 0
+	for decl := range declarations(pkg) {
+		if measure.Measured(decl) {
+			return true
+		}
+		decls++
+	}
+	return decls <= 0
+}
+
+// declarations enumerates the declarations in the package
+// that aren't duplicates.
+func declarations(pkg constructs.Package) iter.Seq[constructs.Declaration] {
+	return func(yield func(constructs.Declaration) bool) {
+		for it := range pkg.InterfaceDecls().Enumerate().Seq() {
+			if !it.Duplicate() && !yield(it) {
+				return
+			}
+		}
+		for obj := range pkg.Objects().Enumerate().Seq() {
+			if !obj.Duplicate() && !yield(obj) {
+				return
+			}
+		}
+		for m := range pkg.Methods().Enumerate().Seq() {
+			if !m.Duplicate() && !yield(m) {
+				return
+			}
+		}
+		for v := range pkg.Values().Enumerate().Seq() {
+			if !v.Duplicate() && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func abstractness(pkg constructs.Package, measure constructs.Measure) float64 {
+	interfaces := pkg.InterfaceDecls().Enumerate().
+		Where(func(it constructs.InterfaceDecl) bool { return !it.Duplicate() && measure.Measured(it) }).Count()
+	objects := pkg.Objects().Enumerate().
+		Where(func(obj constructs.Object) bool { return !obj.Duplicate() && measure.Measured(obj) }).Count()
+	if total := interfaces + objects; total > 0 {
+		return float64(interfaces) / float64(total)
+	}
+	return 0.0
+}
+
+// usages collects the packages used by the declarations in a package.
+type usages struct {
+	measure  constructs.Measure
+	visited  map[constructs.Construct]bool
+	packages map[constructs.Package]bool
+}
+
+func (u *usages) declarations(pkg constructs.Package) {
+	for it := range pkg.InterfaceDecls().Enumerate().Seq() {
+		if u.measure.Measured(it) {
+			addSlice(u, it.TypeParams())
+			u.add(it.Interface())
+		}
+	}
+	for obj := range pkg.Objects().Enumerate().Seq() {
+		if u.measure.Measured(obj) {
+			addSlice(u, obj.TypeParams())
+			u.add(obj.Data())
+		}
+	}
+	for m := range pkg.Methods().Enumerate().Seq() {
+		if u.measure.Measured(m) {
+			addSlice(u, m.TypeParams())
+			u.add(m.Signature())
+			u.addMetrics(m.Metrics())
+		}
+	}
+	for v := range pkg.Values().Enumerate().Seq() {
+		if u.measure.Measured(v) {
+			u.add(v.Type())
+			u.addMetrics(v.Metrics())
+		}
+	}
+}
+
+func (u *usages) addMetrics(metrics constructs.Metrics) {
+	if utils.IsNil(metrics) {
+		return
+	}
+	addSlice(u, metrics.Reads().ToSlice())
+	addSlice(u, metrics.Writes().ToSlice())
+	addSlice(u, metrics.Invokes().ToSlice())
+}
+
+func addSlice[T constructs.Construct](u *usages, cs []T) {
+	for _, c := range cs {
+		u.add(c)
+	}
+}
+
+// add records the package of the given construct if it is a declaration,
+// otherwise it adds the packages of the declarations the construct uses.
+func (u *usages) add(c constructs.Construct) {
+	if utils.IsNil(c) || u.visited[c] {
+		return
+	}
+	u.visited[c] = true
+
+	switch t := c.(type) {
+	case constructs.Declaration:
+		u.packages[t.Package()] = true
+	case constructs.Abstract:
+		u.add(t.Signature())
+	case constructs.Argument:
+		u.add(t.Type())
+	case constructs.Field:
+		u.add(t.Type())
+	case constructs.TypeParam:
+		u.add(t.Type())
+	case constructs.StructDesc:
+		addSlice(u, t.Fields())
+	case constructs.Signature:
+		addSlice(u, t.Params())
+		addSlice(u, t.Results())
+	case constructs.InterfaceDesc:
+		addSlice(u, t.Abstracts())
+		addSlice(u, t.Approx())
+		addSlice(u, t.Exact())
+	case constructs.InterfaceInst:
+		u.add(t.Generic())
+		addSlice(u, t.ImplicitTypes())
+		addSlice(u, t.InstanceTypes())
+	case constructs.ObjectInst:
+		u.add(t.Generic())
+		addSlice(u, t.ImplicitTypes())
+		addSlice(u, t.InstanceTypes())
+	case constructs.MethodInst:
+		u.add(t.Generic())
+		addSlice(u, t.InstanceTypes())
+	case constructs.Selection:
+		u.add(t.Origin())
+	}
+}
+
+func (r *Report) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	if r == nil {
+		return nil
+	}
+	return jsonify.New(ctx, r.Packages)
+}
+
+func (e *Entry) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `path`, e.Path).
+		AddNonZero(ctx, `uses`, e.Uses).
+		AddNonZero(ctx, `usedBy`, e.UsedBy).
+		Add(ctx, `afferent`, e.Afferent).
+		Add(ctx, `efferent`, e.Efferent).
+		Add(ctx, `instability`, e.Instability).
+		Add(ctx, `abstractness`, e.Abstractness).
+		Add(ctx, `distance`, e.Distance)
+}
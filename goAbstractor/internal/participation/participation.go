@@ -0,0 +1,143 @@
+package participation
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+)
+
+// Matrix is the participation of methods with objects.
+// The participation is a fuzzy estimate of membership between zero and one
+// of how much an object participates in the purpose of a method.
+//
+// Each object that a method uses via a read, write, or invocation,
+// as determined by constructs.ObjectOf, is counted once for each usage.
+// The receiver of a method is weighted the same as all the other usages
+// together, so that the receiver is always at least half of the
+// participation of a method. The counts are normalized so that the
+// participation of each method sums to one, unless the method uses
+// no objects, in which case its participation is all zero.
+type Matrix struct {
+	// Methods are the qualified names of the methods in the rows,
+	// e.g. `example.com/foo.Bar` or `example.com/foo.Bar.Baz`.
+	Methods []string
+
+	// Objects are the qualified names of the objects in the columns,
+	// e.g. `example.com/foo.Bar`.
+	Objects []string
+
+	// Values are the participation indexed by method then object.
+	Values [][]float64
+}
+
+// New creates the participation matrix for the methods and objects
+// declared in the read packages of the given project.
+// Methods without bodies, e.g. external functions, are not included,
+// nor are the objects and methods that aren't measured.
+func New(proj constructs.Project, measure constructs.Measure) *Matrix {
+	include := func(decl constructs.Declaration) bool {
+		return !decl.Duplicate() && decl.Package().EntryPoint() && measure.Measured(decl)
+	}
+
+	objects := []constructs.Object{}
+	index := map[constructs.Object]int{}
+	for obj := range proj.Objects().Enumerate().Seq() {
+		if include(obj) {
+			index[obj] = len(objects)
+			objects = append(objects, obj)
+		}
+	}
+
+	m := &Matrix{}
+	for _, obj := range objects {
+		m.Objects = append(m.Objects, constructs.QualifiedName(obj))
+	}
+
+	for method := range proj.Methods().Enumerate().Seq() {
+		if !include(method) || utils.IsNil(method.Metrics()) {
+			continue
+		}
+
+		m.Methods = append(m.Methods, constructs.QualifiedName(method))
+		m.Values = append(m.Values, participation(method, index, len(objects)))
+	}
+	return m
+}
+
+func participation(method constructs.Method, index map[constructs.Object]int, count int) []float64 {
+	row := make([]float64, count)
+	total := 0.0
+	add := func(c constructs.Construct) {
+		if i, has := index[constructs.ObjectOf(c)]; has {
+			row[i]++
+			total++
+		}
+	}
+
+	metrics := method.Metrics()
+	for c := range metrics.Reads().Enumerate().Seq() {
+		add(c)
+	}
+	for c := range metrics.Writes().Enumerate().Seq() {
+		add(c)
+	}
+	for c := range metrics.Invokes().Enumerate().Seq() {
+		add(c)
+	}
+
+	if i, has := index[method.Receiver()]; has {
+		weight := max(total, 1.0)
+		row[i] += weight
+		total += weight
+	}
+
+	if total > 0 {
+		for i := range row {
+			row[i] /= total
+		}
+	}
+	return row
+}
+
+// WriteCSV writes the matrix as comma separated values with a header row
+// of the object names and a first column of the method names.
+func (m *Matrix) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(append([]string{`method`}, m.Objects...)); err != nil {
+		return err
+	}
+	for i, method := range m.Methods {
+		record := []string{method}
+		for _, value := range m.Values[i] {
+			record = append(record, strconv.FormatFloat(value, 'g', -1, 64))
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func (m *Matrix) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	methods := jsonify.NewList()
+	for i, method := range m.Methods {
+		values := jsonify.NewMap()
+		for j, value := range m.Values[i] {
+			if value > 0 {
+				values.Add(ctx, m.Objects[j], value)
+			}
+		}
+		methods.Append(ctx, jsonify.NewMap().
+			Add(ctx, `name`, method).
+			AddNonZero(ctx, `participation`, values))
+	}
+	return jsonify.NewMap().
+		AddNonZero(ctx, `objects`, m.Objects).
+		AddNonZero(ctx, `methods`, methods)
+}
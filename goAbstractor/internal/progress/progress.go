@@ -0,0 +1,72 @@
+package progress
+
+import (
+	"fmt"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
+)
+
+// Progress is a report of how far along the abstraction is.
+type Progress struct {
+	// Phase is the part of the abstraction that is being run.
+	Phase diagnostics.Phase
+
+	// Done is the number of packages that have been finished and
+	// Total is the number of packages to finish in the phase.
+	// Both are zero for phases that don't work through the packages.
+	Done  int
+	Total int
+
+	// Iteration is the iteration, starting at one, of the resolver's loop
+	// that repeats phases until nothing changes. This is zero for phases
+	// that aren't part of that loop.
+	Iteration int
+}
+
+func (p Progress) String() string {
+	s := string(p.Phase)
+	if p.Total > 0 {
+		s += fmt.Sprintf(` %d/%d packages`, p.Done, p.Total)
+	}
+	if p.Iteration > 0 {
+		s += fmt.Sprintf(` (iteration %d)`, p.Iteration)
+	}
+	return s
+}
+
+// Reporter reports the progress of the abstraction.
+// A nil reporter is valid and will not report anything.
+type Reporter struct {
+	handle func(p Progress)
+}
+
+// New creates a new reporter that calls the given handle for each report.
+// Returns nil if the handle is nil.
+func New(handle func(p Progress)) *Reporter {
+	if handle == nil {
+		return nil
+	}
+	return &Reporter{handle: handle}
+}
+
+// Report reports the given progress.
+func (r *Reporter) Report(p Progress) {
+	if r != nil {
+		r.handle(p)
+	}
+}
+
+// Phase reports the start of the given phase.
+func (r *Reporter) Phase(phase diagnostics.Phase) {
+	r.Report(Progress{Phase: phase})
+}
+
+// Packages reports the number of packages done in the given phase.
+func (r *Reporter) Packages(phase diagnostics.Phase, done, total int) {
+	r.Report(Progress{Phase: phase, Done: done, Total: total})
+}
+
+// Iteration reports the start of the given phase in the resolver's loop.
+func (r *Reporter) Iteration(phase diagnostics.Phase, iteration int) {
+	r.Report(Progress{Phase: phase, Iteration: iteration})
+}
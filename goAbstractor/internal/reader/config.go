@@ -3,6 +3,11 @@ package reader
 import (
 	"context"
 	"fmt"
+	"go/build"
+	"go/token"
+	"os"
+	"slices"
+	"strings"
 
 	"golang.org/x/tools/go/packages"
 )
@@ -16,17 +21,140 @@ type Config struct {
 	// Dir is the path to the main package or primary package.
 	// The path should contain the mod file.
 	// The path follows the standard pattern for go tools.
+	//
+	// If the path contains a go.work file and no patterns are given,
+	// all the packages in every module used by the workspace are read.
 	Dir string
 
 	// Patterns is the patterns to load the packages with.
 	Patterns []string
 
+	// Modules are the optional paths to additional module roots to read
+	// along with Dir. All the packages in each module are read so that
+	// the modules can be abstracted together into one project.
+	// The modules are read together with Dir's module, or the modules in
+	// Dir's workspace, as one temporary workspace so that a package used
+	// by more than one of the modules is only read once.
+	Modules []string
+
 	// Context is the optional context to cancel a build with.
 	Context context.Context
 
 	// BuildFlags are the optional build flags to build with.
 	// Example: // +build tag_name
 	BuildFlags []string
+
+	// SkipBroken indicates that packages with errors, and any package
+	// depending on a package with errors, should be skipped instead of
+	// failing the whole read. The skipped packages are returned so that
+	// they can be recorded as missing from the abstraction.
+	SkipBroken bool
+
+	// Tests indicates that the test files and external test packages
+	// should be loaded along with the packages they test.
+	Tests bool
+
+	// Fset is the optional file set to read the files into.
+	// If not given a new file set is used.
+	Fset *token.FileSet
+
+	// BuildConfigs are the optional build configurations to read with.
+	// Each configuration is read separately by ReadBuilds.
+	BuildConfigs []BuildConfig
+
+	// env is the additional environment variables used
+	// when reading a specific build configuration.
+	env []string
+}
+
+// BuildConfig is a build configuration to read a project with.
+type BuildConfig struct {
+	// Name is the unique name for this configuration.
+	Name string
+
+	// GOOS is the optional target operating system, e.g. `linux`.
+	GOOS string
+
+	// GOARCH is the optional target architecture, e.g. `amd64`.
+	GOARCH string
+
+	// Tags are the optional additional build tags.
+	Tags []string
+}
+
+// ParseBuildConfig parses a build configuration from the given
+// specification, `<goos>/<goarch>` optionally followed by `:` and
+// a `+` separated list of build tags, e.g. `linux/amd64:netgo+osusergo`.
+// The specification is used as the name of the configuration.
+func ParseBuildConfig(spec string) (BuildConfig, error) {
+	target, tags, hasTags := strings.Cut(spec, `:`)
+	goos, goarch, found := strings.Cut(target, `/`)
+	if !found || len(goos) <= 0 || len(goarch) <= 0 {
+		return BuildConfig{}, fmt.Errorf(`build configuration %q must start with <goos>/<goarch>`, spec)
+	}
+
+	bc := BuildConfig{
+		Name:   spec,
+		GOOS:   goos,
+		GOARCH: goarch,
+	}
+	if hasTags {
+		for _, tag := range strings.Split(tags, `+`) {
+			if tag = strings.TrimSpace(tag); len(tag) > 0 {
+				bc.Tags = append(bc.Tags, tag)
+			}
+		}
+	}
+	return bc, nil
+}
+
+// apply creates a copy of the given config to read this build configuration.
+func (bc BuildConfig) apply(c Config) *Config {
+	c.BuildConfigs = nil
+	c.BuildFlags = slices.Clone(c.BuildFlags)
+	if len(bc.Tags) > 0 {
+		tags := strings.Join(bc.Tags, `,`)
+		i := slices.IndexFunc(c.BuildFlags, func(flag string) bool {
+			return strings.HasPrefix(flag, `-tags=`)
+		})
+		if i >= 0 {
+			c.BuildFlags[i] += `,` + tags
+		} else {
+			c.BuildFlags = append(c.BuildFlags, `-tags=`+tags)
+		}
+	}
+	c.env = []string{}
+	if len(bc.GOOS) > 0 {
+		c.env = append(c.env, `GOOS=`+bc.GOOS)
+	}
+	if len(bc.GOARCH) > 0 {
+		c.env = append(c.env, `GOARCH=`+bc.GOARCH)
+	}
+	return &c
+}
+
+// DefaultFlags gets the given build flags with the target operating system
+// and architecture that packages are read with when they aren't read for
+// a build configuration, e.g. so that cached results can be keyed by it.
+func DefaultFlags(buildFlags []string) []string {
+	return append(slices.Clone(buildFlags),
+		`GOOS=`+build.Default.GOOS,
+		`GOARCH=`+build.Default.GOARCH)
+}
+
+// GoEnv gets the environment of the Go toolchain that the packages
+// are read with for this configuration, see GoEnv.
+func (bc BuildConfig) GoEnv(config *Config) ([]string, error) {
+	return GoEnv(bc.apply(*config))
+}
+
+// Flags gets the given build flags with the tags and the environment
+// variables, e.g. `GOOS=linux`, that this configuration is read with.
+// This describes everything that changes how the packages are read
+// for this configuration, e.g. so that cached results can be keyed by it.
+func (bc BuildConfig) Flags(buildFlags []string) []string {
+	c := bc.apply(Config{BuildFlags: buildFlags})
+	return append(c.BuildFlags, c.env...)
 }
 
 func (c Config) toParseConfig() *packages.Config {
@@ -37,13 +165,17 @@ func (c Config) toParseConfig() *packages.Config {
 		packages.NeedExportFile |
 		packages.NeedTypes |
 		packages.NeedSyntax |
-		packages.NeedTypesInfo
+		packages.NeedTypesInfo |
+		packages.NeedForTest |
+		packages.NeedModule
 
 	cfg := &packages.Config{
 		Dir:        c.Dir,
 		BuildFlags: c.BuildFlags,
 		Context:    c.Context,
+		Fset:       c.Fset,
 		Mode:       allNeeds,
+		Tests:      c.Tests,
 	}
 
 	logPackages := false
@@ -53,5 +185,8 @@ func (c Config) toParseConfig() *packages.Config {
 		}
 	}
 
+	if len(c.env) > 0 {
+		cfg.Env = append(os.Environ(), c.env...)
+	}
 	return cfg
 }
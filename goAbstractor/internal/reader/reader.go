@@ -1,14 +1,30 @@
 package reader
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"go/token"
+	"go/version"
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
 
+	"golang.org/x/mod/modfile"
 	"golang.org/x/tools/go/packages"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
 )
 
 // Read reads a project and all its packages and files.
-func Read(config *Config) (ps []*packages.Package, err error) {
+//
+// If the config is set to skip broken packages, the packages with errors
+// are not returned and a diagnostic is returned for each skipped package.
+func Read(config *Config) (ps []*packages.Package, skipped []diagnostics.Diagnostic, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = recoverError(r)
@@ -16,20 +32,318 @@ func Read(config *Config) (ps []*packages.Package, err error) {
 	}()
 
 	cfg := config.toParseConfig()
+	if cfg.Fset == nil {
+		cfg.Fset = token.NewFileSet()
+	}
+
+	work, err := readWorkspace(cfg.Dir)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	patterns := config.Patterns
+	if len(patterns) <= 0 && work != nil {
+		patterns = workspacePatterns(work)
+	}
 	if len(patterns) <= 0 {
 		patterns = []string{cfg.Dir}
 	}
 
+	// The additional modules are read with a temporary workspace that
+	// uses all the modules so that all the packages are loaded together.
+	// This makes any package used by more than one module be shared.
+	if len(config.Modules) > 0 {
+		workDir, err := os.MkdirTemp(``, `goAbstractor-work-*`)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer os.RemoveAll(workDir)
+
+		workPath := filepath.Join(workDir, `go.work`)
+		if err := writeModulesWorkspace(workPath, cfg.Dir, config.Modules); err != nil {
+			return nil, nil, err
+		}
+		cfg.Env = append(workspaceEnv(cfg.Env), `GOWORK=`+workPath)
+
+		for _, dir := range config.Modules {
+			abs, err := filepath.Abs(dir)
+			if err != nil {
+				return nil, nil, err
+			}
+			patterns = append(patterns, filepath.ToSlash(abs)+`/...`)
+		}
+	} else if work != nil {
+		cfg.Env = workspaceEnv(cfg.Env)
+	}
+
 	ps, err = packages.Load(cfg, patterns...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if err = addFileModules(ps); err != nil {
+		return nil, nil, err
+	}
+	if config.SkipBroken {
+		ps, skipped = skipBroken(ps)
+		return ps, skipped, nil
 	}
 	if err = allPackageErrors(ps); err != nil {
+		return nil, nil, err
+	}
+	return ps, nil, nil
+}
+
+// ReadBuilds reads a project once for each of the build configurations
+// in the given config. All the builds are read into the same file set.
+// Returns the packages for each build keyed by the build configuration name.
+//
+// If the config is set to skip broken packages, the returned diagnostics
+// for the skipped packages are named with the build configuration.
+func ReadBuilds(config *Config) (map[string][]*packages.Package, []diagnostics.Diagnostic, error) {
+	fSet := config.Fset
+	if fSet == nil {
+		fSet = token.NewFileSet()
+	}
+
+	builds := map[string][]*packages.Package{}
+	skipped := []diagnostics.Diagnostic{}
+	for _, bc := range config.BuildConfigs {
+		if _, exists := builds[bc.Name]; exists {
+			return nil, nil, fmt.Errorf(`build configuration %q was given more than once`, bc.Name)
+		}
+
+		c := bc.apply(*config)
+		c.Fset = fSet
+		ps, buildSkipped, err := Read(c)
+		if err != nil {
+			return nil, nil, fmt.Errorf(`build configuration %q: %w`, bc.Name, err)
+		}
+
+		for _, d := range buildSkipped {
+			d.Name = bc.Name + ` ` + d.Name
+			skipped = append(skipped, d)
+		}
+		builds[bc.Name] = ps
+	}
+	return builds, skipped, nil
+}
+
+// goEnvNames are the environment variables of the Go toolchain,
+// other than the target, that change how packages are built.
+var goEnvNames = []string{`GOVERSION`, `CGO_ENABLED`, `GOFLAGS`, `GOEXPERIMENT`}
+
+// GoEnv gets the environment of the Go toolchain that the packages are
+// read with for the given config, e.g. `GOVERSION=go1.24.0` and
+// `CGO_ENABLED=1`. These are the values `go env` reports in the config's
+// directory, which may differ from the values this tool was built with,
+// e.g. when a toolchain is selected by the module or when cgo is disabled
+// by default for a different target.
+func GoEnv(config *Config) ([]string, error) {
+	cfg := config.toParseConfig()
+	ctx := cfg.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd := exec.CommandContext(ctx, `go`, append([]string{`env`}, goEnvNames...)...)
+	cmd.Dir = cfg.Dir
+	cmd.Env = cfg.Env
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf(`failed to get the Go environment: %w`, err)
+	}
+	values := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if len(values) != len(goEnvNames) {
+		return nil, fmt.Errorf(`failed to get the Go environment: expected %d values but got %d`, len(goEnvNames), len(values))
+	}
+	env := make([]string, len(goEnvNames))
+	for i, name := range goEnvNames {
+		env[i] = name + `=` + strings.TrimSpace(values[i])
+	}
+	return env, nil
+}
+
+// workspacePatterns gets the patterns for all the packages
+// in every module used by the given workspace.
+func workspacePatterns(work *modfile.WorkFile) []string {
+	patterns := make([]string, 0, len(work.Use))
+	for _, use := range work.Use {
+		rel := filepath.ToSlash(filepath.Clean(use.Path))
+		if !strings.HasPrefix(rel, `.`) && !filepath.IsAbs(rel) {
+			rel = `./` + rel
+		}
+		patterns = append(patterns, rel+`/...`)
+	}
+	return patterns
+}
+
+// workspaceEnv gets the given environment, or the current environment if
+// nil, for reading in workspace mode. The `-mod` flag isn't allowed in
+// workspace mode so it is removed from GOFLAGS.
+func workspaceEnv(env []string) []string {
+	if env == nil {
+		env = os.Environ()
+	}
+	result := make([]string, 0, len(env))
+	for _, e := range env {
+		if flags, ok := strings.CutPrefix(e, `GOFLAGS=`); ok {
+			fields := strings.Fields(flags)
+			fields = slices.DeleteFunc(fields, func(flag string) bool {
+				return strings.HasPrefix(flag, `-mod=`)
+			})
+			e = `GOFLAGS=` + strings.Join(fields, ` `)
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// readWorkspace reads the go.work file in the given directory.
+// Returns nil if there is no go.work file in the directory.
+func readWorkspace(dir string) (*modfile.WorkFile, error) {
+	path := filepath.Join(dir, `go.work`)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
 		return nil, err
 	}
-	return ps, nil
+	return modfile.ParseWork(path, data, nil)
+}
+
+// writeModulesWorkspace writes a go.work file to the given path that uses
+// the module for the given directory and each of the given module directories.
+// If the directory has a go.work file, the modules and replacements from
+// that workspace are used instead of the directory's module.
+func writeModulesWorkspace(path, dir string, modules []string) error {
+	abs := func(base, p string) string {
+		if filepath.IsAbs(p) {
+			return filepath.Clean(p)
+		}
+		return filepath.Join(base, p)
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	existing, err := readWorkspace(dir)
+	if err != nil {
+		return err
+	}
+
+	work := &modfile.WorkFile{Syntax: &modfile.FileSyntax{}}
+	uses := []string{}
+	goVersion := ``
+	if existing != nil {
+		for _, use := range existing.Use {
+			uses = append(uses, abs(dir, use.Path))
+		}
+		for _, r := range existing.Replace {
+			newPath := r.New.Path
+			if len(r.New.Version) <= 0 {
+				newPath = abs(dir, newPath)
+			}
+			if err := work.AddReplace(r.Old.Path, r.Old.Version, newPath, r.New.Version); err != nil {
+				return err
+			}
+		}
+		if existing.Go != nil {
+			goVersion = existing.Go.Version
+		}
+	} else {
+		root, err := moduleRoot(dir)
+		if err != nil {
+			return err
+		}
+		uses = append(uses, root)
+	}
+	for _, module := range modules {
+		module, err := filepath.Abs(module)
+		if err != nil {
+			return err
+		}
+		uses = append(uses, module)
+	}
+
+	// The workspace must have a Go version at least as new as every module.
+	for _, use := range uses {
+		if err := work.AddUse(filepath.ToSlash(use), ``); err != nil {
+			return err
+		}
+		v, err := moduleGoVersion(use)
+		if err != nil {
+			return err
+		}
+		if len(v) > 0 && (len(goVersion) <= 0 || version.Compare(`go`+v, `go`+goVersion) > 0) {
+			goVersion = v
+		}
+	}
+	if len(goVersion) > 0 {
+		if err := work.AddGoStmt(goVersion); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, modfile.Format(work.Syntax), 0o644)
+}
+
+// addFileModules sets the module for any package read from files,
+// e.g. `main.go`, since those are loaded as `command-line-arguments`
+// without a module even when the files are in a module.
+// A package whose files aren't in any module is left without a module.
+func addFileModules(ps []*packages.Package) error {
+	for _, pkg := range ps {
+		if pkg.Module != nil || pkg.PkgPath != `command-line-arguments` || len(pkg.GoFiles) <= 0 {
+			continue
+		}
+		dir, err := moduleRoot(filepath.Dir(pkg.GoFiles[0]))
+		if err != nil {
+			continue
+		}
+		goMod := filepath.Join(dir, `go.mod`)
+		data, err := os.ReadFile(goMod)
+		if err != nil {
+			return err
+		}
+		pkg.Module = &packages.Module{
+			Path:  modfile.ModulePath(data),
+			Dir:   dir,
+			GoMod: goMod,
+		}
+	}
+	return nil
+}
+
+// moduleRoot finds the directory with the go.mod file for the given directory.
+func moduleRoot(dir string) (string, error) {
+	for cur := dir; ; {
+		if _, err := os.Stat(filepath.Join(cur, `go.mod`)); err == nil {
+			return cur, nil
+		}
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			return ``, fmt.Errorf(`no go.mod file found for %s`, dir)
+		}
+		cur = parent
+	}
+}
+
+// moduleGoVersion reads the Go version from the go.mod file in the given
+// module directory. Returns an empty version if the go.mod has no version.
+func moduleGoVersion(dir string) (string, error) {
+	path := filepath.Join(dir, `go.mod`)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ``, err
+	}
+	mod, err := modfile.ParseLax(path, data, nil)
+	if err != nil {
+		return ``, err
+	}
+	if mod.Go == nil {
+		return ``, nil
+	}
+	return mod.Go.Version, nil
 }
 
 func recoverError(r any) error {
@@ -52,7 +366,10 @@ func allPackageErrors(ps []*packages.Package) error {
 			errs = append(errs, err)
 		}
 	})
+	return joinErrors(errs)
+}
 
+func joinErrors(errs []error) error {
 	switch len(errs) {
 	case 0:
 		return nil
@@ -62,3 +379,39 @@ func allPackageErrors(ps []*packages.Package) error {
 		return errors.Join(errs...)
 	}
 }
+
+// skipBroken removes any root package that has errors or depends on a
+// package with errors. Since the dependencies of a package are only reached
+// through the root packages, removing the broken roots removes all the
+// broken packages. A diagnostic is returned for every broken package.
+func skipBroken(ps []*packages.Package) ([]*packages.Package, []diagnostics.Diagnostic) {
+	broken := map[*packages.Package]bool{}
+	skipped := []diagnostics.Diagnostic{}
+	packages.Visit(ps, nil, func(pkg *packages.Package) {
+		errs := []error{}
+		for _, err := range pkg.Errors {
+			errs = append(errs, err)
+		}
+		for _, path := range slices.Sorted(maps.Keys(pkg.Imports)) {
+			if imp := pkg.Imports[path]; broken[imp] {
+				errs = append(errs, fmt.Errorf(`depends on broken package %s`, imp.PkgPath))
+			}
+		}
+		if len(errs) > 0 {
+			broken[pkg] = true
+			skipped = append(skipped, diagnostics.Diagnostic{
+				Phase: diagnostics.Load,
+				Name:  pkg.PkgPath,
+				Err:   joinErrors(errs),
+			})
+		}
+	})
+
+	kept := make([]*packages.Package, 0, len(ps))
+	for _, pkg := range ps {
+		if !broken[pkg] {
+			kept = append(kept, pkg)
+		}
+	}
+	return kept, skipped
+}
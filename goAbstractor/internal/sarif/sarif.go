@@ -0,0 +1,303 @@
+package sarif
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/deadCode"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/smells"
+)
+
+const (
+	schema  = `https://json.schemastore.org/sarif-2.1.0.json`
+	version = `2.1.0`
+	toolURI = `https://github.com/MSUSEL/msusel-tdmetrics-go`
+
+	// srcRoot is the base identifier that the artifact locations are relative to.
+	srcRoot = `%SRCROOT%`
+)
+
+// Levels of the results.
+const (
+	LevelNote    = `note`
+	LevelWarning = `warning`
+)
+
+// Rule identifiers for the findings that aren't smells.
+const (
+	RuleDeadCode = `deadCode`
+	RuleCycle    = `dependencyCycle`
+)
+
+// Log is a Static Analysis Results Interchange Format (SARIF) 2.1.0 log
+// with a single run of the abstractor, such that the findings about the
+// abstracted project, e.g. smells, dead code, and cycles, can be shown
+// by tools, such as code reviews, that read SARIF.
+type Log struct {
+	root    string
+	modules []string
+	rules   []*Rule
+	results []*Result
+}
+
+// Rule is the description of a kind of finding.
+type Rule struct {
+	ID          string
+	Description string
+	Level       string
+}
+
+// Result is a finding of a rule at a location.
+type Result struct {
+	RuleID  string
+	Level   string
+	Message string
+	Loc     *Location
+
+	// Related are other locations involved in the finding,
+	// e.g. the other declarations in a cycle.
+	Related []*Location
+
+	// Properties are the metrics that are evidence of the finding.
+	Properties map[string]float64
+}
+
+// Location is a file and line in the abstracted project.
+type Location struct {
+	URI     string
+	Line    int
+	Message string
+
+	// Range is the lines and columns of the location,
+	// which may be zero if the range isn't known.
+	Range locs.Range
+}
+
+// New creates a new SARIF log for the given project.
+//
+// The root is the directory that was abstracted and is used as the
+// base of the artifact locations. The file paths of the locations are
+// made relative to the module the file is in, so the root is expected
+// to be the directory of that module.
+func New(proj constructs.Project, root string) *Log {
+	l := &Log{rules: []*Rule{}, results: []*Result{}}
+	if len(root) > 0 {
+		if abs, err := filepath.Abs(root); err == nil {
+			l.root = (&url.URL{Scheme: `file`, Path: filepath.ToSlash(abs) + `/`}).String()
+		}
+	}
+	for pkg := range proj.Packages().Enumerate().Seq() {
+		if mod := pkg.Module(); pkg.EntryPoint() && len(mod) > 0 && !slices.Contains(l.modules, mod) {
+			l.modules = append(l.modules, mod)
+		}
+	}
+	// Check the longest module paths first for nested modules.
+	slices.SortFunc(l.modules, func(a, b string) int { return len(b) - len(a) })
+	return l
+}
+
+func (l *Log) addRule(id, description, level string) {
+	if !slices.ContainsFunc(l.rules, func(r *Rule) bool { return r.ID == id }) {
+		l.rules = append(l.rules, &Rule{ID: id, Description: description, Level: level})
+	}
+}
+
+// location creates the location of the given loc, or nil if
+// the loc doesn't have a file, e.g. for a built-in declaration.
+func (l *Log) location(loc locs.Loc, message string) *Location {
+	if utils.IsNil(loc) {
+		return nil
+	}
+	_, file, line := loc.Info()
+	if len(file) <= 0 {
+		return nil
+	}
+	for _, mod := range l.modules {
+		if rel, found := strings.CutPrefix(file, mod+`/`); found {
+			file = rel
+			break
+		}
+	}
+	return &Location{URI: file, Line: line, Message: message, Range: loc.Range()}
+}
+
+// AddSmells adds the code smells as results with a rule for each kind of smell.
+func (l *Log) AddSmells(r *smells.Report) *Log {
+	for _, f := range r.Findings {
+		id := `smells/` + string(f.Smell)
+		l.addRule(id, smellDescription(f.Smell), LevelWarning)
+
+		parts := []string{}
+		props := map[string]float64{}
+		for _, e := range f.Evidence {
+			props[e.Metric] = e.Value
+			part := e.Metric + ` is ` + formatValue(e.Value)
+			if e.Threshold != 0 {
+				limit := `at least`
+				if f.Smell == smells.GodObject && e.Metric == `tcc` {
+					limit = `less than`
+				}
+				part += ` (` + limit + ` ` + formatValue(e.Threshold) + `)`
+			}
+			parts = append(parts, part)
+		}
+		message := fmt.Sprintf(`%s has the %s smell: %s`, f.Name, f.Smell, strings.Join(parts, `, `))
+		if len(f.Related) > 0 {
+			message += `, related to ` + strings.Join(f.Related, `, `)
+		}
+
+		l.results = append(l.results, &Result{
+			RuleID:     id,
+			Level:      LevelWarning,
+			Message:    message + `.`,
+			Loc:        l.location(f.Loc, ``),
+			Properties: props,
+		})
+	}
+	return l
+}
+
+func smellDescription(smell smells.Smell) string {
+	switch smell {
+	case smells.GodObject:
+		return `An object that is complex, uses the data of other objects, and has low cohesion.`
+	case smells.DataClass:
+		return `An object with data whose methods are mostly getters and setters.`
+	case smells.FeatureEnvy:
+		return `A method that accesses the fields of another object more than its own.`
+	case smells.LongMethod:
+		return `A method with many lines of code.`
+	case smells.LongParameterList:
+		return `A method with many parameters.`
+	case smells.ShotgunSurgery:
+		return `A method invoked from many methods in many objects.`
+	}
+	return string(smell)
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', 3, 64)
+}
+
+// AddDeadCode adds the dead declarations, methods, and values as results.
+func (l *Log) AddDeadCode(r *deadCode.Report) *Log {
+	for _, p := range r.Packages {
+		for _, e := range p.Entries {
+			l.addRule(RuleDeadCode, `A declaration that is unreachable from the roots of the project.`, LevelNote)
+			props := map[string]float64{}
+			if e.LineCount > 0 {
+				props[`lineCount`] = float64(e.LineCount)
+			}
+			l.results = append(l.results, &Result{
+				RuleID:     RuleDeadCode,
+				Level:      LevelNote,
+				Message:    fmt.Sprintf(`The %s %s.%s is dead code.`, e.Kind, p.Path, e.Name),
+				Loc:        l.location(e.Loc, ``),
+				Properties: props,
+			})
+		}
+	}
+	return l
+}
+
+// AddCycles adds the dependency cycles as results located at the first
+// declaration in each cycle with the other declarations as related locations.
+func (l *Log) AddCycles(cycles []*constructs.Cycle) *Log {
+	for _, c := range cycles {
+		l.addRule(RuleCycle, `A group of declarations that all depend on each other.`, LevelWarning)
+		names := make([]string, len(c.Declarations))
+		related := []*Location{}
+		for i, decl := range c.Declarations {
+			names[i] = constructs.QualifiedName(decl)
+			if i > 0 {
+				if loc := l.location(decl.Location(), names[i]); loc != nil {
+					related = append(related, loc)
+				}
+			}
+		}
+		l.results = append(l.results, &Result{
+			RuleID: RuleCycle,
+			Level:  LevelWarning,
+			Message: fmt.Sprintf(`%d declarations depend on each other: %s.`,
+				len(c.Declarations), strings.Join(names, `, `)),
+			Loc:        l.location(c.Declarations[0].Location(), names[0]),
+			Related:    related,
+			Properties: map[string]float64{`size`: float64(len(c.Declarations))},
+		})
+	}
+	return l
+}
+
+func (l *Log) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	driver := jsonify.NewMap().
+		Add(ctx, `name`, `goAbstractor`).
+		Add(ctx, `informationUri`, toolURI).
+		Add(ctx, `rules`, l.rules)
+	run := jsonify.NewMap().
+		Add(ctx, `tool`, jsonify.NewMap().Add(ctx, `driver`, driver)).
+		Add(ctx, `results`, l.results)
+	if len(l.root) > 0 {
+		run.Add(ctx, `originalUriBaseIds`, jsonify.NewMap().
+			Add(ctx, srcRoot, jsonify.NewMap().Add(ctx, `uri`, l.root)))
+	}
+	return jsonify.NewMap().
+		Add(ctx, `$schema`, schema).
+		Add(ctx, `version`, version).
+		Add(ctx, `runs`, jsonify.NewList().Append(ctx, run))
+}
+
+func (r *Rule) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `id`, r.ID).
+		Add(ctx, `shortDescription`, jsonify.NewMap().Add(ctx, `text`, r.Description)).
+		Add(ctx, `defaultConfiguration`, jsonify.NewMap().Add(ctx, `level`, r.Level))
+}
+
+func (r *Result) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	m := jsonify.NewMap().
+		Add(ctx, `ruleId`, r.RuleID).
+		Add(ctx, `level`, r.Level).
+		Add(ctx, `message`, jsonify.NewMap().Add(ctx, `text`, r.Message))
+	if r.Loc != nil {
+		m.Add(ctx, `locations`, []*Location{r.Loc})
+	}
+	m.AddNonZero(ctx, `relatedLocations`, r.Related)
+	if len(r.Properties) > 0 {
+		props := jsonify.NewMap()
+		for _, key := range utils.SortedKeys(r.Properties) {
+			props.Add(ctx, key, r.Properties[key])
+		}
+		m.Add(ctx, `properties`, props)
+	}
+	return m
+}
+
+func (loc *Location) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	physical := jsonify.NewMap().
+		Add(ctx, `artifactLocation`, jsonify.NewMap().
+			Add(ctx, `uri`, loc.URI).
+			Add(ctx, `uriBaseId`, srcRoot))
+	if loc.Line > 0 {
+		region := jsonify.NewMap().Add(ctx, `startLine`, loc.Line)
+		if ctx.IncludeRanges() && !loc.Range.IsZero() {
+			region.Add(ctx, `startColumn`, loc.Range.StartColumn).
+				Add(ctx, `endLine`, loc.Range.EndLine).
+				Add(ctx, `endColumn`, loc.Range.EndColumn)
+		}
+		physical.Add(ctx, `region`, region)
+	}
+	m := jsonify.NewMap().Add(ctx, `physicalLocation`, physical)
+	if len(loc.Message) > 0 {
+		m.Add(ctx, `message`, jsonify.NewMap().Add(ctx, `text`, loc.Message))
+	}
+	return m
+}
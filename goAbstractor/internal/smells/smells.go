@@ -0,0 +1,434 @@
+package smells
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/locs"
+)
+
+// Smell is the kind of code smell that was found.
+type Smell string
+
+const (
+	GodObject         Smell = `godObject`
+	DataClass         Smell = `dataClass`
+	FeatureEnvy       Smell = `featureEnvy`
+	LongMethod        Smell = `longMethod`
+	LongParameterList Smell = `longParameterList`
+	ShotgunSurgery    Smell = `shotgunSurgery`
+)
+
+// Thresholds are the limits used to determine if a construct has a smell.
+// A metric meets a threshold when it is at least the threshold, except
+// for the god object cohesion which must be less than the threshold.
+type Thresholds struct {
+	// GodComplexity is the minimum sum of the complexity of an object's
+	// methods (WMC) for the object to be a god object.
+	GodComplexity int
+
+	// GodForeignData is the minimum number of fields of other objects
+	// accessed by an object's methods (ATFD) for the object to be a god object.
+	GodForeignData int
+
+	// GodCohesion is the tight class cohesion (TCC), the ratio of pairs of
+	// an object's methods that access a shared field, that an object must
+	// be below to be a god object.
+	GodCohesion float64
+
+	// DataClassMethods is the minimum number of methods
+	// for an object to be a data class.
+	DataClassMethods int
+
+	// DataClassAccessors is the minimum ratio of an object's methods that
+	// are getters or setters for the object to be a data class.
+	DataClassAccessors float64
+
+	// EnvyAccesses is the minimum number of fields of one other object that
+	// a method accesses for the method to have feature envy. The method must
+	// also access more fields of that object than fields of its receiver.
+	EnvyAccesses int
+
+	// LongMethodLines is the minimum number of lines with code
+	// for a method to be a long method.
+	LongMethodLines int
+
+	// LongParameters is the minimum number of parameters
+	// for a method to have a long parameter list.
+	LongParameters int
+
+	// ShotgunMethods is the minimum number of methods that invoke a method
+	// (CM) for a change to the method to be a shotgun surgery.
+	ShotgunMethods int
+
+	// ShotgunOwners is the minimum number of objects, or packages for
+	// functions without a receiver, that the invoking methods are in (CC)
+	// for a change to the method to be a shotgun surgery.
+	ShotgunOwners int
+}
+
+// DefaultThresholds gets the default thresholds, based on
+// the detection strategies by Lanza and Marinescu.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		GodComplexity:      47,
+		GodForeignData:     5,
+		GodCohesion:        1.0 / 3.0,
+		DataClassMethods:   2,
+		DataClassAccessors: 0.75,
+		EnvyAccesses:       3,
+		LongMethodLines:    50,
+		LongParameters:     5,
+		ShotgunMethods:     7,
+		ShotgunOwners:      5,
+	}
+}
+
+// Report is the code smells found in the declarations of the read packages.
+type Report struct {
+	Findings []*Finding
+}
+
+// Finding is a code smell found in an object or method.
+type Finding struct {
+	Smell Smell
+
+	// Name is the qualified name of the object or method with the smell,
+	// e.g. `example.com/foo.Bar` or `example.com/foo.Bar.Baz`.
+	Name string
+
+	Loc locs.Loc
+
+	// Evidence are the metrics that met the thresholds for the smell.
+	Evidence []*Evidence
+
+	// Related are the qualified names of the other declarations
+	// involved in the smell, e.g. the object a method envies
+	// or the methods invoking a method.
+	Related []string
+}
+
+// Evidence is a metric that met a threshold for a smell.
+type Evidence struct {
+	Metric    string
+	Value     float64
+	Threshold float64
+}
+
+// New finds the code smells in the given project using the given
+// thresholds. Only the objects and methods that are measured are checked,
+// such that methods in test files don't smell or count as callers by default.
+func New(proj constructs.Project, th Thresholds, measure constructs.Measure) *Report {
+	d := &detector{
+		th:      th,
+		measure: measure,
+		report:  &Report{},
+		callers: map[constructs.Method]map[constructs.Declaration]bool{},
+	}
+
+	for obj := range proj.Objects().Enumerate().Seq() {
+		if d.include(obj) {
+			d.object(obj)
+		}
+	}
+
+	for m := range proj.Methods().Enumerate().Seq() {
+		if d.include(m) && m.IsNamed() && !utils.IsNil(m.Metrics()) {
+			d.method(m)
+			d.addCallers(m, m.Metrics())
+		}
+	}
+	for v := range proj.Values().Enumerate().Seq() {
+		if d.include(v) {
+			d.addCallers(v, v.Metrics())
+		}
+	}
+	d.shotgunSurgery()
+
+	slices.SortFunc(d.report.Findings, func(a, b *Finding) int {
+		if c := strings.Compare(string(a.Smell), string(b.Smell)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return d.report
+}
+
+// include determines if the declaration is measured and in a read package.
+func (d *detector) include(decl constructs.Declaration) bool {
+	return !decl.Duplicate() && decl.Package().EntryPoint() && d.measure.Measured(decl)
+}
+
+type detector struct {
+	th      Thresholds
+	measure constructs.Measure
+	report  *Report
+	callers map[constructs.Method]map[constructs.Declaration]bool
+}
+
+func (d *detector) add(smell Smell, decl constructs.Declaration, evidence []*Evidence, related ...string) {
+	slices.Sort(related)
+	d.report.Findings = append(d.report.Findings, &Finding{
+		Smell:    smell,
+		Name:     constructs.QualifiedName(decl),
+		Loc:      decl.Location(),
+		Evidence: evidence,
+		Related:  related,
+	})
+}
+
+func (d *detector) object(obj constructs.Object) {
+	methods := []constructs.Method{}
+	for m := range obj.Methods().Enumerate().Seq() {
+		if !m.Duplicate() && d.measure.Measured(m) && !utils.IsNil(m.Metrics()) {
+			methods = append(methods, m)
+		}
+	}
+	d.godObject(obj, methods)
+	d.dataClass(obj, methods)
+}
+
+// godObject checks if the object is complex, uses the data of
+// other objects, and has low cohesion.
+func (d *detector) godObject(obj constructs.Object, methods []constructs.Method) {
+	complexity := 0
+	foreign := map[fieldRef]bool{}
+	used := make([]map[string]bool, len(methods))
+	for i, m := range methods {
+		complexity += m.Metrics().Complexity()
+		used[i] = map[string]bool{}
+		for _, f := range fieldAccesses(m.Metrics()) {
+			if f.owner == obj {
+				used[i][f.name] = true
+			} else {
+				foreign[f] = true
+			}
+		}
+	}
+
+	pairs, shared := 0, 0
+	for i := range used {
+		for j := i + 1; j < len(used); j++ {
+			pairs++
+			for name := range used[i] {
+				if used[j][name] {
+					shared++
+					break
+				}
+			}
+		}
+	}
+	cohesion := 1.0
+	if pairs > 0 {
+		cohesion = float64(shared) / float64(pairs)
+	}
+
+	if complexity >= d.th.GodComplexity &&
+		len(foreign) >= d.th.GodForeignData &&
+		cohesion < d.th.GodCohesion {
+		d.add(GodObject, obj, []*Evidence{
+			{Metric: `wmc`, Value: float64(complexity), Threshold: float64(d.th.GodComplexity)},
+			{Metric: `atfd`, Value: float64(len(foreign)), Threshold: float64(d.th.GodForeignData)},
+			{Metric: `tcc`, Value: cohesion, Threshold: d.th.GodCohesion},
+		})
+	}
+}
+
+// dataClass checks if the object has data and is mostly getters and setters.
+func (d *detector) dataClass(obj constructs.Object, methods []constructs.Method) {
+	if utils.IsNil(obj.Data()) || len(obj.Data().Fields()) <= 0 ||
+		len(methods) <= 0 || len(methods) < d.th.DataClassMethods {
+		return
+	}
+	accessors := 0
+	for _, m := range methods {
+		if m.Metrics().Getter() || m.Metrics().Setter() {
+			accessors++
+		}
+	}
+	ratio := float64(accessors) / float64(len(methods))
+	if ratio >= d.th.DataClassAccessors {
+		d.add(DataClass, obj, []*Evidence{
+			{Metric: `methods`, Value: float64(len(methods)), Threshold: float64(d.th.DataClassMethods)},
+			{Metric: `accessors`, Value: ratio, Threshold: d.th.DataClassAccessors},
+		})
+	}
+}
+
+func (d *detector) method(m constructs.Method) {
+	if lines := m.Metrics().CodeCount(); lines >= d.th.LongMethodLines {
+		d.add(LongMethod, m, []*Evidence{
+			{Metric: `codeCount`, Value: float64(lines), Threshold: float64(d.th.LongMethodLines)},
+			{Metric: `complexity`, Value: float64(m.Metrics().Complexity())},
+		})
+	}
+
+	if params := len(m.Signature().Params()); params >= d.th.LongParameters {
+		d.add(LongParameterList, m, []*Evidence{
+			{Metric: `parameters`, Value: float64(params), Threshold: float64(d.th.LongParameters)},
+		})
+	}
+
+	if m.HasReceiver() {
+		d.featureEnvy(m)
+	}
+}
+
+// featureEnvy checks if the method accesses the fields of
+// another object more than the fields of its receiver.
+func (d *detector) featureEnvy(m constructs.Method) {
+	counts := map[constructs.Object]int{}
+	for _, f := range fieldAccesses(m.Metrics()) {
+		counts[f.owner]++
+	}
+	own := counts[m.Receiver()]
+
+	var envied constructs.Object
+	for obj, count := range counts {
+		if obj == m.Receiver() || !d.include(obj) {
+			continue
+		}
+		if envied == nil || count > counts[envied] ||
+			(count == counts[envied] && constructs.QualifiedName(obj) < constructs.QualifiedName(envied)) {
+			envied = obj
+		}
+	}
+
+	if envied != nil && counts[envied] >= d.th.EnvyAccesses && counts[envied] > own {
+		d.add(FeatureEnvy, m, []*Evidence{
+			{Metric: `foreignAccesses`, Value: float64(counts[envied]), Threshold: float64(d.th.EnvyAccesses)},
+			{Metric: `ownAccesses`, Value: float64(own)},
+		}, constructs.QualifiedName(envied))
+	}
+}
+
+// addCallers records the given declaration as a caller
+// of the methods that it invokes.
+func (d *detector) addCallers(caller constructs.Declaration, metrics constructs.Metrics) {
+	if utils.IsNil(metrics) {
+		return
+	}
+	for c := range metrics.Invokes().Enumerate().Seq() {
+		if m := invoked(c); m != nil && m != caller && d.include(m) {
+			callers, has := d.callers[m]
+			if !has {
+				callers = map[constructs.Declaration]bool{}
+				d.callers[m] = callers
+			}
+			callers[caller] = true
+		}
+	}
+}
+
+// shotgunSurgery checks for methods that are invoked by many methods
+// in many objects, such that a change to the method may require
+// changes to all of those objects.
+func (d *detector) shotgunSurgery() {
+	for m, callers := range d.callers {
+		owners := map[string]bool{}
+		related := []string{}
+		for caller := range callers {
+			owners[owner(caller)] = true
+			related = append(related, constructs.QualifiedName(caller))
+		}
+		if len(callers) >= d.th.ShotgunMethods && len(owners) >= d.th.ShotgunOwners {
+			d.add(ShotgunSurgery, m, []*Evidence{
+				{Metric: `cm`, Value: float64(len(callers)), Threshold: float64(d.th.ShotgunMethods)},
+				{Metric: `cc`, Value: float64(len(owners)), Threshold: float64(d.th.ShotgunOwners)},
+			}, related...)
+		}
+	}
+}
+
+// owner gets the qualified name of the receiver of a method,
+// otherwise the package path of the declaration.
+func owner(decl constructs.Declaration) string {
+	if m, ok := decl.(constructs.Method); ok && m.HasReceiver() {
+		return constructs.QualifiedName(m.Receiver())
+	}
+	return decl.Package().Path()
+}
+
+// invoked gets the method that the given invocation is of.
+func invoked(c constructs.Construct) constructs.Method {
+	switch t := c.(type) {
+	case constructs.Method:
+		return t
+	case constructs.MethodInst:
+		return t.Generic()
+	case constructs.Selection:
+		if obj := constructs.SelectedObject(t.Origin()); !utils.IsNil(obj) {
+			for m := range obj.Methods().Enumerate().Seq() {
+				if m.Name() == t.Name() {
+					return m
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fieldRef is a field in an object.
+type fieldRef struct {
+	owner constructs.Object
+	name  string
+}
+
+// fieldAccesses gets the fields read or written in the given metrics.
+func fieldAccesses(metrics constructs.Metrics) []fieldRef {
+	refs := []fieldRef{}
+	add := func(c constructs.Construct) {
+		sel, ok := c.(constructs.Selection)
+		if !ok {
+			return
+		}
+		obj := constructs.SelectedObject(sel.Origin())
+		if utils.IsNil(obj) || utils.IsNil(obj.Data()) {
+			return
+		}
+		for _, f := range obj.Data().Fields() {
+			if f.Name() == sel.Name() {
+				ref := fieldRef{owner: obj, name: f.Name()}
+				if !slices.Contains(refs, ref) {
+					refs = append(refs, ref)
+				}
+				return
+			}
+		}
+	}
+	for c := range metrics.Reads().Enumerate().Seq() {
+		add(c)
+	}
+	for c := range metrics.Writes().Enumerate().Seq() {
+		add(c)
+	}
+	return refs
+}
+
+func (r *Report) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	if r == nil {
+		return nil
+	}
+	return jsonify.New(ctx, r.Findings)
+}
+
+func (f *Finding) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `smell`, string(f.Smell)).
+		Add(ctx, `name`, f.Name).
+		AddNonZero(ctx, `loc`, f.Loc).
+		AddNonZeroIf(ctx, ctx.IncludeRanges(), `range`, locs.RangeOf(f.Loc)).
+		Add(ctx, `evidence`, f.Evidence).
+		AddNonZero(ctx, `related`, f.Related)
+}
+
+func (e *Evidence) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `metric`, e.Metric).
+		Add(ctx, `value`, e.Value).
+		AddNonZero(ctx, `threshold`, e.Threshold)
+}
@@ -0,0 +1,217 @@
+package stats
+
+import (
+	"runtime"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/Snow-Gremlin/goToolbox/utils"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/progress"
+)
+
+// sampleInterval is how often the heap is sampled to find the peak heap.
+const sampleInterval = 10 * time.Millisecond
+
+// heapMetric is the runtime metric for the bytes of live and
+// not yet swept objects in the heap.
+const heapMetric = `/memory/classes/heap/objects:bytes`
+
+// Stats is the timing and memory statistics of an abstraction.
+type Stats struct {
+	// Wall is the total wall time of the abstraction.
+	Wall time.Duration
+
+	// Allocs is the total bytes allocated and Mallocs is the total
+	// number of objects allocated during the abstraction.
+	Allocs  uint64
+	Mallocs uint64
+
+	// PeakHeap is the largest number of bytes sampled in the heap.
+	PeakHeap uint64
+
+	// Iterations is the number of iterations of the resolver's loop
+	// that repeats phases until nothing changes.
+	Iterations int
+
+	// Phases are the statistics for each phase in the order
+	// that each phase was first started.
+	Phases []*Phase
+
+	// Kinds is the number of constructs, not including duplicates,
+	// in the project for each kind of construct.
+	Kinds map[string]int
+}
+
+// Phase is the timing and memory statistics of one phase.
+// Phases that are run more than once, such as the phases in the resolver's
+// loop, are the sum of every time the phase was run.
+type Phase struct {
+	Phase    diagnostics.Phase
+	Runs     int
+	Wall     time.Duration
+	Allocs   uint64
+	Mallocs  uint64
+	PeakHeap uint64
+}
+
+// Recorder records the statistics of an abstraction. The phases are
+// determined from the progress of the abstraction passed into Observe.
+type Recorder struct {
+	lock   sync.Mutex
+	stats  *Stats
+	phases map[diagnostics.Phase]*Phase
+
+	cur        *Phase
+	curStart   time.Time
+	curAllocs  uint64
+	curMallocs uint64
+
+	start   time.Time
+	allocs  uint64
+	mallocs uint64
+	sample  []metrics.Sample
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewRecorder creates a new recorder and starts sampling the heap.
+// Finish must be called to stop the sampling.
+func NewRecorder() *Recorder {
+	r := &Recorder{
+		stats:   &Stats{Kinds: map[string]int{}},
+		phases:  map[diagnostics.Phase]*Phase{},
+		sample:  []metrics.Sample{{Name: heapMetric}},
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	r.start = time.Now()
+	r.allocs, r.mallocs = readAllocs()
+	go r.run()
+	return r
+}
+
+func readAllocs() (allocs, mallocs uint64) {
+	ms := &runtime.MemStats{}
+	runtime.ReadMemStats(ms)
+	return ms.TotalAlloc, ms.Mallocs
+}
+
+func (r *Recorder) run() {
+	defer close(r.stopped)
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.lock.Lock()
+			r.sampleHeap()
+			r.lock.Unlock()
+		}
+	}
+}
+
+// sampleHeap updates the peak heap of the abstraction and current phase.
+// The lock must be held while calling this.
+func (r *Recorder) sampleHeap() {
+	metrics.Read(r.sample)
+	if r.sample[0].Value.Kind() != metrics.KindUint64 {
+		return
+	}
+	heap := r.sample[0].Value.Uint64()
+	r.stats.PeakHeap = max(r.stats.PeakHeap, heap)
+	if r.cur != nil {
+		r.cur.PeakHeap = max(r.cur.PeakHeap, heap)
+	}
+}
+
+// Observe records the given progress. When the progress is for a different
+// phase than the current phase, the current phase is ended and the
+// new phase is started. This can be used as a progress handle.
+func (r *Recorder) Observe(p progress.Progress) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.stats.Iterations = max(r.stats.Iterations, p.Iteration)
+	if r.cur != nil && r.cur.Phase == p.Phase {
+		return
+	}
+
+	r.endPhase()
+	ph, has := r.phases[p.Phase]
+	if !has {
+		ph = &Phase{Phase: p.Phase}
+		r.phases[p.Phase] = ph
+		r.stats.Phases = append(r.stats.Phases, ph)
+	}
+	ph.Runs++
+	r.cur = ph
+	r.curStart = time.Now()
+	r.curAllocs, r.curMallocs = readAllocs()
+	r.sampleHeap()
+}
+
+// endPhase ends the current phase, if there is a current phase.
+// The lock must be held while calling this.
+func (r *Recorder) endPhase() {
+	if r.cur == nil {
+		return
+	}
+	r.sampleHeap()
+	allocs, mallocs := readAllocs()
+	r.cur.Wall += time.Since(r.curStart)
+	r.cur.Allocs += allocs - r.curAllocs
+	r.cur.Mallocs += mallocs - r.curMallocs
+	r.cur = nil
+}
+
+// Finish stops the recording and returns the statistics.
+// The construct kinds are counted from the given project,
+// which may be nil if the abstraction failed.
+func (r *Recorder) Finish(proj constructs.Project) *Stats {
+	close(r.stop)
+	<-r.stopped
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.endPhase()
+	allocs, mallocs := readAllocs()
+	r.stats.Wall = time.Since(r.start)
+	r.stats.Allocs = allocs - r.allocs
+	r.stats.Mallocs = mallocs - r.mallocs
+
+	if !utils.IsNil(proj) {
+		for c := range proj.Enumerate().Seq() {
+			if !c.Duplicate() {
+				r.stats.Kinds[string(c.Kind())]++
+			}
+		}
+	}
+	return r.stats
+}
+
+func (s *Stats) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `wallSeconds`, s.Wall.Seconds()).
+		Add(ctx, `allocBytes`, s.Allocs).
+		Add(ctx, `allocObjects`, s.Mallocs).
+		Add(ctx, `peakHeapBytes`, s.PeakHeap).
+		Add(ctx, `iterations`, s.Iterations).
+		AddNonZero(ctx, `phases`, s.Phases).
+		AddNonZero(ctx, `kinds`, s.Kinds)
+}
+
+func (p *Phase) ToJson(ctx *jsonify.Context) jsonify.Datum {
+	return jsonify.NewMap().
+		Add(ctx, `phase`, string(p.Phase)).
+		Add(ctx, `runs`, p.Runs).
+		Add(ctx, `wallSeconds`, p.Wall.Seconds()).
+		Add(ctx, `allocBytes`, p.Allocs).
+		Add(ctx, `allocObjects`, p.Mallocs).
+		Add(ctx, `peakHeapBytes`, p.PeakHeap)
+}
@@ -0,0 +1,79 @@
+package stats_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/abstraction"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/progress"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/stats"
+)
+
+func Test_Stats_Recorder(t *testing.T) {
+	r := stats.NewRecorder()
+	r.Observe(progress.Progress{Phase: diagnostics.Load})
+	r.Observe(progress.Progress{Phase: diagnostics.Abstract, Done: 1, Total: 2})
+	r.Observe(progress.Progress{Phase: diagnostics.Abstract, Done: 2, Total: 2})
+	r.Observe(progress.Progress{Phase: diagnostics.References, Iteration: 1})
+	r.Observe(progress.Progress{Phase: diagnostics.Instantiations, Iteration: 1})
+	r.Observe(progress.Progress{Phase: diagnostics.References, Iteration: 2})
+	s := r.Finish(nil)
+
+	check.Equal(t, []string{
+		`load:1`, `abstract:1`, `references:2`, `instantiations:1`,
+	}).Assert(phaseRuns(s))
+	check.Equal(t, 2).Assert(s.Iterations)
+	check.Length(t, 0).Assert(s.Kinds)
+	check.True(t).Assert(s.Wall > 0)
+}
+
+func Test_Stats_Abstraction(t *testing.T) {
+	var s *abstraction.Stats
+	_, err := abstraction.Abstract(context.Background(), abstraction.Config{
+		Dir:      `../../../testData/go/test0013`,
+		Patterns: []string{`main.go`},
+		Stats:    func(st *abstraction.Stats) { s = st },
+	})
+	check.NoError(t).Require(err)
+	check.NotNil(t).Require(s)
+
+	check.Equal(t, []string{
+		`load:1`, `abstract:1`, `imports:1`, `receivers:1`, `references:3`,
+		`instantiations:2`, `duplicates:1`, `interfaces:1`, `inheritance:1`,
+		`deadCode:1`,
+	}).Assert(phaseRuns(s))
+	check.Equal(t, 2).Assert(s.Iterations)
+	check.Equal(t, map[string]int{
+		`abstract`:      3,
+		`argument`:      2,
+		`basic`:         1,
+		`field`:         4,
+		`interfaceDecl`: 3,
+		`interfaceDesc`: 4,
+		`method`:        5,
+		`metrics`:       5,
+		`object`:        3,
+		`package`:       1,
+		`selection`:     7,
+		`signature`:     3,
+		`structDesc`:    3,
+	}).Assert(s.Kinds)
+
+	check.True(t).Assert(s.Wall > 0)
+	check.True(t).Assert(s.Allocs > 0)
+	for _, p := range s.Phases {
+		check.True(t).Name(string(p.Phase)).Assert(p.Wall <= s.Wall)
+	}
+}
+
+func phaseRuns(s *stats.Stats) []string {
+	runs := make([]string, len(s.Phases))
+	for i, p := range s.Phases {
+		runs[i] = string(p.Phase) + `:` + strconv.Itoa(p.Runs)
+	}
+	return runs
+}
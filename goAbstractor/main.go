@@ -1,25 +1,101 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"runtime/debug"
+	"strings"
+	"time"
 
 	"github.com/Snow-Gremlin/goToolbox/argers/args"
 
-	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/abstraction"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstracted"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/cycles"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/deadCode"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
-	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/logger"
-	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/reader"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/sarif"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/smells"
 )
 
 type argObject struct {
+	abstractArgObject
+
 	ShowHelp bool   `args:"flag, h, help"`
-	Verbose  bool   `args:"flag, v, verbose"`
 	Minimize bool   `args:"flag, m, minimize"`
-	InPath   string `args:"i, in"`
 	OutPath  string `args:"o, out"`
+	Format   string `args:"f, format"`
+
+	TestMetrics bool `args:"flag, , testMetrics"`
+
+	ExcludeGenerated bool `args:"flag, g, excludeGenerated"`
+
+	AliveReasons   bool `args:"flag, , aliveReasons"`
+	DeadReport     bool `args:"flag, , deadReport"`
+	StableIDs      bool `args:"flag, , stableIDs"`
+	CKMetrics      bool `args:"flag, , ckMetrics"`
+	PackageMetrics bool `args:"flag, , packageMetrics"`
+	ExcludeStdlib  bool `args:"flag, , excludeStdlib"`
+	Cycles         bool `args:"flag, , cycles"`
+	Ranges         bool `args:"flag, , ranges"`
+
+	Progress bool `args:"flag, P, progress"`
+	Timeout  int  `args:", timeout"`
+	Stats    bool `args:"flag, S, stats"`
+}
+
+// abstractArgObject is the arguments for reading and abstracting a project,
+// which are shared by the commands that abstract a project. The argument
+// reader skips the embedded struct so it is added to the reader separately,
+// see processArgs.
+type abstractArgObject struct {
+	Verbose   bool   `args:"flag, v, verbose"`
+	LogLevel  string `args:", logLevel"`
+	LogGroups string `args:", logGroups"`
+	LogJson   bool   `args:"flag, , logJson"`
+	InPath    string `args:"i, in"`
+	Modules   string `args:"M, modules"`
+	Builds    string `args:"b, builds"`
+	Workers   int    `args:"w, workers"`
+	CacheDir  string `args:"c, cache"`
+	Tolerant  bool   `args:"flag, t, tolerant"`
+	Partial   bool   `args:"flag, p, partial"`
+	SkipDead  bool   `args:"flag, d, skipDead"`
+	Tests     bool   `args:"flag, T, tests"`
+
+	RootExported    string `args:"e, rootExported"`
+	RootsFile       string `args:"R, rootsFile"`
+	ReflectionRoots bool   `args:"flag, , reflectionRoots"`
+}
+
+// processArgs reads the given arguments into the given command's
+// arguments and the arguments for abstracting a project.
+func processArgs(arguments []string, ao any, abstract *abstractArgObject) error {
+	return args.New().Struct(ao).Struct(abstract).Process(hyphenated(arguments))
+}
+
+// hyphenatedFlags are the names the argument reader has for the long flags
+// named with a hyphen, which the reader doesn't allow in a name.
+var hyphenatedFlags = map[string]string{
+	`--dead-report`: `--deadReport`,
+}
+
+// hyphenated replaces any long flag named with a hyphen in the given
+// arguments with the name the argument reader has for the flag.
+func hyphenated(arguments []string) []string {
+	result := make([]string, len(arguments))
+	for i, arg := range arguments {
+		if name, has := hyphenatedFlags[arg]; has {
+			arg = name
+		}
+		result[i] = arg
+	}
+	return result
 }
 
 func main() {
@@ -31,9 +107,21 @@ func main() {
 		}
 	}()
 
+	if len(os.Args) > 1 && os.Args[1] == diffCommand {
+		runDiff(os.Args[2:])
+	}
+	if len(os.Args) > 1 && os.Args[1] == historyCommand {
+		runHistory(os.Args[2:])
+	}
+	if len(os.Args) > 1 && os.Args[1] == participationCommand {
+		runParticipation(os.Args[2:])
+	}
+	if len(os.Args) > 1 && os.Args[1] == smellsCommand {
+		runSmells(os.Args[2:])
+	}
+
 	ao := &argObject{}
-	in := args.New().Struct(ao)
-	if err := in.Process(os.Args[1:]); err != nil {
+	if err := processArgs(os.Args[1:], ao, &ao.abstractArgObject); err != nil {
 		fmt.Println(err.Error())
 		fmt.Println(`Use "-h" argument to show help.`)
 		os.Exit(1)
@@ -44,37 +132,140 @@ func main() {
 			`designed to be used in a design recovery and participation analysis`,
 			`of the Go project.`)
 		fmt.Println(os.Args[0], `<options> -i <inputPath> [ -o <outputPath> ]`)
+		fmt.Println(os.Args[0], diffCommand, `<options> <oldPath> <newPath>`)
+		fmt.Println(`  Compares two outputs, use "` + diffCommand + ` -h" to show its help.`)
+		fmt.Println(os.Args[0], historyCommand, `<options> -i <repoPath> [ -o <outputPath> ]`)
+		fmt.Println(`  Abstracts a git repository's history, use "` + historyCommand + ` -h" to show its help.`)
+		fmt.Println(os.Args[0], participationCommand, `<options> -i <inputPath> [ -o <outputPath> ]`)
+		fmt.Println(`  Computes the participation matrix, use "` + participationCommand + ` -h" to show its help.`)
+		fmt.Println(os.Args[0], smellsCommand, `<options> -i <inputPath> [ -o <outputPath> ]`)
+		fmt.Println(`  Finds the code smells, use "` + smellsCommand + ` -h" to show its help.`)
 		fmt.Println(`  --help|-h: Shows this help text.`)
-		fmt.Println(`  --verbose|-v: Indicates the abstraction process should`,
-			`output additional status information.`)
+		printAbstractHelp()
 		fmt.Println(`  --minimize|-m: Indicates the JSON output should be`,
 			`minimized instead of formatted.`)
-		fmt.Println(`  --in|-i: The input path to the directory of the project`,
-			`or package to read. The project directory should have a go.mod file.`)
 		fmt.Println(`  --out|-o: The output file path to write the JSON to.`,
 			`If not given, the JSON will be outputted to the console.`)
+		fmt.Println(`  --format|-f: The format of the output, either "json" for the`,
+			`abstraction or "sarif" for a SARIF 2.1.0 log of the findings, i.e. the`,
+			`smells with the default thresholds, dead code, and dependency cycles.`,
+			`The SARIF file paths are relative to the input path. If not given,`,
+			`the format is "json".`)
+		fmt.Println(`  --testMetrics: Indicates that the measurements of test code,`,
+			`such as complexity and line counts, should be outputted and that`,
+			`test code should be counted by the CK metrics, package metrics,`,
+			`and smells.`,
+			`By default only the usages of test code are outputted.`)
+		fmt.Println(`  --excludeGenerated|-g: Indicates that the measurements of`,
+			`generated code, files with a "// Code generated ... DO NOT EDIT."`,
+			`header, should not be outputted nor counted by the CK metrics,`,
+			`package metrics, and smells. Generated code is still tagged`,
+			`as generated and its usages are still outputted.`)
+		fmt.Println(`  --aliveReasons: Indicates that the reason each declaration`,
+			`was kept alive by the dead-code elimination should be outputted.`)
+		fmt.Println(`  --dead-report: Indicates that a report of every dead declaration,`,
+			`method, and value, and every unused field of an alive object, in the`,
+			`read packages, with its location and size metrics grouped by package,`,
+			`should be outputted.`)
+		fmt.Println(`  --stableIDs: Indicates that each package, declaration, and type`,
+			`description should output an "id" derived from its package path, nest,`,
+			`name, and type arguments, next to its index. The identifiers do not`,
+			`change when unrelated code changes, so they can be used to join`,
+			`constructs across abstractions of different versions of a project.`,
+			`Each basic type is outputted as an object with its name and "id".`)
+		fmt.Println(`  --ckMetrics: Indicates that the Chidamber and Kemerer metrics,`,
+			`WMC, CBO, RFC, LCOM1 through LCOM5, DIT, and NOC, should be outputted`,
+			`for each object and object instance in the read packages. Embedding an`,
+			`object and satisfying an interface are used in place of subclassing.`)
+		fmt.Println(`  --packageMetrics: Indicates that the afferent and efferent coupling,`,
+			`instability, abstractness, and distance from the main sequence should`,
+			`be outputted for each read package. The coupling is determined from the`,
+			`declarations that are actually used, not from the imports.`)
+		fmt.Println(`  --excludeStdlib: Indicates that the standard library packages`,
+			`should not be counted in the coupling of the package metrics.`)
+		fmt.Println(`  --cycles: Indicates that the dependency cycles should be outputted.`,
+			`A cycle is a group of declarations that all depend on each other via`,
+			`reads, writes, invocations, or the types of fields, parameters,`,
+			`and results, with the kinds of the dependencies between them.`)
+		fmt.Println(`  --ranges: Indicates that the start and end lines and columns`,
+			`of the declarations, metrics, and fields should be outputted, so that`,
+			`the whole of a method or struct can be highlighted.`)
+		fmt.Println(`  --progress|-P: Indicates that the progress of each phase`,
+			`should be written to the standard error.`)
+		fmt.Println(`  --timeout: The number of seconds to allow the abstraction`,
+			`to run before it is cancelled. If not given, there is no timeout.`)
+		fmt.Println(`  --stats|-S: Indicates that the wall time, allocations, and peak`,
+			`heap of each phase, the resolver iteration count, and the number of`,
+			`constructs of each kind should be written as JSON alongside the output,`,
+			`e.g. "out.stats.json" for "out.json". If no output path is given,`,
+			`the statistics are written to the standard error.`)
 		os.Exit(0)
 	}
 
-	ps, err := reader.Read(&reader.Config{
-		Verbose: ao.Verbose,
-		Dir:     ao.InPath,
-	})
+	if !validFormat(ao.Format) {
+		fmt.Println(`Unknown output format:`, ao.Format)
+		os.Exit(1)
+	}
+
+	cfg, err := newConfig(&ao.abstractArgObject, os.Stderr)
 	if err != nil {
-		fmt.Println(`Error reading project:`, err)
+		fmt.Println(err)
 		os.Exit(1)
 	}
 
-	var log *logger.Logger
-	if ao.Verbose {
-		log = logger.New()
+	ctx := context.Background()
+	if ao.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(ao.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	if ao.Progress {
+		cfg.Progress = func(p abstraction.Progress) { fmt.Fprintln(os.Stderr, p) }
+	}
+
+	if ao.Stats {
+		cfg.Stats = func(s *abstraction.Stats) {
+			if err := writeStats(ao.OutPath, ao.Minimize, s); err != nil {
+				fmt.Println(`Error writing stats:`, err)
+			}
+		}
 	}
 
-	proj := abstractor.Abstract(abstractor.Config{
-		Packages: ps,
-		Log:      log,
-	})
-	if err = writeJson(ao.OutPath, ao.Minimize, proj); err != nil {
+	result, err := abstraction.Abstract(ctx, cfg)
+	if err != nil {
+		fmt.Println(`Error abstracting project:`, err)
+		os.Exit(1)
+	}
+	proj := abstracted.Constructs(result)
+	if count := len(proj.Diagnostics().Diagnostics()); count > 0 {
+		fmt.Fprintln(os.Stderr, `Abstraction skipped`, count, `failures,`,
+			`see the diagnostics in the output.`)
+	}
+	jCtx := jsonify.NewContext().
+		SetMinimize(ao.Minimize).
+		SetSkipDead(ao.SkipDead).
+		SetIncludeTestMetrics(ao.TestMetrics).
+		SetExcludeGeneratedMetrics(ao.ExcludeGenerated).
+		SetIncludeAliveReasons(ao.AliveReasons).
+		SetIncludeDeadReport(ao.DeadReport).
+		SetIncludeStableIDs(ao.StableIDs).
+		SetIncludeCKMetrics(ao.CKMetrics).
+		SetIncludePackageMetrics(ao.PackageMetrics).
+		SetExcludeStdlib(ao.ExcludeStdlib).
+		SetIncludeCycles(ao.Cycles).
+		SetIncludeRanges(ao.Ranges)
+	var out jsonify.Jsonable = proj
+	if ao.Format == formatSarif {
+		out = sarif.New(proj, ao.InPath).
+			AddSmells(smells.New(proj, smells.DefaultThresholds(), constructs.Measure{
+				Tests:            ao.TestMetrics,
+				ExcludeGenerated: ao.ExcludeGenerated,
+			})).
+			AddDeadCode(deadCode.New(proj)).
+			AddCycles(cycles.Find(proj, ao.SkipDead))
+	}
+	if err = writeJson(ao.OutPath, jCtx, out); err != nil {
 		fmt.Println(`Error abstracting project:`, err)
 		os.Exit(1)
 	}
@@ -82,10 +273,165 @@ func main() {
 	os.Exit(0)
 }
 
-func writeJson(path string, minimize bool, p constructs.Project) error {
-	ctx := jsonify.NewContext()
-	ctx.SetMinimize(minimize)
-	b, err := jsonify.Marshal(ctx, p)
+// printAbstractHelp prints the help text for the arguments
+// shared by the commands that abstract a project.
+func printAbstractHelp() {
+	fmt.Println(`  --verbose|-v: Indicates the abstraction process should`,
+		`output additional status information.`)
+	fmt.Println(`  --logLevel: The minimum level, "debug", "info", "warn", or "error",`,
+		`of the status information to output. Debug includes a trace of`,
+		`each declaration. If not given, the level is "info". Giving a level`,
+		`outputs the status information, as if --verbose was given.`)
+	fmt.Println(`  --logGroups: A comma separated list of groups, e.g. "resolver,usages",`,
+		`of detailed status information to also output at every level, including`,
+		`debug. Use "*" to output every group. If not given, only the ungrouped`,
+		`information is outputted. Giving groups outputs the status information,`,
+		`as if --verbose was given.`)
+	fmt.Println(`  --logJson: Indicates the status information should be outputted`,
+		`as JSON lines, with the time, level, message, group, and indent,`,
+		`to the standard error so that it can be filtered and analyzed.`)
+	fmt.Println(`  --in|-i: The input path to the directory of the project`,
+		`or package to read. The project directory should have a go.mod file`,
+		`or a go.work file to read every module in the workspace.`)
+	fmt.Println(`  --modules|-M: A comma separated list of paths to additional`,
+		`module directories to read and abstract along with the input path.`)
+	fmt.Println(`  --builds|-b: A comma separated list of build configurations`,
+		`to read and abstract together, e.g. "linux/amd64,windows/amd64:netgo+osusergo".`,
+		`Each configuration is <goos>/<goarch> optionally followed by a colon`,
+		`and plus separated build tags. Each declaration and metrics lists the`,
+		`configurations it was found in. If not given, the current platform is read.`)
+	fmt.Println(`  --workers|-w: The number of packages to analyze and abstract concurrently.`,
+		`The output is the same as when run serially. If not given,`,
+		`the packages will be analyzed and abstracted serially.`)
+	fmt.Println(`  --cache|-c: The directory to cache the constructs abstracted from each package in.`,
+		`The constructs for any package that, along with its dependencies, hasn't changed`,
+		`are restored instead of being analyzed and abstracted again.`,
+		`All the packages are still read and type checked.`,
+		`If not given, no cache is used.`)
+	fmt.Println(`  --tolerant|-t: Indicates that failures in declarations`,
+		`should be recorded as diagnostics and skipped instead of`,
+		`stopping the abstraction.`)
+	fmt.Println(`  --partial|-p: Indicates that packages with errors, and`,
+		`packages depending on them, should be skipped and recorded as`,
+		`diagnostics instead of stopping the abstraction.`)
+	fmt.Println(`  --skipDead|-d: Indicates that the constructs found to be dead`,
+		`by the dead-code elimination should be left out of the output.`)
+	fmt.Println(`  --tests|-T: Indicates that test files and external test`,
+		`packages should be read. Test code is tagged as test in the output.`)
+	fmt.Println(`  --rootExported|-e: A comma separated list of package paths`,
+		`whose exported API, including exported methods, should be kept`,
+		`alive by the dead-code elimination.`)
+	fmt.Println(`  --rootsFile|-R: The path to a file listing declarations that`,
+		`should be kept alive by the dead-code elimination, one per line,`,
+		`e.g. "example.com/foo.Bar" or "example.com/foo.Bar.Method".`,
+		`Empty lines and lines starting with "#" are ignored.`)
+	fmt.Println(`  --reflectionRoots: Indicates that declarations used in`,
+		`reflection-based registrations, e.g. "gob.Register(Foo{})",`,
+		`should be kept alive by the dead-code elimination.`)
+}
+
+// newConfig creates the configuration for abstracting the project from
+// the given arguments. The JSON lines log, if requested, is written to the
+// given writer. The log handler writes every level since the logs are
+// filtered by the log level and the shown groups before reaching it,
+// so that the shown groups are written at every level.
+func newConfig(ao *abstractArgObject, logOut io.Writer) (abstraction.Config, error) {
+	symbols, err := readRootsFile(ao.RootsFile)
+	if err != nil {
+		return abstraction.Config{}, fmt.Errorf(`error reading roots file: %w`, err)
+	}
+
+	var level slog.Level
+	if len(ao.LogLevel) > 0 {
+		if err := level.UnmarshalText([]byte(ao.LogLevel)); err != nil {
+			return abstraction.Config{}, fmt.Errorf(`error reading log level: %w`, err)
+		}
+	}
+
+	var logHandler slog.Handler
+	if ao.LogJson {
+		logHandler = slog.NewJSONHandler(logOut, &slog.HandlerOptions{Level: slog.LevelDebug})
+	}
+
+	return abstraction.Config{
+		Dir:             ao.InPath,
+		Modules:         splitList(ao.Modules),
+		Builds:          splitList(ao.Builds),
+		Tests:           ao.Tests,
+		SkipBroken:      ao.Partial,
+		SkipDead:        ao.SkipDead,
+		Tolerant:        ao.Tolerant,
+		Workers:         ao.Workers,
+		CacheDir:        ao.CacheDir,
+		ExportedRoots:   splitList(ao.RootExported),
+		RootSymbols:     symbols,
+		ReflectionRoots: ao.ReflectionRoots,
+		Verbose:         ao.Verbose,
+		LogHandler:      logHandler,
+		LogLevel:        level,
+		LogGroups:       splitList(ao.LogGroups),
+	}, nil
+}
+
+// Output formats, where an empty format is JSON.
+const (
+	formatJson  = `json`
+	formatSarif = `sarif`
+)
+
+func validFormat(format string) bool {
+	return len(format) <= 0 || format == formatJson || format == formatSarif
+}
+
+func splitList(list string) []string {
+	parts := []string{}
+	for _, part := range strings.Split(list, `,`) {
+		if part = strings.TrimSpace(part); len(part) > 0 {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// readRootsFile reads the qualified names of the declarations that
+// should be roots of the dead-code elimination from the given file.
+// Returns nil if no file path was given.
+func readRootsFile(path string) ([]string, error) {
+	if len(path) <= 0 {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	symbols := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 && !strings.HasPrefix(line, `#`) {
+			symbols = append(symbols, line)
+		}
+	}
+	return symbols, nil
+}
+
+// writeStats writes the statistics next to the given output path,
+// e.g. `out.stats.json` for `out.json`. If no output path is given,
+// the statistics are written to the standard error.
+func writeStats(outPath string, minimize bool, s *abstraction.Stats) error {
+	b, err := jsonify.Marshal(jsonify.NewContext().SetMinimize(minimize), s)
+	if err != nil {
+		return err
+	}
+
+	if len(outPath) > 0 {
+		path := strings.TrimSuffix(outPath, filepath.Ext(outPath)) + `.stats.json`
+		return os.WriteFile(path, b, 0o666)
+	}
+	_, err = fmt.Fprintln(os.Stderr, string(b))
+	return err
+}
+
+func writeJson(path string, ctx *jsonify.Context, data jsonify.Jsonable) error {
+	b, err := jsonify.Marshal(ctx, data)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/abstraction"
+)
+
+func Test_LogGroups(t *testing.T) {
+	// The fixture is only built with the test tag
+	// which the command line reads from the Go flags.
+	t.Setenv(`GOFLAGS`, `-tags=test`)
+	dir, err := filepath.Abs(`../testData/go/test0021`)
+	check.NoError(t).Require(err)
+	buf := &bytes.Buffer{}
+	abstractWithArgs(t, buf, `-i`, dir,
+		`--logJson`, `--logLevel`, `warn`, `--logGroups`, `usages`)
+
+	// The shown group's debug traces are written even though the level
+	// is warn, but the other groups and the ungrouped info are hidden.
+	check.True(t).Name(`has usages`).Assert(strings.Contains(buf.String(), `"group":"usages"`))
+	check.True(t).Name(`has debug`).Assert(strings.Contains(buf.String(), `"level":"DEBUG"`))
+	check.False(t).Name(`has resolver`).Assert(strings.Contains(buf.String(), `"group":"resolver"`))
+	check.False(t).Name(`has info`).Assert(strings.Contains(buf.String(), `"level":"INFO"`))
+}
+
+func Test_LogLevel(t *testing.T) {
+	ao := parseArgs(t, `--logLevel`, `debug`)
+	cfg, err := newConfig(&ao.abstractArgObject, &bytes.Buffer{})
+	check.NoError(t).Require(err)
+	check.Nil(t).Name(`log handler`).Assert(cfg.LogHandler)
+	check.Equal(t, slog.LevelDebug).Name(`log level`).Assert(cfg.LogLevel)
+
+	ao = parseArgs(t, `--logLevel`, `loud`)
+	_, err = newConfig(&ao.abstractArgObject, &bytes.Buffer{})
+	check.MatchError(t, `error reading log level`).Assert(err)
+}
+
+func Test_DeadReport(t *testing.T) {
+	check.True(t).Name(`dead report`).Assert(parseArgs(t, `--dead-report`).DeadReport)
+}
+
+func Test_SubcommandArgs(t *testing.T) {
+	// The commands that abstract a project read the same arguments
+	// for the abstraction and create the configuration the same way.
+	arguments := []string{`-i`, `foo`, `-M`, `bar, baz`, `-b`, `linux/amd64`,
+		`-c`, `cache`, `-w`, `4`, `-t`, `-p`, `-T`}
+	for _, abstract := range []*abstractArgObject{
+		&parseArgs(t, arguments...).abstractArgObject,
+		func() *abstractArgObject {
+			ao := &participationArgObject{}
+			check.NoError(t).Require(processArgs(append(arguments, `--csv`), ao, &ao.abstractArgObject))
+			return &ao.abstractArgObject
+		}(),
+		func() *abstractArgObject {
+			ao := &smellsArgObject{}
+			check.NoError(t).Require(processArgs(append(arguments, `--ranges`), ao, &ao.abstractArgObject))
+			return &ao.abstractArgObject
+		}(),
+	} {
+		cfg, err := newConfig(abstract, &bytes.Buffer{})
+		check.NoError(t).Require(err)
+		check.Equal(t, `foo`).Assert(cfg.Dir)
+		check.Equal(t, []string{`bar`, `baz`}).Assert(cfg.Modules)
+		check.Equal(t, []string{`linux/amd64`}).Assert(cfg.Builds)
+		check.Equal(t, `cache`).Assert(cfg.CacheDir)
+		check.Equal(t, 4).Assert(cfg.Workers)
+		check.True(t).Assert(cfg.Tolerant && cfg.SkipBroken && cfg.Tests)
+	}
+}
+
+func parseArgs(t *testing.T, arguments ...string) *argObject {
+	ao := &argObject{}
+	check.NoError(t).Require(processArgs(arguments, ao, &ao.abstractArgObject))
+	return ao
+}
+
+func abstractWithArgs(t *testing.T, logOut *bytes.Buffer, arguments ...string) *abstraction.Project {
+	cfg, err := newConfig(&parseArgs(t, arguments...).abstractArgObject, logOut)
+	check.NoError(t).Require(err)
+	proj, err := abstraction.Abstract(context.Background(), cfg)
+	check.NoError(t).Require(err)
+	return proj
+}
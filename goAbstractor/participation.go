@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/abstraction"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstracted"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/participation"
+)
+
+// participationCommand is the name of the subcommand to
+// compute the participation matrix of a project.
+const participationCommand = `participation`
+
+type participationArgObject struct {
+	abstractArgObject
+
+	ShowHelp bool   `args:"flag, h, help"`
+	Minimize bool   `args:"flag, m, minimize"`
+	OutPath  string `args:"o, out"`
+	Csv      bool   `args:"flag, , csv"`
+
+	TestMetrics      bool `args:"flag, , testMetrics"`
+	ExcludeGenerated bool `args:"flag, g, excludeGenerated"`
+}
+
+// runParticipation abstracts a project and writes the participation
+// matrix of its methods with its objects as JSON or CSV.
+func runParticipation(arguments []string) {
+	ao := &participationArgObject{}
+	if err := processArgs(arguments, ao, &ao.abstractArgObject); err != nil {
+		fmt.Println(err.Error())
+		fmt.Println(`Use "participation -h" argument to show help.`)
+		os.Exit(1)
+	}
+
+	if ao.ShowHelp {
+		fmt.Println(`Participation will read a Go project and output the participation`,
+			`matrix, the fuzzy estimate between zero and one of how much each object`,
+			`participates in each method, determined from the method's receiver`,
+			`and the objects it reads, writes, and invokes.`)
+		fmt.Println(os.Args[0], participationCommand, `<options> -i <inputPath> [ -o <outputPath> ]`)
+		fmt.Println(`  --help|-h: Shows this help text.`)
+		printAbstractHelp()
+		fmt.Println(`  --minimize|-m: Indicates the JSON output should be`,
+			`minimized instead of formatted.`)
+		fmt.Println(`  --out|-o: The output file path to write the matrix to.`,
+			`If not given, the matrix will be outputted to the console.`)
+		fmt.Println(`  --csv: Indicates the matrix should be outputted as comma`,
+			`separated values, with a row for each method and a column for each`,
+			`object, instead of JSON. This is also used if the output path`,
+			`ends with ".csv".`)
+		fmt.Println(`  --testMetrics: Indicates that the methods and objects in test`,
+			`code should be included in the matrix. By default test code is read`,
+			`but isn't included.`)
+		fmt.Println(`  --excludeGenerated|-g: Indicates that the methods and objects`,
+			`in generated code, files with a "// Code generated ... DO NOT EDIT."`,
+			`header, shouldn't be included in the matrix.`)
+		os.Exit(0)
+	}
+
+	cfg, err := newConfig(&ao.abstractArgObject, os.Stderr)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	result, err := abstraction.Abstract(context.Background(), cfg)
+	if err != nil {
+		fmt.Println(`Error abstracting project:`, err)
+		os.Exit(1)
+	}
+	proj := abstracted.Constructs(result)
+
+	matrix := participation.New(proj, constructs.Measure{
+		Tests:            ao.TestMetrics,
+		ExcludeGenerated: ao.ExcludeGenerated,
+	})
+	if ao.Csv || strings.HasSuffix(strings.ToLower(ao.OutPath), `.csv`) {
+		err = writeCSV(ao.OutPath, matrix)
+	} else {
+		err = writeJson(ao.OutPath, jsonify.NewContext().SetMinimize(ao.Minimize), matrix)
+	}
+	if err != nil {
+		fmt.Println(`Error writing participation:`, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func writeCSV(path string, matrix *participation.Matrix) error {
+	if len(path) <= 0 {
+		return matrix.WriteCSV(os.Stdout)
+	}
+	buf := &bytes.Buffer{}
+	if err := matrix.WriteCSV(buf); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o666)
+}
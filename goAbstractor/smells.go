@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/abstraction"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstracted"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/sarif"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/smells"
+)
+
+// smellsCommand is the name of the subcommand to
+// find the code smells in a project.
+const smellsCommand = `smells`
+
+type smellsArgObject struct {
+	abstractArgObject
+
+	ShowHelp bool   `args:"flag, h, help"`
+	Minimize bool   `args:"flag, m, minimize"`
+	OutPath  string `args:"o, out"`
+	Format   string `args:"f, format"`
+	Ranges   bool   `args:"flag, , ranges"`
+
+	TestMetrics      bool `args:"flag, , testMetrics"`
+	ExcludeGenerated bool `args:"flag, g, excludeGenerated"`
+
+	GodComplexity      int     `args:", godComplexity"`
+	GodForeignData     int     `args:", godForeignData"`
+	GodCohesion        float64 `args:", godCohesion"`
+	DataClassMethods   int     `args:", dataClassMethods"`
+	DataClassAccessors float64 `args:", dataClassAccessors"`
+	EnvyAccesses       int     `args:", envyAccesses"`
+	LongMethodLines    int     `args:", longMethodLines"`
+	LongParameters     int     `args:", longParameters"`
+	ShotgunMethods     int     `args:", shotgunMethods"`
+	ShotgunOwners      int     `args:", shotgunOwners"`
+}
+
+// runSmells abstracts a project and writes the code smells found in it.
+func runSmells(arguments []string) {
+	th := smells.DefaultThresholds()
+	ao := &smellsArgObject{
+		GodComplexity:      th.GodComplexity,
+		GodForeignData:     th.GodForeignData,
+		GodCohesion:        th.GodCohesion,
+		DataClassMethods:   th.DataClassMethods,
+		DataClassAccessors: th.DataClassAccessors,
+		EnvyAccesses:       th.EnvyAccesses,
+		LongMethodLines:    th.LongMethodLines,
+		LongParameters:     th.LongParameters,
+		ShotgunMethods:     th.ShotgunMethods,
+		ShotgunOwners:      th.ShotgunOwners,
+	}
+	if err := processArgs(arguments, ao, &ao.abstractArgObject); err != nil {
+		fmt.Println(err.Error())
+		fmt.Println(`Use "smells -h" argument to show help.`)
+		os.Exit(1)
+	}
+
+	if ao.ShowHelp {
+		fmt.Println(`Smells will read a Go project and output the code smells found in`,
+			`the objects and methods of the read packages, with the location of each`,
+			`and the metrics that met the thresholds for the smell.`)
+		fmt.Println(os.Args[0], smellsCommand, `<options> -i <inputPath> [ -o <outputPath> ]`)
+		fmt.Println(`  --help|-h: Shows this help text.`)
+		printAbstractHelp()
+		fmt.Println(`  --minimize|-m: Indicates the JSON output should be`,
+			`minimized instead of formatted.`)
+		fmt.Println(`  --out|-o: The output file path to write the smells to.`,
+			`If not given, the smells will be outputted to the console.`)
+		fmt.Println(`  --format|-f: The format of the output, either "json" or "sarif"`,
+			`for a SARIF 2.1.0 log with file paths relative to the input path.`,
+			`If not given, the format is "json".`)
+		fmt.Println(`  --testMetrics: Indicates that test code should be checked`,
+			`for smells. By default test code is read but isn't checked.`)
+		fmt.Println(`  --excludeGenerated|-g: Indicates that generated code, files`,
+			`with a "// Code generated ... DO NOT EDIT." header, shouldn't`,
+			`be checked for smells.`)
+		fmt.Println(`  --ranges: Indicates that the start and end lines and columns`,
+			`of each smell should be outputted, so that the whole of a method`,
+			`or struct can be highlighted.`)
+		fmt.Println(`  --godComplexity: The minimum sum of the complexity of an`,
+			`object's methods (WMC) for a god object. Default is`, th.GodComplexity)
+		fmt.Println(`  --godForeignData: The minimum number of fields of other`,
+			`objects accessed by an object's methods (ATFD) for a god object.`,
+			`Default is`, th.GodForeignData)
+		fmt.Println(`  --godCohesion: The tight class cohesion (TCC) that an object`,
+			`must be below for a god object. Default is`, th.GodCohesion)
+		fmt.Println(`  --dataClassMethods: The minimum number of methods for a`,
+			`data class. Default is`, th.DataClassMethods)
+		fmt.Println(`  --dataClassAccessors: The minimum ratio of methods that are`,
+			`getters or setters for a data class. Default is`, th.DataClassAccessors)
+		fmt.Println(`  --envyAccesses: The minimum number of fields of another`,
+			`object that a method accesses for feature envy. Default is`, th.EnvyAccesses)
+		fmt.Println(`  --longMethodLines: The minimum number of lines with code`,
+			`for a long method. Default is`, th.LongMethodLines)
+		fmt.Println(`  --longParameters: The minimum number of parameters for a`,
+			`long parameter list. Default is`, th.LongParameters)
+		fmt.Println(`  --shotgunMethods: The minimum number of methods invoking a`,
+			`method (CM) for shotgun surgery. Default is`, th.ShotgunMethods)
+		fmt.Println(`  --shotgunOwners: The minimum number of objects or packages`,
+			`that the invoking methods are in (CC) for shotgun surgery.`,
+			`Default is`, th.ShotgunOwners)
+		os.Exit(0)
+	}
+
+	if !validFormat(ao.Format) {
+		fmt.Println(`Unknown output format:`, ao.Format)
+		os.Exit(1)
+	}
+
+	cfg, err := newConfig(&ao.abstractArgObject, os.Stderr)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	result, err := abstraction.Abstract(context.Background(), cfg)
+	if err != nil {
+		fmt.Println(`Error abstracting project:`, err)
+		os.Exit(1)
+	}
+	proj := abstracted.Constructs(result)
+
+	report := smells.New(proj, smells.Thresholds{
+		GodComplexity:      ao.GodComplexity,
+		GodForeignData:     ao.GodForeignData,
+		GodCohesion:        ao.GodCohesion,
+		DataClassMethods:   ao.DataClassMethods,
+		DataClassAccessors: ao.DataClassAccessors,
+		EnvyAccesses:       ao.EnvyAccesses,
+		LongMethodLines:    ao.LongMethodLines,
+		LongParameters:     ao.LongParameters,
+		ShotgunMethods:     ao.ShotgunMethods,
+		ShotgunOwners:      ao.ShotgunOwners,
+	}, constructs.Measure{
+		Tests:            ao.TestMetrics,
+		ExcludeGenerated: ao.ExcludeGenerated,
+	})
+
+	// The locations are outputted with the file and line since
+	// the location offsets are only meaningful with the abstraction.
+	jCtx := jsonify.NewContext().
+		SetMinimize(ao.Minimize).
+		IncludeDebugFullLoc(true).
+		SetIncludeRanges(ao.Ranges)
+	var out jsonify.Jsonable = report
+	if ao.Format == formatSarif {
+		out = sarif.New(proj, ao.InPath).AddSmells(report)
+	}
+	if err = writeJson(ao.OutPath, jCtx, out); err != nil {
+		fmt.Println(`Error writing smells:`, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
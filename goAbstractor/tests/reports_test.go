@@ -0,0 +1,404 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/ckMetrics"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/cycles"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/deadCode"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/packageMetrics"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/participation"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/sarif"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/smells"
+)
+
+// The reports, such as the participation matrix and metrics, are checked
+// with the project abstracted from a fixture and the options for the
+// report, e.g. measuring test code, that the output doesn't select.
+
+func Test_T0011_Participation(t *testing.T) {
+	proj := newTest(t, `test0011`).abstract().proj
+
+	buf := &bytes.Buffer{}
+	check.NoError(t).Require(participation.New(proj, constructs.Measure{}).WriteCSV(buf))
+	check.Equal(t, "method,command-line-arguments.Bacon,command-line-arguments.Set\n"+
+		"command-line-arguments.Set.AsSlices,0,1\n"+
+		"command-line-arguments.PrintSlice,0,0\n"+
+		"command-line-arguments.main,0.5714285714285714,0.42857142857142855\n").
+		Assert(buf.String())
+}
+
+func Test_T0030_Participation(t *testing.T) {
+	proj := newTest(t, `test0030`).withTests().abstract(`./...`).proj
+
+	buf := &bytes.Buffer{}
+	check.NoError(t).Require(participation.New(proj, constructs.Measure{}).WriteCSV(buf))
+	check.Equal(t, "method,test0030/shapes.Square,test0030/shapes.Unit\n"+
+		"test0030.main,1,0\n"+
+		"test0030/shapes.Square.Area,1,0\n"+
+		"test0030/shapes.NewSquare,1,0\n"+
+		"test0030/shapes.Square.String,1,0\n"+
+		"test0030/shapes.Unit.String,0,1\n").
+		Assert(buf.String())
+
+	buf.Reset()
+	check.NoError(t).Require(participation.New(proj, constructs.Measure{Tests: true}).WriteCSV(buf))
+	check.Equal(t, "method,test0030/shapes.Square,test0030/shapes.Unit,test0030/shapes.fakeSquare\n"+
+		"test0030.main,1,0,0\n"+
+		"test0030/shapes.Square.Area,1,0,0\n"+
+		"test0030/shapes.fakeSquare.Area,0,0,1\n"+
+		"test0030/shapes.ExampleSquare,0,0,1\n"+
+		"test0030/shapes.NewSquare,1,0,0\n"+
+		"test0030/shapes.Square.String,1,0,0\n"+
+		"test0030/shapes.Unit.String,0,1,0\n"+
+		"test0030/shapes_test.ExampleNewSquare,1,0,0\n").
+		Assert(buf.String())
+
+	buf.Reset()
+	check.NoError(t).Require(participation.New(proj, constructs.Measure{ExcludeGenerated: true}).WriteCSV(buf))
+	check.Equal(t, "method,test0030/shapes.Square\n"+
+		"test0030.main,1\n"+
+		"test0030/shapes.Square.Area,1\n"+
+		"test0030/shapes.NewSquare,1\n").
+		Assert(buf.String())
+}
+
+func Test_T0013_CKMetrics(t *testing.T) {
+	proj := newTest(t, `test0013`).abstract().proj
+
+	b, err := jsonify.Marshal(jsonify.NewContext().SetMinimize(true), ckMetrics.New(proj, constructs.Measure{}))
+	check.NoError(t).Require(err)
+	check.Equal(t, `[`+
+		`{"cbo":2,"dit":1,"lcom1":0,"lcom2":0,"lcom3":1,"lcom4":1,"lcom5":0,"loc":15,`+
+		`"name":"command-line-arguments.Point","noc":0,"rfc":1,"wmc":1},`+
+		`{"cbo":1,"dit":0,"lcom1":0,"lcom2":0,"lcom3":1,"lcom4":1,"lcom5":0,"loc":7,`+
+		`"name":"command-line-arguments.XCoord","noc":1,"rfc":1,"wmc":1},`+
+		`{"cbo":1,"dit":0,"lcom1":0,"lcom2":0,"lcom3":1,"lcom4":1,"lcom5":0,"loc":11,`+
+		`"name":"command-line-arguments.YCoord","noc":1,"rfc":1,"wmc":1}]`).
+		Assert(string(b))
+}
+
+func Test_T0030_CKMetrics(t *testing.T) {
+	proj := newTest(t, `test0030`).withTests().abstract(`./...`).proj
+
+	// The fake in the test file isn't measured, nor is it coupled
+	// to the square, unless the test code is measured. The unit and the
+	// square's string method in the generated file are measured unless
+	// the generated code is excluded.
+	entries := func(r *ckMetrics.Report) []string {
+		result := make([]string, len(r.Objects))
+		for i, e := range r.Objects {
+			result[i] = fmt.Sprintf(`%s cbo=%d wmc=%d`, e.Name, e.CBO, e.WMC)
+		}
+		return result
+	}
+	check.Equal(t, []string{
+		`test0030/shapes.Square cbo=0 wmc=2`,
+		`test0030/shapes.Unit cbo=0 wmc=1`,
+	}).Assert(entries(ckMetrics.New(proj, constructs.Measure{})))
+	check.Equal(t, []string{
+		`test0030/shapes.Square cbo=1 wmc=2`,
+		`test0030/shapes.Unit cbo=0 wmc=1`,
+		`test0030/shapes.fakeSquare cbo=1 wmc=1`,
+	}).Assert(entries(ckMetrics.New(proj, constructs.Measure{Tests: true})))
+	check.Equal(t, []string{
+		`test0030/shapes.Square cbo=0 wmc=1`,
+	}).Assert(entries(ckMetrics.New(proj, constructs.Measure{ExcludeGenerated: true})))
+}
+
+func Test_T0014_PackageMetrics(t *testing.T) {
+	proj := newTest(t, `test0014`).abstract(`./...`).proj
+
+	b, err := jsonify.Marshal(jsonify.NewContext().SetMinimize(true), packageMetrics.New(proj, true, constructs.Measure{}))
+	check.NoError(t).Require(err)
+	check.Equal(t, `[`+
+		`{"abstractness":0,"afferent":0,"distance":0,"efferent":2,"instability":1,`+
+		`"path":"test0014","uses":["test0014/animals","test0014/enums"]},`+
+		`{"abstractness":0.6,"afferent":1,"distance":0.10000000000000009,"efferent":1,"instability":0.5,`+
+		`"path":"test0014/animals","usedBy":["test0014"],"uses":["test0014/enums"]},`+
+		`{"abstractness":0.25,"afferent":2,"distance":0.75,"efferent":0,"instability":0,`+
+		`"path":"test0014/enums","usedBy":["test0014","test0014/animals"]}]`).
+		Assert(string(b))
+}
+
+func Test_T0030_PackageMetrics(t *testing.T) {
+	proj := newTest(t, `test0030`).withTests().abstract(`./...`).proj
+
+	// The external test package only has test code,
+	// so it is left out unless the test code is measured.
+	b, err := jsonify.Marshal(jsonify.NewContext().SetMinimize(true),
+		packageMetrics.New(proj, true, constructs.Measure{}))
+	check.NoError(t).Require(err)
+	check.Equal(t, `[`+
+		`{"abstractness":0,"afferent":0,"distance":0,"efferent":1,"instability":1,`+
+		`"path":"test0030","uses":["test0030/shapes"]},`+
+		`{"abstractness":0,"afferent":1,"distance":1,"efferent":0,"instability":0,`+
+		`"path":"test0030/shapes","usedBy":["test0030"]}]`).
+		Assert(string(b))
+
+	b, err = jsonify.Marshal(jsonify.NewContext().SetMinimize(true),
+		packageMetrics.New(proj, true, constructs.Measure{Tests: true}))
+	check.NoError(t).Require(err)
+	check.Equal(t, `[`+
+		`{"abstractness":0,"afferent":0,"distance":0,"efferent":1,"instability":1,`+
+		`"path":"test0030","uses":["test0030/shapes"]},`+
+		`{"abstractness":0,"afferent":2,"distance":1,"efferent":0,"instability":0,`+
+		`"path":"test0030/shapes","usedBy":["test0030","test0030/shapes_test"]},`+
+		`{"abstractness":0,"afferent":0,"distance":0,"efferent":1,"instability":1,`+
+		`"path":"test0030/shapes_test","uses":["test0030/shapes"]}]`).
+		Assert(string(b))
+
+	// Only the generated code uses the standard library.
+	uses := func(measure constructs.Measure) []string {
+		result := []string{}
+		for _, e := range packageMetrics.New(proj, false, measure).Packages {
+			result = append(result, e.Path+` uses `+strings.Join(e.Uses, `, `))
+		}
+		return result
+	}
+	check.Equal(t, []string{
+		`test0030 uses test0030/shapes`,
+		`test0030/shapes uses strconv`,
+	}).Assert(uses(constructs.Measure{}))
+	check.Equal(t, []string{
+		`test0030 uses test0030/shapes`,
+		`test0030/shapes uses `,
+	}).Assert(uses(constructs.Measure{ExcludeGenerated: true}))
+}
+
+func Test_T0019_Cycles_SkipDead(t *testing.T) {
+	// The unused ping and pong are dead so their cycle is skipped.
+	proj := newTest(t, `test0019`).abstract().proj
+	check.Equal(t, []string{
+		`Handler -field-> Visitor, Visitor -field-> Handler`,
+		`Node -signature-> Tree, Tree -field-> Node`,
+		`Parity.IsEven -invokes-> Parity.IsOdd, Parity.IsOdd -invokes-> Parity.IsEven`,
+		`tick -invokes-> tock, tock -invokes-> tick`,
+	}).Assert(cycleStrings(cycles.Find(proj, true)))
+}
+
+// cycleStrings gets each cycle as the dependencies, with the kinds of each
+// dependency, between the declarations in the cycle. The cycles are sorted.
+func cycleStrings(found []*constructs.Cycle) []string {
+	result := make([]string, len(found))
+	for i, c := range found {
+		deps := make([]string, len(c.Dependencies))
+		for j, dep := range c.Dependencies {
+			kinds := make([]string, len(dep.Kinds))
+			for k, kind := range dep.Kinds {
+				kinds[k] = string(kind)
+			}
+			deps[j] = constructs.LocalName(dep.From) + ` -` + strings.Join(kinds, `,`) + `-> ` + constructs.LocalName(dep.To)
+		}
+		result[i] = strings.Join(deps, `, `)
+	}
+	slices.Sort(result)
+	return result
+}
+
+func Test_T0013_Smells(t *testing.T) {
+	proj := newTest(t, `test0013`).abstract().proj
+
+	th := smells.DefaultThresholds()
+	th.DataClassMethods = 1
+	th.LongMethodLines = 5
+	th.LongParameters = 1
+	b, err := jsonify.Marshal(jsonify.NewContext().SetMinimize(true).IncludeDebugFullLoc(true), smells.New(proj, th, constructs.Measure{}))
+	check.NoError(t).Require(err)
+	check.Equal(t, `[`+
+		`{"evidence":[{"metric":"methods","threshold":1,"value":1},{"metric":"accessors","threshold":0.75,"value":1}],`+
+		`"loc":{"file":"main.go","line":7,"offset":7},"name":"command-line-arguments.XCoord","smell":"dataClass"},`+
+		`{"evidence":[{"metric":"methods","threshold":1,"value":1},{"metric":"accessors","threshold":0.75,"value":1}],`+
+		`"loc":{"file":"main.go","line":11,"offset":11},"name":"command-line-arguments.YCoord","smell":"dataClass"},`+
+		`{"evidence":[{"metric":"parameters","threshold":1,"value":1}],`+
+		`"loc":{"file":"main.go","line":35,"offset":35},"name":"command-line-arguments.PrintPoint","smell":"longParameterList"}]`).
+		Assert(string(b))
+}
+
+func Test_T0020_Smells(t *testing.T) {
+	proj := newTest(t, `test0020`).abstract().proj
+
+	// The fixture is small so the thresholds are lowered
+	// such that each smell is found at least once.
+	th := smells.Thresholds{
+		GodComplexity:      8,
+		GodForeignData:     3,
+		GodCohesion:        0.5,
+		DataClassMethods:   3,
+		DataClassAccessors: 0.75,
+		EnvyAccesses:       3,
+		LongMethodLines:    17,
+		LongParameters:     4,
+		ShotgunMethods:     3,
+		ShotgunOwners:      3,
+	}
+	check.Equal(t, []string{
+		`dataClass command-line-arguments.Account @6 [methods=4/3 accessors=1/0.75]`,
+		`featureEnvy command-line-arguments.Bank.Transfer @25 [foreignAccesses=3/3 ownAccesses=1] ` +
+			`command-line-arguments.Account`,
+		`featureEnvy command-line-arguments.Statement.Describe @66 [foreignAccesses=3/3 ownAccesses=0] ` +
+			`command-line-arguments.Account`,
+		`godObject command-line-arguments.Bank @19 [wmc=10/8 atfd=3/3 tcc=0/0.5]`,
+		`longMethod command-line-arguments.report @85 [codeCount=18/17 complexity=3]`,
+		`longParameterList command-line-arguments.report @85 [parameters=4/4]`,
+		`shotgunSurgery command-line-arguments.logf @80 [cm=3/3 cc=3/3] ` +
+			`command-line-arguments.Bank.Transfer, command-line-arguments.Statement.Describe, ` +
+			`command-line-arguments.Teller.Greet`,
+	}).Assert(findings(smells.New(proj, th, constructs.Measure{})))
+
+	// With the default thresholds only the smells that
+	// don't depend on the size of the fixture are found.
+	check.Equal(t, []string{
+		`dataClass command-line-arguments.Account @6 [methods=4/2 accessors=1/0.75]`,
+		`featureEnvy command-line-arguments.Bank.Transfer @25 [foreignAccesses=3/3 ownAccesses=1] ` +
+			`command-line-arguments.Account`,
+		`featureEnvy command-line-arguments.Statement.Describe @66 [foreignAccesses=3/3 ownAccesses=0] ` +
+			`command-line-arguments.Account`,
+	}).Assert(findings(smells.New(proj, smells.DefaultThresholds(), constructs.Measure{})))
+}
+
+func Test_T0030_Smells(t *testing.T) {
+	proj := newTest(t, `test0030`).withTests().abstract(`./...`).proj
+
+	// Every method with code is a long method so that
+	// each method that is checked for smells is found.
+	th := smells.DefaultThresholds()
+	th.LongMethodLines = 1
+	check.Equal(t, []string{
+		`longMethod test0030.main @11 [codeCount=4/1 complexity=1]`,
+		`longMethod test0030/shapes.NewSquare @9 [codeCount=3/1 complexity=1]`,
+		`longMethod test0030/shapes.Square.Area @13 [codeCount=3/1 complexity=1]`,
+		`longMethod test0030/shapes.Square.String @17 [codeCount=3/1 complexity=1]`,
+		`longMethod test0030/shapes.Unit.String @13 [codeCount=3/1 complexity=1]`,
+	}).Assert(findings(smells.New(proj, th, constructs.Measure{})))
+	check.Equal(t, []string{
+		`longMethod test0030.main @11 [codeCount=4/1 complexity=1]`,
+		`longMethod test0030/shapes.ExampleSquare @13 [codeCount=4/1 complexity=1]`,
+		`longMethod test0030/shapes.NewSquare @9 [codeCount=3/1 complexity=1]`,
+		`longMethod test0030/shapes.Square.Area @13 [codeCount=3/1 complexity=1]`,
+		`longMethod test0030/shapes.Square.String @17 [codeCount=3/1 complexity=1]`,
+		`longMethod test0030/shapes.Unit.String @13 [codeCount=3/1 complexity=1]`,
+		`longMethod test0030/shapes.fakeSquare.Area @9 [codeCount=3/1 complexity=1]`,
+		`longMethod test0030/shapes_test.ExampleNewSquare @7 [codeCount=3/1 complexity=1]`,
+	}).Assert(findings(smells.New(proj, th, constructs.Measure{Tests: true})))
+	check.Equal(t, []string{
+		`longMethod test0030.main @11 [codeCount=4/1 complexity=1]`,
+		`longMethod test0030/shapes.NewSquare @9 [codeCount=3/1 complexity=1]`,
+		`longMethod test0030/shapes.Square.Area @13 [codeCount=3/1 complexity=1]`,
+	}).Assert(findings(smells.New(proj, th, constructs.Measure{ExcludeGenerated: true})))
+}
+
+// findings gets each finding as the smell, name, line,
+// evidence as value and threshold, and related names.
+func findings(r *smells.Report) []string {
+	result := make([]string, len(r.Findings))
+	for i, f := range r.Findings {
+		_, _, line := f.Loc.Info()
+		evidence := make([]string, len(f.Evidence))
+		for j, e := range f.Evidence {
+			evidence[j] = fmt.Sprintf(`%s=%g`, e.Metric, e.Value)
+			if e.Threshold != 0 {
+				evidence[j] += fmt.Sprintf(`/%g`, e.Threshold)
+			}
+		}
+		result[i] = fmt.Sprintf(`%s %s @%d [%s]`, f.Smell, f.Name, line, strings.Join(evidence, ` `))
+		if len(f.Related) > 0 {
+			result[i] += ` ` + strings.Join(f.Related, `, `)
+		}
+	}
+	return result
+}
+
+func Test_T0013_Sarif(t *testing.T) {
+	proj := newTest(t, `test0013`).abstract().proj
+
+	th := smells.DefaultThresholds()
+	th.LongParameters = 1
+	log := sarif.New(proj, ``).AddSmells(smells.New(proj, th, constructs.Measure{}))
+	b, err := jsonify.Marshal(jsonify.NewContext().SetMinimize(true), log)
+	check.NoError(t).Require(err)
+	check.Equal(t, `{"$schema":"https://json.schemastore.org/sarif-2.1.0.json","runs":[{`+
+		`"results":[{"level":"warning","locations":[{"physicalLocation":{`+
+		`"artifactLocation":{"uri":"main.go","uriBaseId":"%SRCROOT%"},"region":{"startLine":35}}}],`+
+		`"message":{"text":"command-line-arguments.PrintPoint has the longParameterList smell: parameters is 1 (at least 1)."},`+
+		`"properties":{"parameters":1},"ruleId":"smells/longParameterList"}],`+
+		`"tool":{"driver":{"informationUri":"https://github.com/MSUSEL/msusel-tdmetrics-go","name":"goAbstractor",`+
+		`"rules":[{"defaultConfiguration":{"level":"warning"},"id":"smells/longParameterList",`+
+		`"shortDescription":{"text":"A method with many parameters."}}]}}}],"version":"2.1.0"}`).
+		Assert(string(b))
+}
+
+func Test_T0019_Sarif(t *testing.T) {
+	proj := newTest(t, `test0019`).abstract().proj
+
+	log := sarif.New(proj, ``).AddDeadCode(deadCode.New(proj)).AddCycles(cycles.Find(proj, false))
+	b, err := jsonify.Marshal(jsonify.NewContext().SetMinimize(true), log)
+	check.NoError(t).Require(err)
+
+	// There is one result for each dead declaration, not for their fields,
+	// and one result for each cycle with the other declarations related.
+	var data struct {
+		Runs []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Message   struct{ Text string }
+				Locations []struct {
+					PhysicalLocation struct {
+						Region struct{ StartLine int }
+					}
+				}
+				RelatedLocations []struct {
+					Message          struct{ Text string }
+					PhysicalLocation struct {
+						Region struct{ StartLine int }
+					}
+				}
+			}
+			Tool struct {
+				Driver struct {
+					Rules []struct{ ID string }
+				}
+			}
+		}
+	}
+	check.NoError(t).Require(json.Unmarshal(b, &data))
+	results := []string{}
+	for _, r := range data.Runs[0].Results {
+		result := fmt.Sprintf(`%s:%s @%d %s`, r.RuleID, r.Level,
+			r.Locations[0].PhysicalLocation.Region.StartLine, r.Message.Text)
+		for _, rel := range r.RelatedLocations {
+			result += fmt.Sprintf(` [%s @%d]`, rel.Message.Text, rel.PhysicalLocation.Region.StartLine)
+		}
+		results = append(results, result)
+	}
+	check.Equal(t, []string{
+		`deadCode:note @63 The method command-line-arguments.ping is dead code.`,
+		`deadCode:note @70 The method command-line-arguments.pong is dead code.`,
+		`dependencyCycle:warning @16 2 declarations depend on each other: ` +
+			`command-line-arguments.Node, command-line-arguments.Tree. [command-line-arguments.Tree @20]`,
+		`dependencyCycle:warning @12 2 declarations depend on each other: ` +
+			`command-line-arguments.Handler, command-line-arguments.Visitor. [command-line-arguments.Visitor @7]`,
+		`dependencyCycle:warning @36 2 declarations depend on each other: ` +
+			`command-line-arguments.Parity.IsEven, command-line-arguments.Parity.IsOdd. [command-line-arguments.Parity.IsOdd @43]`,
+		`dependencyCycle:warning @51 2 declarations depend on each other: ` +
+			`command-line-arguments.tick, command-line-arguments.tock. [command-line-arguments.tock @58]`,
+		`dependencyCycle:warning @63 2 declarations depend on each other: ` +
+			`command-line-arguments.ping, command-line-arguments.pong. [command-line-arguments.pong @70]`,
+	}).Assert(results)
+
+	rules := []string{}
+	for _, r := range data.Runs[0].Tool.Driver.Rules {
+		rules = append(rules, r.ID)
+	}
+	check.Equal(t, []string{sarif.RuleDeadCode, sarif.RuleCycle}).Assert(rules)
+}
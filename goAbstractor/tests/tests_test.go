@@ -1,8 +1,15 @@
 package tests
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/Snow-Gremlin/goToolbox/testers/check"
+
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/resolver/dce"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/logger"
 )
 
@@ -17,6 +24,10 @@ func configLogger(log *logger.Logger) *logger.Logger {
 	//log = log.Show(`packages`)
 	//log = log.Show(`resolver`)
 	//log = log.Show(`usages`)
+	//log = log.Show(logger.AllGroups)
+
+	// Use the level to include the trace of each declaration.
+	//log = log.SetLevel(logger.LevelDebug)
 	return log
 }
 
@@ -43,3 +54,161 @@ func Test_T0015(t *testing.T) { newTest(t, `test0015`).abstract().full() }
 func Test_T0016(t *testing.T) { newTest(t, `test0016`).abstract().full() }
 func Test_T0017(t *testing.T) { newTest(t, `test0017`).abstract().full() }
 func Test_T0018(t *testing.T) { newTest(t, `test0018`).abstract().full() }
+
+func Test_T0019(t *testing.T) {
+	newTest(t, `test0019`).output(jsonify.NewContext().SetIncludeCycles(true)).abstract().full()
+}
+
+func Test_T0020(t *testing.T) { newTest(t, `test0020`).abstract().full() }
+
+func Test_T0021(t *testing.T) { newTest(t, `test0021`).abstract().full() }
+
+func Test_T0022(t *testing.T) { newTest(t, `test0022`).abstract().full() }
+
+func Test_T0022_Modules(t *testing.T) {
+	// The lib module is used by the workspace and by the extra module
+	// so it is read once and both modules invoke the same function.
+	// The lib and extra modules have no main package so they are libraries
+	// with their exported API as roots, while the app module is a program.
+	newTest(t, `test0022`).module(`extra`).
+		output(jsonify.NewContext().SetIncludeAliveReasons(true)).
+		expect(`modules.yaml`).abstract().full()
+}
+
+func Test_T0023(t *testing.T) {
+	newTest(t, `test0023`).build(`linux/amd64`, `windows/amd64`).abstract(`./...`).full()
+}
+
+func Test_T0024(t *testing.T) { newTest(t, `test0024`).tolerate().abstract().partial() }
+
+func Test_T0024_Builds(t *testing.T) {
+	newTest(t, `test0024`).tolerate().build(`linux/amd64`, `windows/amd64`).expect(`builds.yaml`).abstract().partial()
+}
+
+func Test_T0025(t *testing.T) { newTest(t, `test0025`).skipBroken().abstract(`./...`).partial() }
+
+func Test_T0026(t *testing.T) { newTest(t, `test0026`).abstract(`./...`).full() }
+
+func Test_T0026_Tests(t *testing.T) {
+	newTest(t, `test0026`).withTests().expect(`tests.yaml`).abstract(`./...`).full()
+}
+
+func Test_T0027(t *testing.T) { newTest(t, `test0027`).abstract(`main.go`, `color_string.go`).full() }
+
+func Test_T0027_Excluded(t *testing.T) {
+	newTest(t, `test0027`).output(jsonify.NewContext().SetExcludeGeneratedMetrics(true)).
+		expect(`excluded.yaml`).abstract(`main.go`, `color_string.go`).partial()
+}
+
+func Test_T0028(t *testing.T) {
+	newTest(t, `test0028`).rooted(dce.Roots{
+		Exported: []string{`test0028/api`},
+		Symbols:  map[string]string{`test0028.listed`: dce.ReasonSymbolList},
+	}, true).output(jsonify.NewContext().SetIncludeAliveReasons(true)).abstract(`.`).partial()
+}
+
+func Test_T0029(t *testing.T) { newTest(t, `test0029`).abstract(`./...`).partial() }
+
+func Test_T0030(t *testing.T) {
+	newTest(t, `test0030`).output(jsonify.NewContext().SetIncludeAliveReasons(true)).abstract(`./...`).partial()
+}
+
+func Test_T0031(t *testing.T) {
+	newTest(t, `test0031`).output(jsonify.NewContext().SetIncludeDeadReport(true)).abstract().full()
+}
+
+func Test_T0032(t *testing.T) {
+	newTest(t, `test0032`).output(jsonify.NewContext().SetIncludeStableIDs(true)).abstract(`main.go`).full()
+}
+
+func Test_T0032_Added(t *testing.T) {
+	// Adding unrelated declarations before the existing ones changes the
+	// indices but not the identifiers, including another init function
+	// which changes the number the existing init function is named with.
+	newTest(t, `test0032`).output(jsonify.NewContext().SetIncludeStableIDs(true)).
+		expect(`added.yaml`).abstract(`main.go`, `aardvark.go`).full()
+}
+
+func Test_T0014_Parallel(t *testing.T) { newTest(t, `test0014`).parallel(4).abstract().full() }
+
+// The packages of each build, including the standard library packages they
+// depend on, abstracted concurrently must result in the same output as when
+// they are abstracted serially.
+func Test_T0023_Parallel(t *testing.T) {
+	serial := newTest(t, `test0023`).build(`linux/amd64`, `windows/amd64`).abstract(`./...`)
+	newTest(t, `test0023`).build(`linux/amd64`, `windows/amd64`).parallel(4).abstract(`./...`).same(serial)
+}
+
+func Test_T0026_Parallel(t *testing.T) {
+	serial := newTest(t, `test0026`).withTests().abstract(`./...`)
+	newTest(t, `test0026`).withTests().parallel(4).abstract(`./...`).same(serial)
+}
+
+func Test_T0029_Parallel(t *testing.T) {
+	serial := newTest(t, `test0029`).abstract(`./...`)
+	newTest(t, `test0029`).parallel(4).abstract(`./...`).same(serial)
+}
+
+// The logger package depends on many standard library packages
+// with generics and interfaces, e.g. encoding/json and log/slog.
+func Test_Logger_Parallel(t *testing.T) {
+	serial := newTest(t, `logger`).in(`../internal/`).abstract(`./...`)
+	newTest(t, `logger`).in(`../internal/`).parallel(4).abstract(`./...`).same(serial)
+}
+
+func Test_T0014_Tolerant(t *testing.T) { newTest(t, `test0014`).tolerate().abstract().full() }
+
+func Test_T0014_Cached(t *testing.T) {
+	root, dir := copyTestData(t, `test0014`), t.TempDir()
+	newTest(t, `test0014`).in(root).cached(dir).abstract().full().cacheUsed(0, 3) // Fills the cache.
+	newTest(t, `test0014`).in(root).cached(dir).abstract().full().cacheUsed(3, 0) // Reads from the cache.
+
+	// Only the edited package is abstracted again since the others don't depend on it.
+	path := root + `test0014/main.go`
+	data, err := os.ReadFile(path)
+	check.NoError(t).Require(err)
+	check.NoError(t).Require(os.WriteFile(path, append(data, "\nfunc edited() {}\n"...), 0o644))
+	uncached := newTest(t, `test0014`).in(root).abstract()
+	newTest(t, `test0014`).in(root).cached(dir).abstract().same(uncached).cacheUsed(2, 1)
+}
+
+// Test_Cached_AllFixtures abstracts every fixture while filling the cache,
+// which abstracts every package from the source, and when restored from the
+// cache, and checks that the output is the same, e.g. every field of every
+// construct is restored. Any package with a failure isn't cached so is
+// abstracted again.
+func Test_Cached_AllFixtures(t *testing.T) {
+	entries, err := os.ReadDir(pathToTestData)
+	check.NoError(t).Require(err)
+	for _, entry := range entries {
+		dir := entry.Name()
+		t.Run(dir, func(t *testing.T) {
+			t.Parallel()
+			patterns, cacheDir := fixturePatterns(t, dir), t.TempDir()
+			filled := newTest(t, dir).withTests().skipBroken().tolerate().cached(cacheDir).abstract(patterns...)
+			newTest(t, dir).withTests().skipBroken().tolerate().cached(cacheDir).abstract(patterns...).same(filled).cacheHit()
+		})
+	}
+}
+
+// fixturePatterns gets the patterns to read a fixture with. A fixture with
+// a module is read as a whole, a workspace is read as a whole without
+// patterns, and otherwise each file is read.
+func fixturePatterns(t *testing.T, dir string) []string {
+	path := pathToTestData + dir
+	if _, err := os.Stat(path + `/go.work`); err == nil {
+		return nil
+	}
+	if _, err := os.Stat(path + `/go.mod`); err == nil {
+		return []string{`./...`}
+	}
+	files, err := filepath.Glob(path + `/*.go`)
+	check.NoError(t).Require(err)
+	patterns := []string{}
+	for _, file := range files {
+		if !strings.HasSuffix(file, `_test.go`) {
+			patterns = append(patterns, filepath.Base(file))
+		}
+	}
+	return patterns
+}
@@ -4,17 +4,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/Snow-Gremlin/goToolbox/differs/diff"
 	"github.com/Snow-Gremlin/goToolbox/testers/check"
+	"golang.org/x/tools/go/packages"
 	"gopkg.in/yaml.v3"
 
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/abstractor/resolver/dce"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/cache"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/constructs/kind"
+	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/diagnostics"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/jsonify"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/logger"
 	"github.com/MSUSEL/msusel-tdmetrics-go/goAbstractor/internal/reader"
@@ -25,55 +32,201 @@ const (
 	expAbstraction = `/abstraction.yaml`
 	expPartials    = `/partial.yaml`
 	writeOutFile   = `/out.json`
+
+	// follow is the step in the path of a partial test that continues
+	// the path from the construct referenced at the current step,
+	// e.g. `method12` or the index after the `package` key.
+	follow = `->`
+)
+
+var (
+	keyPattern       = regexp.MustCompile(`^[a-zA-Z]+$`)
+	referencePattern = regexp.MustCompile(`^([a-zA-Z]+)(\d+)$`)
 )
 
 func newTest(t *testing.T, dir string) *testTool {
 	return &testTool{
 		t:       t,
+		root:    pathToTestData,
 		dir:     dir,
 		verbose: true, // testing.Verbose()
 	}
 }
 
 type testTool struct {
-	t       *testing.T
-	dir     string
-	verbose bool
-	proj    constructs.Project
+	t           *testing.T
+	root        string
+	dir         string
+	verbose     bool
+	workers     int
+	cacheDir    string
+	cache       *cache.Cache
+	tolerant    bool
+	tests       bool
+	skipsBroken bool
+	builds      []string
+	modules     []string
+	roots       dce.Roots
+	reflection  bool
+	ctx         *jsonify.Context
+	expFile     string
+	proj        constructs.Project
+}
+
+// in sets the directory containing the test data directory,
+// e.g. a copy of the test data that the test may modify.
+func (tt *testTool) in(root string) *testTool {
+	tt.root = root
+	return tt
+}
+
+func (tt *testTool) parallel(workers int) *testTool {
+	tt.workers = workers
+	return tt
+}
+
+func (tt *testTool) cached(dir string) *testTool {
+	tt.cacheDir = dir
+	return tt
+}
+
+func (tt *testTool) tolerate() *testTool {
+	tt.tolerant = true
+	return tt
+}
+
+// withTests reads the test files and external test packages.
+func (tt *testTool) withTests() *testTool {
+	tt.tests = true
+	return tt
+}
+
+// skipBrokenPackages skips the packages with errors, and the packages
+// depending on them, instead of failing to read the project.
+func (tt *testTool) skipBroken() *testTool {
+	tt.skipsBroken = true
+	return tt
+}
+
+// build reads and abstracts the packages for each of the given
+// build configurations, e.g. `linux/amd64`, instead of the default build.
+func (tt *testTool) build(specs ...string) *testTool {
+	tt.builds = append(tt.builds, specs...)
+	return tt
+}
+
+// module reads the given modules, relative to the test data directory,
+// along with the packages matching the patterns.
+func (tt *testTool) module(dirs ...string) *testTool {
+	tt.modules = append(tt.modules, dirs...)
+	return tt
 }
 
+// rooted adds the given roots to the dead-code elimination and,
+// if reflection is true, the declarations used via reflection.
+func (tt *testTool) rooted(roots dce.Roots, reflection bool) *testTool {
+	tt.roots = roots
+	tt.reflection = reflection
+	return tt
+}
+
+// output sets the context to write the abstraction with when checking it,
+// e.g. to include the alive reasons, instead of the default context.
+func (tt *testTool) output(ctx *jsonify.Context) *testTool {
+	tt.ctx = ctx
+	return tt
+}
+
+// expect sets the name of the file in the test data directory with the
+// expected abstraction or partials, instead of the default file,
+// e.g. when the test data is checked with more than one configuration.
+func (tt *testTool) expect(file string) *testTool {
+	tt.expFile = `/` + file
+	return tt
+}
+
+// abstract reads and abstracts the packages matching the given patterns.
+// If no patterns are given, the `main.go` file is read,
+// unless the test data is a workspace which is read as a whole.
 func (tt *testTool) abstract(patterns ...string) *testTool {
 	tt.t.Helper()
-	if len(patterns) <= 0 {
+	if len(patterns) <= 0 && !tt.hasFile(`go.work`) {
 		patterns = []string{`main.go`}
 	}
 
-	ps, err := reader.Read(&reader.Config{
+	rc := &reader.Config{
 		Verbose:    tt.verbose,
-		Dir:        pathToTestData + tt.dir,
+		Dir:        tt.root + tt.dir,
 		Patterns:   patterns,
 		BuildFlags: []string{`-tags=test`},
-	})
+		Tests:      tt.tests,
+		SkipBroken: tt.skipsBroken,
+	}
+	for _, dir := range tt.modules {
+		rc.Modules = append(rc.Modules, tt.root+tt.dir+`/`+dir)
+	}
+	for _, spec := range tt.builds {
+		bc, err := reader.ParseBuildConfig(spec)
+		check.NoError(tt.t).
+			Name(`Parse build`).
+			With(`Dir`, tt.dir).
+			With(`Build`, spec).
+			Require(err)
+		rc.BuildConfigs = append(rc.BuildConfigs, bc)
+	}
+
+	var (
+		ps      []*packages.Package
+		builds  map[string][]*packages.Package
+		skipped []diagnostics.Diagnostic
+		err     error
+	)
+	if len(rc.BuildConfigs) > 0 {
+		builds, skipped, err = reader.ReadBuilds(rc)
+	} else {
+		ps, skipped, err = reader.Read(rc)
+	}
 	check.NoError(tt.t).
 		Name(`Read project`).
 		With(`Dir`, tt.dir).
 		Require(err)
 
+	if len(tt.cacheDir) > 0 {
+		tt.cache, err = cache.Open(tt.cacheDir, rc, ps, builds)
+		check.NoError(tt.t).
+			Name(`Open cache`).
+			With(`Dir`, tt.dir).
+			Require(err)
+	}
+
 	var log *logger.Logger
 	if tt.verbose {
 		log = configLogger(logger.New())
 	}
 
 	tt.proj = abstractor.Abstract(abstractor.Config{
-		Packages: ps,
-		Log:      log,
+		Packages:        ps,
+		Builds:          builds,
+		Log:             log,
+		Workers:         tt.workers,
+		Cache:           tt.cache,
+		Tolerant:        tt.tolerant,
+		Diagnostics:     skipped,
+		Roots:           tt.roots,
+		ReflectionRoots: tt.reflection,
 	})
 	return tt
 }
 
+// hasFile determines if the test data directory has the given file.
+func (tt *testTool) hasFile(name string) bool {
+	_, err := os.Stat(tt.root + tt.dir + `/` + name)
+	return err == nil
+}
+
 func (tt *testTool) readExp(expData any, file string) *testTool {
 	tt.t.Helper()
-	expFile, err := os.ReadFile(pathToTestData + tt.dir + file)
+	expFile, err := os.ReadFile(tt.root + tt.dir + file)
 	check.NoError(tt.t).
 		Name(`Read expected json`).
 		With(`Dir`, tt.dir).
@@ -92,7 +245,7 @@ func (tt *testTool) readExp(expData any, file string) *testTool {
 func (tt *testTool) full() *testTool {
 	tt.t.Helper()
 	var expData any
-	tt.readExp(&expData, expAbstraction)
+	tt.readExp(&expData, tt.expected(expAbstraction))
 
 	exp, err := json.MarshalIndent(expData, ``, `  `)
 	check.NoError(tt.t).
@@ -100,8 +253,7 @@ func (tt *testTool) full() *testTool {
 		With(`Dir`, tt.dir).
 		Require(err)
 
-	ctx := jsonify.NewContext()
-	gotten, err := jsonify.Marshal(ctx, tt.proj)
+	gotten, err := jsonify.Marshal(tt.context(), tt.proj)
 	check.NoError(tt.t).
 		Name(`Marshal project`).
 		With(`Dir`, tt.dir).
@@ -116,6 +268,23 @@ func (tt *testTool) full() *testTool {
 	return tt
 }
 
+// expected gets the file with the expected abstraction or partials,
+// the given default file unless another file was set with `expect`.
+func (tt *testTool) expected(file string) string {
+	if len(tt.expFile) > 0 {
+		return tt.expFile
+	}
+	return file
+}
+
+// context gets the context to write the abstraction with.
+func (tt *testTool) context() *jsonify.Context {
+	if tt.ctx != nil {
+		return tt.ctx
+	}
+	return jsonify.NewContext()
+}
+
 var _ = (*testTool).dump // ignore dump being unused.
 
 func (tt *testTool) dump() *testTool {
@@ -136,7 +305,7 @@ type partialTest struct {
 func (tt *testTool) partial() *testTool {
 	tt.t.Helper()
 	var partialTests []partialTest
-	tt.readExp(&partialTests, expPartials)
+	tt.readExp(&partialTests, tt.expected(expPartials))
 
 	for _, pt := range partialTests {
 		tt.runPartialTest(pt)
@@ -154,8 +323,9 @@ func (tt *testTool) runPartialTest(pt partialTest) {
 			t.Skip(`The Go version changes the specific indices, this test is for ` + pt.GoVersion + `.`)
 		}
 
-		ctx := jsonify.NewContext().IncludeDebugIndex(true)
-		subData := tt.proj.ToJson(ctx).Seek(pt.Path)
+		ctx := tt.context().IncludeDebugIndex(true)
+		root := tt.proj.ToJson(ctx)
+		subData := seek(root, root, pt.Path)
 
 		exp, err := json.MarshalIndent(pt.Data, ``, `  `)
 		check.NoError(t).
@@ -180,6 +350,64 @@ func (tt *testTool) runPartialTest(pt partialTest) {
 	})
 }
 
+// seek gets the data at the given path of a partial test.
+// The path may have follow steps to continue from a referenced construct,
+// so that the constructs, e.g. those of the standard library, can be
+// reached without the indices that change with the Go version.
+// If the references are in a list or map, e.g. from a range,
+// the rest of the path is continued from each of them.
+func seek(root, data jsonify.Datum, path []any) jsonify.Datum {
+	index := slices.Index(path, any(follow))
+	if index < 0 {
+		return data.Seek(path)
+	}
+
+	refs, rest := data.Seek(path[:index]), path[index+1:]
+	hint := referenceHint(path[:index])
+	switch refs := refs.(type) {
+	case *jsonify.List:
+		result := jsonify.NewList()
+		for _, ref := range refs.RawValue().([]jsonify.Datum) {
+			result.Append(nil, seek(root, referenced(root, ref, hint), rest))
+		}
+		return result
+	case *jsonify.Map:
+		result := jsonify.NewMap()
+		for key, ref := range refs.RawValue().(map[string]jsonify.Datum) {
+			result.Add(nil, key, seek(root, referenced(root, ref, hint), rest))
+		}
+		return result
+	default:
+		return seek(root, referenced(root, refs, hint), rest)
+	}
+}
+
+// referenceHint gets the kind of the constructs referenced by an index
+// from the last key in the given path, e.g. `package` or `methods`.
+func referenceHint(path []any) kind.Kind {
+	for _, step := range slices.Backward(path) {
+		if s, ok := step.(string); ok && keyPattern.MatchString(s) {
+			return kind.Kind(s)
+		}
+	}
+	return ``
+}
+
+// referenced gets the construct referenced by the given reference,
+// either a kind and index, e.g. `method12`, or an index of the hinted kind.
+func referenced(root, ref jsonify.Datum, hint kind.Kind) jsonify.Datum {
+	switch v := ref.RawValue().(type) {
+	case string:
+		if m := referencePattern.FindStringSubmatch(v); m != nil {
+			index, _ := strconv.Atoi(m[2])
+			return root.Seek([]any{kind.Kind(m[1]).Plural(), index - 1})
+		}
+	case int:
+		return root.Seek([]any{hint.Plural(), v - 1})
+	}
+	panic(fmt.Errorf(`not a reference: %v`, ref.RawValue()))
+}
+
 func (tt *testTool) save() *testTool {
 	tt.t.Helper()
 	ctx := jsonify.NewContext().IncludeDebugIndex(true)
@@ -189,10 +417,65 @@ func (tt *testTool) save() *testTool {
 		With(`Dir`, tt.dir).
 		Require(err)
 
-	err = os.WriteFile(pathToTestData+tt.dir+writeOutFile, gotten, 0o644)
+	err = os.WriteFile(tt.root+tt.dir+writeOutFile, gotten, 0o644)
 	check.NoError(tt.t).
 		Name(`Save project`).
 		With(`Dir`, tt.dir).
 		Require(err)
 	return tt
 }
+
+// same checks that the abstraction is identical to the other abstraction.
+func (tt *testTool) same(other *testTool) *testTool {
+	tt.t.Helper()
+	ctx := jsonify.NewContext()
+	exp, err := jsonify.Marshal(ctx, other.proj)
+	check.NoError(tt.t).
+		Name(`Marshal other project`).
+		With(`Dir`, tt.dir).
+		Require(err)
+
+	gotten, err := jsonify.Marshal(ctx, tt.proj)
+	check.NoError(tt.t).
+		Name(`Marshal project`).
+		With(`Dir`, tt.dir).
+		Require(err)
+
+	if !slices.Equal(exp, gotten) {
+		expLines := strings.Split(string(exp), "\n")
+		gotLines := strings.Split(string(gotten), "\n")
+		diffLines := diff.Default().PlusMinus(expLines, gotLines)
+		tt.t.Error("\n" + strings.Join(diffLines, "\n"))
+	}
+	return tt
+}
+
+// cacheHit checks that packages were restored from the cache.
+func (tt *testTool) cacheHit() *testTool {
+	tt.t.Helper()
+	check.NotZero(tt.t).Name(`Cache hits`).With(`Dir`, tt.dir).Assert(tt.cache.Hits())
+	return tt
+}
+
+// cacheUsed checks the number of packages that were
+// restored from the cache and that were abstracted.
+func (tt *testTool) cacheUsed(hits, misses int) *testTool {
+	tt.t.Helper()
+	check.Equal(tt.t, tt.cache.Hits()).Name(`Cache hits`).With(`Dir`, tt.dir).Assert(hits)
+	check.Equal(tt.t, tt.cache.Misses()).Name(`Cache misses`).With(`Dir`, tt.dir).Assert(misses)
+	return tt
+}
+
+// copyTestData copies the test data directory into a temporary
+// directory so that the test may modify it. The directory containing
+// the copy is returned to be used with `in`.
+func copyTestData(t *testing.T, dir string) string {
+	t.Helper()
+	root := t.TempDir() + `/`
+	err := os.CopyFS(root+dir, os.DirFS(pathToTestData+dir))
+	check.NoError(t).
+		Name(`Copy test data`).
+		With(`Dir`, dir).
+		Require(err)
+	return root
+}
@@ -0,0 +1,78 @@
+//go:build test
+
+package main
+
+// Visitor and the Handler function type depend on each other
+// through the field of Visitor and the parameter of Handler.
+type Visitor struct {
+	handle Handler
+	depth  int
+}
+
+type Handler func(v *Visitor) bool
+
+// Node and Tree depend on each other through the result of
+// the abstract method in Node and the field of Tree.
+type Node interface {
+	Children() []Tree
+}
+
+type Tree struct {
+	root Node
+}
+
+// walk depends on Visitor through its signature but
+// nothing depends on walk so it isn't in a cycle.
+func walk(v *Visitor) bool {
+	return v.handle(v)
+}
+
+// Parity's methods depend on each other through
+// the selections of the methods on Parity.
+type Parity struct {
+	n int
+}
+
+func (p Parity) IsEven() bool {
+	if p.n == 0 {
+		return true
+	}
+	return Parity{n: p.n - 1}.IsOdd()
+}
+
+func (p Parity) IsOdd() bool {
+	if p.n == 0 {
+		return false
+	}
+	return Parity{n: p.n - 1}.IsEven()
+}
+
+// tick and tock depend on each other through their invocations.
+func tick(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return tock(n - 1)
+}
+
+func tock(n int) int {
+	return tick(n) + 1
+}
+
+// ping and pong depend on each other but are never used.
+func ping(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return pong(n - 1)
+}
+
+func pong(n int) int {
+	return ping(n)
+}
+
+func main() {
+	v := &Visitor{handle: func(v *Visitor) bool { return v.depth > 0 }}
+	var t Tree
+	println(walk(v), Parity{n: 4}.IsEven(), tick(3), t.root == nil)
+}
@@ -0,0 +1,116 @@
+//go:build test
+
+package main
+
+// Account is a data class with only getters and setters.
+type Account struct {
+	owner   string
+	balance int
+	limit   int
+}
+
+func (a *Account) Owner() string      { return a.owner }
+func (a *Account) Balance() int       { return a.balance }
+func (a *Account) SetBalance(b int)   { a.balance = b }
+func (a *Account) SetLimit(limit int) { a.limit = limit }
+
+// Bank is a god object that is complex, reaches into the fields of
+// the accounts, and whose methods don't share any of its own fields.
+type Bank struct {
+	name    string
+	audits  int
+	clients []*Account
+}
+
+func (b *Bank) Transfer(from, to *Account, amount int) bool {
+	logf(`transfer`)
+	if amount <= 0 {
+		return false
+	}
+	if from.balance-amount < -from.limit {
+		return false
+	}
+	if from.owner == to.owner {
+		return false
+	}
+	from.balance -= amount
+	to.balance += amount
+	b.audits++
+	return true
+}
+
+func (b *Bank) Rename(name string) {
+	if len(name) == 0 {
+		return
+	}
+	for _, c := range name {
+		if c == ' ' {
+			return
+		}
+	}
+	b.name = name
+}
+
+func (b *Bank) Open(a *Account) {
+	if a == nil {
+		return
+	}
+	b.clients = append(b.clients, a)
+}
+
+// Statement has feature envy since it only accesses the fields of an account.
+type Statement struct {
+	lines []string
+}
+
+func (s *Statement) Describe(a *Account) string {
+	logf(`describe`)
+	if a.balance > a.limit {
+		return a.owner + ` is over the limit`
+	}
+	return a.owner
+}
+
+// Teller is the third object invoking logf.
+type Teller struct{}
+
+func (t Teller) Greet() { logf(`hello`) }
+
+// logf is invoked from many objects so changing it is a shotgun surgery.
+func logf(msg string) {
+	println(msg)
+}
+
+// report is a long method with a long parameter list.
+func report(name string, count, total, limit int) {
+	println(`report for`, name)
+	println(`count`, count)
+	println(`total`, total)
+	println(`limit`, limit)
+	if total > limit {
+		println(`over the limit`)
+	}
+	if count == 0 {
+		println(`no entries`)
+	}
+	println(`average`, total/max(count, 1))
+	println(`remaining`, limit-total)
+	println(`ratio`, total*100/max(limit, 1))
+	println(`twice`, total*2)
+	println(`half`, total/2)
+	println(`done`)
+}
+
+func main() {
+	a := &Account{owner: `ann`, balance: 10}
+	b := &Account{owner: `bob`}
+	bank := &Bank{}
+	bank.Open(a)
+	bank.Open(b)
+	bank.Rename(`first`)
+	println(bank.Transfer(a, b, 5), (&Statement{}).Describe(a))
+	Teller{}.Greet()
+	report(a.Owner(), 2, a.Balance(), 20)
+	a.SetBalance(3)
+	a.SetLimit(4)
+}
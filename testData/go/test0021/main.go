@@ -0,0 +1,17 @@
+//go:build test
+
+package main
+
+import "test0021/shapes"
+
+// A test for qualified references to the functions, variables, and
+// constants of another package. The generic function is called both
+// with the instance types inferred from the arguments and given explicitly.
+
+func main() {
+	shapes.Count++
+	area := shapes.Area(shapes.Sides, 2)
+	implicit := shapes.Max(area, 3)
+	explicit := shapes.Max[float64](1.5, 2)
+	println(area, implicit, explicit)
+}
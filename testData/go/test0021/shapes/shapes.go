@@ -0,0 +1,18 @@
+//go:build test
+
+package shapes
+
+const Sides = 4
+
+var Count int
+
+func Area(width, height int) int {
+	return width * height
+}
+
+func Max[T int | float64](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
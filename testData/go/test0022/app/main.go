@@ -0,0 +1,12 @@
+//go:build test
+
+package main
+
+import "example.com/lib"
+
+// A test for reading a go.work workspace. The lib module is used
+// by this module and by the extra module that isn't in the workspace.
+
+func main() {
+	println(lib.Greet(`gopher`))
+}
@@ -0,0 +1,3 @@
+module example.com/extra
+
+go 1.23.1
@@ -0,0 +1,9 @@
+//go:build test
+
+package extra
+
+import "example.com/lib"
+
+func Shout() string {
+	return lib.Greet(`everyone`) + `!`
+}
@@ -0,0 +1,7 @@
+//go:build test
+
+package lib
+
+func Greet(name string) string {
+	return `hello ` + name
+}
@@ -0,0 +1,3 @@
+module test0023
+
+go 1.23.1
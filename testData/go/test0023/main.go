@@ -0,0 +1,10 @@
+//go:build test
+
+package main
+
+// A test for abstracting more than one build configuration where a
+// function has a different implementation in each build configuration.
+
+func main() {
+	println(open(`cats.txt`))
+}
@@ -0,0 +1,35 @@
+//go:build test
+
+package main
+
+import u "unsafe"
+
+// A test for tolerating a failure to abstract a function. The unsafe
+// builtins called via an aliased import can't be abstracted so the
+// function using them fails while the rest of the declarations,
+// including the blank functions, are still abstracted.
+
+type Cat struct {
+	Name string
+}
+
+func (c Cat) Meow() string {
+	return c.Name + ` says meow`
+}
+
+func size() uintptr {
+	type pair struct{ a, b int }
+	return u.Sizeof(pair{})
+}
+
+func _() {
+	println(`first`)
+}
+
+func _() {
+	println(`second`)
+}
+
+func main() {
+	println(Cat{Name: `Tom`}.Meow(), size())
+}
@@ -0,0 +1,7 @@
+//go:build test
+
+package broken
+
+func Bad() int {
+	return `not an int`
+}
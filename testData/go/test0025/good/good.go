@@ -0,0 +1,7 @@
+//go:build test
+
+package good
+
+func Hello() string {
+	return `hello`
+}
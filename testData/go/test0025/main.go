@@ -0,0 +1,12 @@
+//go:build test
+
+package main
+
+import "test0025/good"
+
+// A test for skipping the packages with errors, and the packages that
+// depend on them, while still abstracting the rest of the packages.
+
+func main() {
+	println(good.Hello())
+}
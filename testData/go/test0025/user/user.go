@@ -0,0 +1,9 @@
+//go:build test
+
+package user
+
+import "test0025/broken"
+
+func Use() int {
+	return broken.Bad() + 1
+}
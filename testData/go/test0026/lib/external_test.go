@@ -0,0 +1,9 @@
+//go:build test
+
+package lib_test
+
+import "test0026/lib"
+
+func ExampleAdd_external() {
+	println(lib.Add(1, 2))
+}
@@ -0,0 +1,7 @@
+//go:build test
+
+package lib
+
+func Add(a, b int) int {
+	return a + b
+}
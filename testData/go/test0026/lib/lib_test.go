@@ -0,0 +1,11 @@
+//go:build test
+
+package lib
+
+func double(a int) int {
+	return Add(a, a)
+}
+
+func ExampleAdd() {
+	println(double(2))
+}
@@ -0,0 +1,13 @@
+//go:build test
+
+package main
+
+import "test0026/lib"
+
+// A test for reading the test files and external test packages. Only the
+// variant of the package with its test files is abstracted and the
+// declarations from the test files are tagged as test code.
+
+func main() {
+	println(lib.Add(1, 2))
+}
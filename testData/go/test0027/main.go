@@ -0,0 +1,17 @@
+//go:build test
+
+package main
+
+// A test for tagging the code in generated files and leaving the
+// measurements of the generated code out while keeping its usages.
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+)
+
+func main() {
+	println(Red.String(), Green.String())
+}
@@ -0,0 +1,9 @@
+//go:build test
+
+package api
+
+func Used() {}
+
+func Extra() {}
+
+func internal() {}
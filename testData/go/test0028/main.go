@@ -0,0 +1,37 @@
+//go:build test
+
+package main
+
+import (
+	"encoding/gob"
+	_ "unsafe"
+
+	"test0028/api"
+)
+
+// A test for the roots of the dead-code elimination. Declarations are
+// kept alive by being used, exported from a listed package, listed as a
+// symbol, named in a directive, or registered for reflection.
+
+type Message struct {
+	Text string
+}
+
+type Unused struct {
+	Text string
+}
+
+//go:linkname hidden
+func hidden() {}
+
+//export exported
+func exported() {}
+
+func listed() {}
+
+func dead() {}
+
+func main() {
+	gob.Register(Message{})
+	api.Used()
+}
@@ -0,0 +1,30 @@
+//go:build test
+
+package lib
+
+import "syscall"
+
+type Ordered interface {
+	~int | ~string
+}
+
+func Less[T Ordered](a, b T) bool {
+	return a < b
+}
+
+type Kind int
+
+const (
+	Number Kind = iota
+	String
+)
+
+func (k Kind) String() string {
+	return names[k]
+}
+
+var names = []string{Number: `number`, String: `string`}
+
+func Pid() int {
+	return syscall.Getpid()
+}
@@ -0,0 +1,25 @@
+//go:build test
+
+package main
+
+import (
+	"syscall"
+
+	"test0029/lib"
+)
+
+// A test for abstracting the packages concurrently. The main package refers
+// to the declarations in lib before lib is abstracted, which must not change
+// how lib's own declarations refer to each other, and lib is abstracted after
+// syscall, which must not change how lib refers to syscall.
+
+func Max[T lib.Ordered](a, b T) T {
+	if lib.Less(a, b) {
+		return b
+	}
+	return a
+}
+
+func main() {
+	println(Max(1, 2), lib.String.String(), lib.Pid() == syscall.Getpid())
+}
@@ -0,0 +1,14 @@
+//go:build test
+
+package main
+
+import "test0030/shapes"
+
+// A test for leaving the test code and generated code out of the reports,
+// e.g. the CK metrics, smells, package metrics, and participation, unless
+// the test code is requested or the generated code isn't excluded.
+
+func main() {
+	s := shapes.NewSquare(2)
+	println(s.Area(), s.String())
+}
@@ -0,0 +1,9 @@
+//go:build test
+
+package shapes_test
+
+import "test0030/shapes"
+
+func ExampleNewSquare() {
+	println(shapes.NewSquare(1).Area())
+}
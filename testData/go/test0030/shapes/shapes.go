@@ -0,0 +1,15 @@
+//go:build test
+
+package shapes
+
+type Square struct {
+	side int
+}
+
+func NewSquare(side int) *Square {
+	return &Square{side: side}
+}
+
+func (s *Square) Area() int {
+	return s.side * s.side
+}
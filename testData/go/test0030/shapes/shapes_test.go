@@ -0,0 +1,16 @@
+//go:build test
+
+package shapes
+
+type fakeSquare struct {
+	square *Square
+}
+
+func (f *fakeSquare) Area() int {
+	return f.square.Area()
+}
+
+func ExampleSquare() {
+	f := &fakeSquare{square: NewSquare(3)}
+	println(f.Area())
+}
@@ -0,0 +1,39 @@
+//go:build test
+
+package main
+
+// A test for the dead code report, e.g. the dead functions, values,
+// and objects, and the fields of alive objects that are never used.
+
+type point struct {
+	x, y int
+	z    int
+}
+
+type labeled struct {
+	point
+	label string
+}
+
+func sum(p point) int {
+	return p.x + p.y
+}
+
+type unused struct {
+	name  string
+	count int
+}
+
+func deadFunc(u unused) string {
+	if u.count > 0 {
+		return u.name
+	}
+	return ""
+}
+
+var deadValue = 42
+
+func main() {
+	l := labeled{point: point{x: 1, y: 2}}
+	println(sum(l.point), l.x)
+}
@@ -0,0 +1,9 @@
+//go:build test
+
+package main
+
+type Aardvark struct{ age int }
+
+func (a Aardvark) Age() int { return a.age }
+
+func init() { println(Aardvark{}.Age()) }
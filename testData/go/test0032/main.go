@@ -0,0 +1,15 @@
+//go:build test
+
+package main
+
+// A test for the stable identifiers. Adding the declarations in aardvark.go,
+// including another init function, changes the indices of the existing
+// declarations but not their identifiers.
+
+type Cat struct{ name string }
+
+func (c Cat) Name() string { return c.name }
+
+func init() { println(`cat`) }
+
+func main() { println(Cat{name: `tom`}.Name(), 3) }
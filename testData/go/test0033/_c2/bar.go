@@ -0,0 +1,12 @@
+//go:build test
+
+package a
+
+func Bar(x int) int {
+	if x > 0 {
+		return x
+	}
+	return 0
+}
+
+func init() { Bar(1) }
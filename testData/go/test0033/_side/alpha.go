@@ -0,0 +1,7 @@
+//go:build test
+
+package a
+
+func Baz() {}
+
+func init() { Baz() }
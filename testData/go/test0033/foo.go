@@ -0,0 +1,10 @@
+//go:build test
+
+package a
+
+// A test for mining the history of a repository. This is the first commit
+// and the directories starting with an underscore hold the files written by
+// the later commits, the second and third on the main branch and the one on
+// a side branch which is merged after them.
+
+func Foo() int { return 1 }
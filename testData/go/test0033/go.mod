@@ -0,0 +1,3 @@
+module test0033
+
+go 1.23.1
@@ -0,0 +1,11 @@
+//go:build test
+
+package a
+
+import "test0034/b"
+
+// A test for mining the history of a repository with more than one package.
+// Only the root package is read so the package it imports is only collected
+// when all the packages are.
+
+func Foo() int { return b.Bar() }
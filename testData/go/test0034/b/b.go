@@ -0,0 +1,5 @@
+//go:build test
+
+package b
+
+func Bar() int { return 1 }
@@ -0,0 +1,3 @@
+module test0034
+
+go 1.23.1